@@ -5,8 +5,21 @@ import (
 	"altt/internal/logger"
 	"altt/internal/routes"
 	"altt/internal/service/rpc"
+	"altt/internal/service/web3/allowance"
 	"altt/internal/service/web3/approver"
 	"altt/internal/service/web3/balancer"
+	"altt/internal/service/web3/ens"
+	"altt/internal/service/web3/follower"
+	"altt/internal/service/web3/gas"
+	"altt/internal/service/web3/heads"
+	"altt/internal/service/web3/indexer"
+	"altt/internal/service/web3/pricing"
+	"altt/internal/service/web3/streamer"
+	"altt/internal/service/web3/transactions"
+	"altt/internal/service/web3/transfers"
+	"altt/internal/service/web3/verifier"
+	"altt/internal/service/web3/watchlist"
+	"altt/internal/storage"
 	"flag"
 	"fmt"
 	"log"
@@ -36,10 +49,66 @@ func main() {
 
 	appLog.Info("init services")
 	rpc.NewService(appConf.ChainRPCs)
-	serviceBalancer := balancer.NewService(appLog, approver.InitService(appLog), appConf.DisableMetrics)
+	store, err := storage.Open(appConf.Storage.Path)
+	if err != nil {
+		appLog.Fatal("unable to open storage", err, zap.String("path", appConf.Storage.Path))
+	}
+	repository := storage.NewRepository(store)
+	defer func() {
+		if err = repository.Close(); err != nil {
+			appLog.Error("unable to close storage", err)
+		}
+	}()
+	if appConf.Watchlist.File != "" {
+		imported, errImport := repository.ImportWatches(watchlist.NewFileRepository(appConf.Watchlist.File))
+		if errImport != nil {
+			appLog.Fatal("unable to import watches", errImport, zap.String("file", appConf.Watchlist.File))
+		}
+		if imported > 0 {
+			appLog.Info("watches imported into storage", zap.Int("count", imported))
+		}
+	}
+	serviceGas, err := gas.NewService(appLog, appConf.Gas)
+	if err != nil {
+		appLog.Fatal("unable to init gas", err)
+	}
+	serviceApprover := approver.InitService(appLog, serviceGas)
+	servicePricing, err := pricing.NewService(appLog, appConf.Pricing)
+	if err != nil {
+		appLog.Fatal("unable to init pricing", err)
+	}
+	serviceHeads := heads.NewService(appLog, appConf.HeadPollInterval)
+	serviceVerifier := verifier.NewService(appLog, appConf.Verifier)
+	serviceFollower := follower.NewService(appLog, serviceHeads, appConf.FollowerWindow)
+	serviceIndexer := indexer.NewService(appLog, serviceApprover, serviceFollower, repository, appConf.Indexer)
+	serviceBalancer := balancer.NewService(appLog, serviceApprover, servicePricing, serviceVerifier, serviceIndexer, repository, appConf.DisableMetrics, appConf.Balancer)
+	serviceStreamer := streamer.NewService(appLog, serviceApprover, serviceHeads, appConf.Stream.MaxSubscriptions)
+	serviceTransfers := transfers.NewService(appLog, serviceApprover, appConf.Transfers)
+	serviceAllowance, err := allowance.NewService(appLog, serviceApprover, serviceBalancer, appConf.Allowance)
+	if err != nil {
+		appLog.Fatal("unable to init allowance", err)
+	}
+	serviceTransactions := transactions.NewService(appLog)
+	serviceENS, err := ens.NewService(appLog, appConf.ENS)
+	if err != nil {
+		appLog.Fatal("unable to init ens", err)
+	}
+	serviceWatchlist := watchlist.NewService(appLog, serviceApprover, serviceHeads, repository, watchlist.Config{
+		MaxAttempts:    appConf.Watchlist.MaxAttempts,
+		InitialBackoff: appConf.Watchlist.InitialBackoff,
+		MaxBackoff:     appConf.Watchlist.MaxBackoff,
+	})
+	if err = serviceWatchlist.Start(); err != nil {
+		appLog.Fatal("unable to start watchlist", err)
+	}
+	defer serviceWatchlist.Stop()
+	serviceIndexer.Start()
+	defer serviceIndexer.Stop()
+	serviceBalancer.Start()
+	defer serviceBalancer.Stop()
 
 	appLog.Info("init http service")
-	appHTTPServer := routes.InitAppRouter(appLog, serviceBalancer, fmt.Sprintf(":%d", appConf.AppPort), appConf.DisableMetrics)
+	appHTTPServer := routes.InitAppRouter(appLog, serviceBalancer, serviceStreamer, serviceWatchlist, serviceTransfers, serviceAllowance, serviceENS, serviceGas, serviceTransactions, fmt.Sprintf(":%d", appConf.AppPort), appConf.DisableMetrics, appConf.Addresses.Lenient)
 	defer func() {
 		if err = appHTTPServer.Stop(); err != nil {
 			appLog.Fatal("unable to stop http service", err)
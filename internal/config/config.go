@@ -1,18 +1,166 @@
 package config
 
 import (
+	"altt/internal/utils"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"gopkg.in/yaml.v2"
 )
 
 type AppConfig struct {
-	AppPort        int                 `yaml:"app_port"`
-	DisableMetrics bool                `yaml:"disable_metrics"`
-	ChainRPCs      map[string][]string `yaml:"rpc_urls"`
+	AppPort          int                 `yaml:"app_port"`
+	DisableMetrics   bool                `yaml:"disable_metrics"`
+	ChainRPCs        map[string][]string `yaml:"rpc_urls"`
+	Balancer         BalancerConfig      `yaml:"balancer"`
+	HeadPollInterval time.Duration       `yaml:"head_poll_interval"`
+	FollowerWindow   int                 `yaml:"follower_window"`
+	Stream           StreamConfig        `yaml:"stream"`
+	Watchlist        WatchlistConfig     `yaml:"watchlist"`
+	Pricing          PricingConfig       `yaml:"pricing"`
+	Verifier         VerifierConfig      `yaml:"verifier"`
+	Indexer          IndexerConfig       `yaml:"indexer"`
+	Transfers        TransfersConfig     `yaml:"transfers"`
+	Storage          StorageConfig       `yaml:"storage"`
+	Allowance        AllowanceConfig     `yaml:"allowance"`
+	ENS              ENSConfig           `yaml:"ens"`
+	Addresses        AddressesConfig     `yaml:"addresses"`
+	Gas              GasConfig           `yaml:"gas"`
+}
+
+type AddressesConfig struct {
+	// Lenient accepts all lowercase and all uppercase addresses without EIP-55 checksum in requests and config
+	Lenient bool `yaml:"lenient"`
+}
+
+type BalancerConfig struct {
+	// CallTimeout is deadline of single upstream rpc call shared by coalesced requests
+	CallTimeout time.Duration `yaml:"call_timeout"`
+	// ResultTTL is how long result of completed call is reused by new requests, zero disables reuse
+	ResultTTL time.Duration `yaml:"result_ttl"`
+	// SnapshotFlushInterval is how often balances read from rpc are saved to storage together
+	SnapshotFlushInterval time.Duration `yaml:"snapshot_flush_interval"`
+}
+
+type WatchlistConfig struct {
+	// File is path of legacy json file with watch definitions, it is imported into storage when storage has no watches
+	File string `yaml:"file"`
+	// MaxAttempts is number of webhook delivery attempts before event goes to dead-letter list
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+}
+
+type PricingConfig struct {
+	// MaxPriceAge is heartbeat of oracle feeds without known heartbeat. Round older than heartbeat of its feed
+	// and HeartbeatGrace is rejected as stale
+	MaxPriceAge time.Duration `yaml:"max_price_age"`
+	// FeedHeartbeats are heartbeats by aggregator address, they override known heartbeats of entities.PriceFeedHeartbeats
+	FeedHeartbeats map[string]time.Duration `yaml:"feed_heartbeats"`
+	// HeartbeatGrace is added to heartbeat to cover delay of round update transaction
+	HeartbeatGrace time.Duration `yaml:"heartbeat_grace"`
+	// CacheTTL is how long fetched price is reused
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// TWAPWindow is averaging window of uniswap v3 pools, zero uses spot price
+	TWAPWindow time.Duration `yaml:"twap_window"`
+	// MinLiquidityUSD is liquidity floor, pools with less liquidity are not used for pricing
+	MinLiquidityUSD float64 `yaml:"min_liquidity_usd"`
+	// DEXPools are used for tokens without oracle feed
+	DEXPools []DEXPoolConfig `yaml:"dex_pools"`
+}
+
+type DEXPoolConfig struct {
+	Chain string `yaml:"chain"`
+	Token string `yaml:"token"`
+	// Quote is token paired with Token in pool, its USD price is taken from oracle or from other pool
+	Quote string `yaml:"quote"`
+	// Type is uniswap_v2 for v2-style pairs or uniswap_v3
+	Type string `yaml:"type"`
+	Pool string `yaml:"pool"`
+	// PoolAddress is Pool parsed by InitConf with rules of `addresses`
+	PoolAddress common.Address `yaml:"-"`
+}
+
+type VerifierConfig struct {
+	// MinHeaderSources is number of independent rpc endpoints which must return the same block header
+	MinHeaderSources int `yaml:"min_header_sources"`
+	// MaxSlotIndex is last storage slot probed when looking for balances mapping of token
+	MaxSlotIndex int `yaml:"max_slot_index"`
+}
+
+type IndexerConfig struct {
+	// Enabled turns on serving repeat erc20 balance lookups from indexed transfer logs
+	Enabled bool `yaml:"enabled"`
+	// ReconcileInterval is how often indexed balances are compared with balanceOf
+	ReconcileInterval time.Duration `yaml:"reconcile_interval"`
+	// MaxTracked is limit of indexed balances, least recently queried are dropped first
+	MaxTracked int `yaml:"max_tracked"`
+	// MaxBlockRange is max blocks in one logs query, on bigger gap balances are read again instead
+	MaxBlockRange uint64 `yaml:"max_block_range"`
+	// MaxStaleness is max age of last indexed block, balances of chain indexed earlier are read from rpc
+	MaxStaleness time.Duration `yaml:"max_staleness"`
+}
+
+type TransfersConfig struct {
+	// InitialBlockRange is size of first eth_getLogs range, it is halved when provider rejects range and grows back on success
+	InitialBlockRange uint64 `yaml:"initial_block_range"`
+	MaxBlockRange     uint64 `yaml:"max_block_range"`
+	// MaxScanBlocks limits blocks scanned by one request, page is returned with cursor to continue when limit is reached
+	MaxScanBlocks uint64 `yaml:"max_scan_blocks"`
+}
+
+type AllowanceConfig struct {
+	// UnlimitedThreshold is raw allowance, decimal or 0x-prefixed hex, from which approval is treated as effectively unlimited
+	UnlimitedThreshold string `yaml:"unlimited_threshold"`
+	// ScanPeriod is how far back exposure scanner reads Approval logs, it is turned into blocks by block time of chain
+	ScanPeriod time.Duration `yaml:"scan_period"`
+	// ScanBlocks is fixed number of recent blocks scanned on every chain instead of ScanPeriod, zero uses ScanPeriod
+	ScanBlocks    uint64               `yaml:"scan_blocks"`
+	SpenderLabels []SpenderLabelConfig `yaml:"spender_labels"`
+}
+
+type GasConfig struct {
+	// BlockCount is number of recent blocks in eth_feeHistory
+	BlockCount uint64 `yaml:"block_count"`
+	// Percentiles are priority fee percentiles of slow, normal and fast suggestions
+	Percentiles []float64 `yaml:"percentiles"`
+	// CacheTTL is how long gas guidance of chain is reused
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// TxPriorityPercentile is priority fee percentile of recent blocks paid by transactions sent by approver, nil uses
+	// default and zero is valid
+	TxPriorityPercentile *float64 `yaml:"tx_priority_percentile"`
+	// MaxFeePerGas caps fee per gas of sent transactions, in gwei by chain name
+	MaxFeePerGas map[string]string `yaml:"max_fee_per_gas"`
+}
+
+type ENSConfig struct {
+	// CacheTTL is how long resolved names and primary names of addresses are reused
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// CacheSize is limit of cached names and of cached addresses, least recently used are dropped first
+	CacheSize int `yaml:"cache_size"`
+	// GatewayTimeout is deadline of CCIP-read request to gateway of offchain resolver
+	GatewayTimeout time.Duration `yaml:"gateway_timeout"`
+}
+
+// SpenderLabelConfig names spender contract in exposure reports in addition to built-in labels
+type SpenderLabelConfig struct {
+	Chain   string `yaml:"chain"`
+	Address string `yaml:"address"`
+	Label   string `yaml:"label"`
+}
+
+type StorageConfig struct {
+	// Path is path of storage file, empty keeps state in memory only
+	Path string `yaml:"path"`
+}
+
+type StreamConfig struct {
+	// MaxSubscriptions is limit of watched balances per websocket connection
+	MaxSubscriptions int `yaml:"max_subscriptions"`
 }
 
 func InitConf(confFile string) (*AppConfig, error) {
@@ -30,6 +178,38 @@ func InitConf(confFile string) (*AppConfig, error) {
 	if err = yaml.NewDecoder(file).Decode(&cfg); err != nil {
 		return nil, fmt.Errorf("error decode config file: %w", err)
 	}
+	if err = cfg.validateAddresses(); err != nil {
+		return nil, fmt.Errorf("error validate config file: %w", err)
+	}
+	if err = cfg.validateGas(); err != nil {
+		return nil, fmt.Errorf("error validate config file: %w", err)
+	}
 
 	return &cfg, nil
 }
+
+// validateAddresses checks all addresses of config with the same rules as addresses in requests, parsed pool
+// addresses are kept in config
+func (c *AppConfig) validateAddresses() error {
+	for i := range c.Pricing.DEXPools {
+		address, err := utils.ParseAddress(c.Pricing.DEXPools[i].Pool, c.Addresses.Lenient)
+		if err != nil {
+			return fmt.Errorf("pricing.dex_pools[%d].pool: %w", i, err)
+		}
+		c.Pricing.DEXPools[i].PoolAddress = address
+	}
+	for i, label := range c.Allowance.SpenderLabels {
+		if _, err := utils.ParseAddress(label.Address, c.Addresses.Lenient); err != nil {
+			return fmt.Errorf("allowance.spender_labels[%d].address: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateGas checks percentiles of gas config which are not replaced by defaults
+func (c *AppConfig) validateGas() error {
+	if percentile := c.Gas.TxPriorityPercentile; percentile != nil && (*percentile < 0 || *percentile > 100) {
+		return fmt.Errorf("gas.tx_priority_percentile must be in [0, 100]: %v", *percentile)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package entities
+
+type AccountType string
+
+const (
+	// AccountTypeEOA is account without code, or EIP-7702 account delegated to contract code
+	AccountTypeEOA          AccountType = "eoa"
+	AccountTypeContract     AccountType = "contract"
+	AccountTypeProxy        AccountType = "proxy"
+	AccountTypeSmartAccount AccountType = "smart_account"
+)
+
+type ProxyStandard string
+
+const (
+	ProxyEIP1967       ProxyStandard = "eip-1967"
+	ProxyEIP1967Beacon ProxyStandard = "eip-1967-beacon"
+	ProxyEIP1822       ProxyStandard = "eip-1822"
+	ProxyEIP1167       ProxyStandard = "eip-1167"
+	// ProxyZeppelinOS is pre EIP-1967 slot of OpenZeppelin proxies, USDC proxy uses it
+	ProxyZeppelinOS ProxyStandard = "zeppelinos"
+)
+
+type SmartAccountKind string
+
+const SmartAccountSafe SmartAccountKind = "safe"
+
+// AccountInfo is classification of address from its code, nonce and proxy storage slots
+type AccountInfo struct {
+	Chain     Chain       `json:"chain"`
+	ChainName string      `json:"chain_name"`
+	Address   string      `json:"address"`
+	Type      AccountType `json:"type"`
+	Nonce     uint64      `json:"nonce"`
+	CodeSize  int         `json:"code_size"`
+	// DelegatedTo is contract which code EIP-7702 account runs
+	DelegatedTo    string        `json:"delegated_to,omitempty"`
+	ProxyStandard  ProxyStandard `json:"proxy_standard,omitempty"`
+	Implementation string        `json:"implementation,omitempty"`
+	Beacon         string        `json:"beacon,omitempty"`
+	Admin          string        `json:"admin,omitempty"`
+	// SmartAccount fields are set for known smart account types
+	SmartAccount SmartAccountKind `json:"smart_account,omitempty"`
+	Version      string           `json:"version,omitempty"`
+	Owners       []string         `json:"owners,omitempty"`
+	Threshold    uint64           `json:"threshold,omitempty"`
+}
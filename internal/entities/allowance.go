@@ -0,0 +1,50 @@
+package entities
+
+// Allowance is amount of token which spender may transfer on behalf of owner. Token is empty for contracts which are
+// not known tokens, Allowance is empty if decimals of such contract can not be read.
+type Allowance struct {
+	Chain        Chain  `json:"chain"`
+	ChainName    string `json:"chain_name"`
+	Token        Token  `json:"token,omitempty"`
+	Contract     string `json:"contract"`
+	Owner        string `json:"owner"`
+	Spender      string `json:"spender"`
+	Allowance    string `json:"allowance,omitempty"`
+	AllowanceWei string `json:"allowance_wei"`
+	// Unlimited is set for max uint256 approvals and ones at or above configured threshold
+	Unlimited bool `json:"unlimited"`
+}
+
+// Exposure is non-zero allowance given by holder, found by Approval logs
+type Exposure struct {
+	Allowance
+	SpenderLabel string `json:"spender_label,omitempty"`
+	// ApprovedAtBlock is block of latest Approval log of token and spender
+	ApprovedAtBlock uint64 `json:"approved_at_block"`
+}
+
+// ExposureScan is range of blocks which was scanned for Approval logs on chain
+type ExposureScan struct {
+	Chain     Chain  `json:"chain"`
+	ChainName string `json:"chain_name"`
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+}
+
+// FailedExposure is approved pair of contract and spender which allowance could not be read
+type FailedExposure struct {
+	Chain     Chain  `json:"chain"`
+	ChainName string `json:"chain_name"`
+	Contract  string `json:"contract"`
+	Spender   string `json:"spender"`
+	Error     string `json:"error"`
+}
+
+// ExposureReport is list of current approvals of holder. Approvals given before scanned ranges are not included.
+type ExposureReport struct {
+	Holder       string           `json:"holder"`
+	Exposures    []Exposure       `json:"exposures"`
+	Scanned      []ExposureScan   `json:"scanned"`
+	FailedChains []string         `json:"failed_chains,omitempty"`
+	FailedPairs  []FailedExposure `json:"failed_pairs,omitempty"`
+}
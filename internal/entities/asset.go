@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AssetVariants maps canonical asset to tokens representing it on chains. Canonical token goes first,
+// bridged variants follow. Tokens missing here are canonical assets of their own.
+var AssetVariants = map[Token][]Token{
+	AgEUR: {AgEUR, LZAgEUR},
+}
+
+// bridgedTokens are tokens issued by bridge instead of asset issuer
+var bridgedTokens = map[Token]struct{}{
+	LZAgEUR: {},
+}
+
+// AssetMember is chain specific representation of canonical asset
+type AssetMember struct {
+	Chain   Chain
+	Token   Token
+	Native  bool
+	Bridged bool
+}
+
+// AssetBalance is balance of canonical asset summed across chains and bridged variants. BlockTag is the least final
+// tag actually used by chains, FailedChains are not part of total.
+type AssetBalance struct {
+	Asset        Token          `json:"asset"`
+	Holder       common.Address `json:"holder"`
+	HolderName   string         `json:"holder_name,omitempty"`
+	BlockTag     BlockTag       `json:"block_tag"`
+	TotalBalance string         `json:"total_balance"`
+	USDValue     string         `json:"usd_value,omitempty"`
+	Chains       []*Balance     `json:"chains"`
+	FailedChains []string       `json:"failed_chains,omitempty"`
+}
+
+// CanonicalAsset returns canonical asset of token, bridged variants are mapped to asset they represent
+func CanonicalAsset(token Token) Token {
+	for asset, variants := range AssetVariants {
+		for _, variant := range variants {
+			if variant == token {
+				return asset
+			}
+		}
+	}
+	return token
+}
+
+// GetAssetMembers returns all chain specific tokens of canonical asset, native coins included.
+// Members are sorted by chain id, canonical token goes before bridged variant on the same chain.
+func GetAssetMembers(asset Token) []AssetMember {
+	variants, ok := AssetVariants[asset]
+	if !ok {
+		variants = []Token{asset}
+	}
+	seen := make(map[Chain]map[Token]struct{})
+	members := make([]AssetMember, 0, len(variants))
+	add := func(chain Chain, token Token) {
+		if _, ok := seen[chain][token]; ok {
+			return
+		}
+		if seen[chain] == nil {
+			seen[chain] = make(map[Token]struct{})
+		}
+		seen[chain][token] = struct{}{}
+		_, bridged := bridgedTokens[token]
+		members = append(members, AssetMember{
+			Chain:   chain,
+			Token:   token,
+			Native:  IsFuel(chain, token),
+			Bridged: bridged,
+		})
+	}
+	for _, token := range variants {
+		for chain := range Tokens[token] {
+			add(chain, token)
+		}
+		for chain, fuel := range chainFuel {
+			if fuel == token {
+				add(chain, token)
+			}
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Chain != members[j].Chain {
+			return members[i].Chain < members[j].Chain
+		}
+		if members[i].Bridged != members[j].Bridged {
+			return !members[i].Bridged
+		}
+		return members[i].Token < members[j].Token
+	})
+	return members
+}
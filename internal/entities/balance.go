@@ -1,9 +1,60 @@
 package entities
 
+import (
+	"math/big"
+	"time"
+)
+
 type Balance struct {
-	Chain           Chain  `json:"chain"`
-	ChainName       string `json:"chain_name"`
-	Token           Token  `json:"token"`
-	TokenBalance    string `json:"token_balance"`
-	TokenBalanceWei string `json:"token_balance_wei"`
+	Chain           Chain    `json:"chain"`
+	ChainName       string   `json:"chain_name"`
+	Token           Token    `json:"token"`
+	TokenBalance    string   `json:"token_balance"`
+	TokenBalanceWei string   `json:"token_balance_wei"`
+	BlockTag        BlockTag `json:"block_tag"`
+	// USDValue and USDPrice are empty if token has no reliable price
+	USDValue          string `json:"usd_value,omitempty"`
+	USDPrice          string `json:"usd_price,omitempty"`
+	USDPriceUpdatedAt int64  `json:"usd_price_updated_at,omitempty"`
+	// USDPriceSource is oracle or pool the price is read from, `_spot` sources can be moved within single block
+	USDPriceSource string `json:"usd_price_source,omitempty"`
+	// Verified is set when balance is proven by eth_getProof against block agreed by independent endpoints
+	Verified    bool   `json:"verified,omitempty"`
+	BlockNumber uint64 `json:"block_number,omitempty"`
+	// Holder and HolderName are set in http responses, HolderName is primary ENS name of holder
+	Holder     string `json:"holder,omitempty"`
+	HolderName string `json:"holder_name,omitempty"`
+	// Account is classification of holder, it is set on request
+	Account *AccountInfo `json:"account,omitempty"`
+}
+
+// BalanceSnapshot is balance read from rpc, persisted so recent result is reused after restart
+type BalanceSnapshot struct {
+	Value     *big.Int  `json:"value"`
+	BlockTag  BlockTag  `json:"block_tag"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type MultiTokenBalance struct {
+	Chain      Chain                   `json:"chain"`
+	ChainName  string                  `json:"chain_name"`
+	Contract   string                  `json:"contract"`
+	Holder     string                  `json:"holder"`
+	HolderName string                  `json:"holder_name,omitempty"`
+	BlockTag   BlockTag                `json:"block_tag"`
+	Tokens     []MultiTokenBalanceItem `json:"tokens"`
+}
+
+type MultiTokenBalanceItem struct {
+	ID          string `json:"id"`
+	Balance     string `json:"balance"`
+	MetadataURI string `json:"metadata_uri,omitempty"`
+}
+
+// Price is USD price of token
+type Price struct {
+	Token     Token  `json:"token"`
+	USDPrice  string `json:"usd_price"`
+	UpdatedAt int64  `json:"updated_at"`
+	Source    string `json:"source"`
 }
@@ -0,0 +1,76 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type BlockTag string
+
+const (
+	BlockTagLatest    BlockTag = "latest"
+	BlockTagPending   BlockTag = "pending"
+	BlockTagSafe      BlockTag = "safe"
+	BlockTagFinalized BlockTag = "finalized"
+)
+
+var ErrUnknownBlockTag = errors.New("unknown block tag")
+
+// BlockTagFromString parses block tag, empty string means latest.
+func BlockTagFromString(src string) (BlockTag, error) {
+	if src == "" {
+		return BlockTagLatest, nil
+	}
+	switch tag := BlockTag(strings.ToLower(src)); tag {
+	case BlockTagLatest, BlockTagPending, BlockTagSafe, BlockTagFinalized:
+		return tag, nil
+	}
+	return "", ErrUnknownBlockTag
+}
+
+// BlockNumber returns block number argument for ethclient calls.
+// Pending state is requested with dedicated Pending* methods, so it is nil here as well as latest.
+func (t BlockTag) BlockNumber() *big.Int {
+	switch t {
+	case BlockTagSafe:
+		return big.NewInt(int64(rpc.SafeBlockNumber))
+	case BlockTagFinalized:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber))
+	}
+	return nil
+}
+
+// Fallback returns closest tag to use if endpoint does not support current one.
+// Finalized degrades to safe and only then to latest, so caller always gets the most final state available.
+func (t BlockTag) Fallback() (BlockTag, bool) {
+	switch t {
+	case BlockTagFinalized:
+		return BlockTagSafe, true
+	case BlockTagSafe, BlockTagPending:
+		return BlockTagLatest, true
+	}
+	return "", false
+}
+
+// LeastFinal returns tag of less final state, it is the tag which holds for results read at both tags
+func LeastFinal(a, b BlockTag) BlockTag {
+	if b.finality() < a.finality() {
+		return b
+	}
+	return a
+}
+
+func (t BlockTag) finality() int {
+	switch t {
+	case BlockTagPending:
+		return 0
+	case BlockTagSafe:
+		return 2
+	case BlockTagFinalized:
+		return 3
+	}
+	return 1
+}
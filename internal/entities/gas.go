@@ -0,0 +1,76 @@
+package entities
+
+import "math/big"
+
+// GasFee is fee per gas in wei and in gwei
+type GasFee struct {
+	Wei  string `json:"wei"`
+	Gwei string `json:"gwei"`
+}
+
+// GasPercentile is priority fee paid at percentile of transactions in recent blocks
+type GasPercentile struct {
+	Percentile  float64 `json:"percentile"`
+	PriorityFee GasFee  `json:"priority_fee"`
+}
+
+// GasSuggestion is EIP-1559 fee for transaction
+type GasSuggestion struct {
+	MaxFeePerGas         GasFee `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas GasFee `json:"max_priority_fee_per_gas"`
+}
+
+type GasSuggestions struct {
+	Slow   GasSuggestion `json:"slow"`
+	Normal GasSuggestion `json:"normal"`
+	Fast   GasSuggestion `json:"fast"`
+}
+
+// GasInfo is gas guidance of chain. EIP-1559 fields are empty for chains without base fee, GasPrice is always set.
+type GasInfo struct {
+	Chain       Chain  `json:"chain"`
+	ChainName   string `json:"chain_name"`
+	BlockNumber uint64 `json:"block_number"`
+	EIP1559     bool   `json:"eip1559"`
+	// BaseFee is base fee of next block
+	BaseFee     *GasFee         `json:"base_fee,omitempty"`
+	Percentiles []GasPercentile `json:"priority_fee_percentiles,omitempty"`
+	Suggestions *GasSuggestions `json:"suggestions,omitempty"`
+	GasPrice    GasFee          `json:"gas_price"`
+}
+
+// Rollup is fee model of L2 chain
+type Rollup string
+
+const (
+	RollupOPStack  Rollup = "op-stack"
+	RollupArbitrum Rollup = "arbitrum"
+)
+
+// NativeFee is fee in wei and in native coin of chain
+type NativeFee struct {
+	Wei    string `json:"wei"`
+	Amount string `json:"amount"`
+}
+
+// FeeEstimate is total fee of transaction. On rollups it is L2 execution fee plus L1 data fee,
+// which is usually the bigger part. Other chains have execution fee only.
+type FeeEstimate struct {
+	Chain     Chain  `json:"chain"`
+	ChainName string `json:"chain_name"`
+	Token     Token  `json:"token"`
+	Rollup    Rollup `json:"rollup,omitempty"`
+	// GasLimit is L2 execution gas, on arbitrum gas which pays L1 data fee is not included
+	GasLimit     uint64    `json:"gas_limit"`
+	GasPrice     GasFee    `json:"gas_price"`
+	ExecutionFee NativeFee `json:"execution_fee"`
+	L1DataFee    NativeFee `json:"l1_data_fee"`
+	TotalFee     NativeFee `json:"total_fee"`
+}
+
+// TxFee is fee of transaction to send. GasFeeCap and GasTipCap are set for EIP-1559 transactions, GasPrice for legacy ones.
+type TxFee struct {
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+	GasPrice  *big.Int
+}
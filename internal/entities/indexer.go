@@ -0,0 +1,12 @@
+package entities
+
+// IndexerStatus is progress of transfer indexer on chain, balances of stale chain are read from rpc
+type IndexerStatus struct {
+	Chain      Chain  `json:"chain"`
+	ChainName  string `json:"chain_name"`
+	Head       uint64 `json:"head"`
+	IndexedAt  int64  `json:"indexed_at,omitempty"`
+	AgeSeconds int64  `json:"age_seconds"`
+	Stale      bool   `json:"stale"`
+	Tracked    int    `json:"tracked"`
+}
@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PriceFeeds are Chainlink <TOKEN>/USD aggregator proxies, see https://docs.chain.link/data-feeds/price-feeds/addresses
+var PriceFeeds = map[Token]map[Chain]common.Address{
+	ETH: {
+		ChainEthereum:  common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"),
+		ChainPolygon:   common.HexToAddress("0xF9680D99D6C9589e2a93a78A04A279e029bd9E7d"),
+		ChainArbitrum:  common.HexToAddress("0x639Fe6ab55C921f74e7fac1ee960C0B6293ba612"),
+		ChainOptimism:  common.HexToAddress("0x13e3Ee699D1909E989722E753853AE30b17e08c5"),
+		ChainAvalanche: common.HexToAddress("0x976B3D034E162d8bD72D6b9C989d545b839003b0"),
+	},
+	BTC_b: {
+		ChainEthereum:  common.HexToAddress("0xF4030086522a5bEEa4988F8cA5B36dbC97BeE88c"),
+		ChainAvalanche: common.HexToAddress("0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743"),
+	},
+	USDC: {
+		ChainEthereum:  common.HexToAddress("0x8fFfFfd4AfB6115b954Bd326cbe7B4BA576818f6"),
+		ChainPolygon:   common.HexToAddress("0xfE4A8cc5b5B2366C1B58Bea3858e81843581b2F7"),
+		ChainArbitrum:  common.HexToAddress("0x50834F3163758fcC1Df9973b6e91f0F0F0434aD3"),
+		ChainOptimism:  common.HexToAddress("0x16a9FA2FDa030272Ce99B29CF780dFA30361E0f3"),
+		ChainAvalanche: common.HexToAddress("0xF096872672F44d6EBA71458D74fe67F9a77a23B9"),
+	},
+	USDT: {
+		ChainEthereum:  common.HexToAddress("0x3E7d1eAB13ad0104d2750B8863b489D65364e32D"),
+		ChainPolygon:   common.HexToAddress("0x0A6513e40db6EB1b165753AD52E80663aeA50545"),
+		ChainArbitrum:  common.HexToAddress("0x3f3f5dF88dC9F13eac63DF89EC16ef6e7E25DdE7"),
+		ChainOptimism:  common.HexToAddress("0xECef79E109e997bCA29c1c0897ec9d7b03647F5E"),
+		ChainAvalanche: common.HexToAddress("0xEBE676ee90Fe1112671f19b6B7459bC678B67e8a"),
+	},
+	DAI: {
+		ChainEthereum:  common.HexToAddress("0xAed0c38402a5d19df6E4c03F4E2DceD6e29c1ee9"),
+		ChainPolygon:   common.HexToAddress("0x4746DeC9e833A82EC7C2C1356372CcF2cfcD2F3D"),
+		ChainArbitrum:  common.HexToAddress("0xc5C8E77B397E531B8EC06BFb0048328B30E9eCfB"),
+		ChainOptimism:  common.HexToAddress("0x8dBa75e83DA73cc766A7e5a0ee71F656BAb470d6"),
+		ChainAvalanche: common.HexToAddress("0x51D7180edA2260cc4F6e4EebB82FEF5c3c2B8300"),
+	},
+	XDAI: {
+		ChainGnosis: common.HexToAddress("0x678df3415fc31947dA4324eC63212874be5a82f8"),
+	},
+	MATIC: {
+		ChainEthereum: common.HexToAddress("0x7bAC85A8a13A4BcD8abb3eB7d6b4d632c5a57676"),
+		ChainPolygon:  common.HexToAddress("0xAB594600376Ec9fD91F8e885dADF0CE036862dE0"),
+	},
+	AVAX: {
+		ChainEthereum:  common.HexToAddress("0xFF3EEb22B5E3dE6e705b44749C2559d704923FD7"),
+		ChainAvalanche: common.HexToAddress("0x0A77230d17318075983913bC2145DB16C7366156"),
+	},
+	BNB: {
+		ChainEthereum: common.HexToAddress("0x14e613AC84a31f709eadbdF89C6CC390fDc9540A"),
+		ChainBNB:      common.HexToAddress("0x0567F2323251f0Aab15c8dFb1967E4e8A7D42aeE"),
+	},
+	FTM: {
+		ChainEthereum: common.HexToAddress("0x2DE7E4a9488488e0058B95854CC2f7955B35dC9b"),
+		ChainFantom:   common.HexToAddress("0xf4766552D15AE4d256Ad41B6cf2933482B0680dc"),
+	},
+}
+
+// PriceFeedHeartbeats are heartbeats of feeds which update more often than once a day. Feed which is silent
+// longer than its heartbeat is stale even if its round is younger than a day.
+var PriceFeedHeartbeats = map[common.Address]time.Duration{
+	PriceFeeds[ETH][ChainEthereum]:   time.Hour,
+	PriceFeeds[BTC_b][ChainEthereum]: time.Hour,
+	PriceFeeds[DAI][ChainEthereum]:   time.Hour,
+	PriceFeeds[USDC][ChainEthereum]:  24 * time.Hour,
+	PriceFeeds[USDT][ChainEthereum]:  24 * time.Hour,
+}
+
+// GetPriceFeeds returns chains with USD price feed of token. Feed on the balance chain goes first,
+// feeds on other chains are fallback as price of the token is the same everywhere.
+func GetPriceFeeds(chain Chain, token Token) []ChainAddress {
+	feeds, ok := PriceFeeds[token]
+	if !ok {
+		return nil
+	}
+	result := make([]ChainAddress, 0, len(feeds))
+	if feed, ok := feeds[chain]; ok {
+		result = append(result, ChainAddress{Chain: chain, Address: feed})
+	}
+	for feedChain, feed := range feeds {
+		if feedChain != chain {
+			result = append(result, ChainAddress{Chain: feedChain, Address: feed})
+		}
+	}
+	// fallback feeds are ordered by chain, so the same feed is tried first on every call
+	fallback := result
+	if len(result) > 0 && result[0].Chain == chain {
+		fallback = result[1:]
+	}
+	sort.Slice(fallback, func(i, j int) bool {
+		return fallback[i].Chain < fallback[j].Chain
+	})
+	return result
+}
+
+type ChainAddress struct {
+	Chain   Chain
+	Address common.Address
+}
@@ -0,0 +1,47 @@
+package entities
+
+import "github.com/ethereum/go-ethereum/common"
+
+// spenderLabelsAnyChain are contracts deployed to the same address on every supported chain
+var spenderLabelsAnyChain = map[common.Address]string{
+	common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3"): "Uniswap Permit2",
+	common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582"): "1inch Aggregation Router v5",
+}
+
+// SpenderLabels are well known spender contracts per chain
+var SpenderLabels = map[Chain]map[common.Address]string{
+	ChainEthereum: {
+		common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"): "Uniswap V2 Router",
+		common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564"): "Uniswap V3 Router",
+		common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"): "Uniswap V3 Router 2",
+		common.HexToAddress("0xDef1C0ded9bec7F1a1670819833240f027b25EfF"): "0x Exchange Proxy",
+		common.HexToAddress("0x87870Bca3F3fD6335C3F4ce8392D69350B4fA4E2"): "Aave V3 Pool",
+	},
+	ChainOptimism: {
+		common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564"): "Uniswap V3 Router",
+		common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"): "Uniswap V3 Router 2",
+		common.HexToAddress("0x794a61358D6845594F94dc1DB02A252b5b4814aD"): "Aave V3 Pool",
+	},
+	ChainPolygon: {
+		common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564"): "Uniswap V3 Router",
+		common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"): "Uniswap V3 Router 2",
+		common.HexToAddress("0xDef1C0ded9bec7F1a1670819833240f027b25EfF"): "0x Exchange Proxy",
+		common.HexToAddress("0x794a61358D6845594F94dc1DB02A252b5b4814aD"): "Aave V3 Pool",
+	},
+	ChainArbitrum: {
+		common.HexToAddress("0xE592427A0AEce92De3Edee1F18E0157C05861564"): "Uniswap V3 Router",
+		common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"): "Uniswap V3 Router 2",
+		common.HexToAddress("0x794a61358D6845594F94dc1DB02A252b5b4814aD"): "Aave V3 Pool",
+	},
+	ChainAvalanche: {
+		common.HexToAddress("0x794a61358D6845594F94dc1DB02A252b5b4814aD"): "Aave V3 Pool",
+	},
+}
+
+// GetSpenderLabel returns name of well known spender contract or empty string
+func GetSpenderLabel(chain Chain, spender common.Address) string {
+	if label, ok := SpenderLabels[chain][spender]; ok {
+		return label
+	}
+	return spenderLabelsAnyChain[spender]
+}
@@ -0,0 +1,33 @@
+package entities
+
+import "time"
+
+// TokenMetadata is erc20 metadata read from token contract
+type TokenMetadata struct {
+	Chain     Chain     `json:"chain"`
+	Address   string    `json:"address"`
+	Symbol    string    `json:"symbol"`
+	Decimals  uint8     `json:"decimals"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TokenInfo is erc20 metadata read from contract. Registry is set when contract is known token.
+type TokenInfo struct {
+	Chain          Chain               `json:"chain"`
+	ChainName      string              `json:"chain_name"`
+	Contract       string              `json:"contract"`
+	Name           string              `json:"name"`
+	Symbol         string              `json:"symbol"`
+	Decimals       uint8               `json:"decimals"`
+	TotalSupply    string              `json:"total_supply"`
+	TotalSupplyWei string              `json:"total_supply_wei"`
+	Registry       *TokenRegistryCheck `json:"registry,omitempty"`
+}
+
+// TokenRegistryCheck compares on-chain metadata with known token, mismatch points to bridged variant or wrong address
+type TokenRegistryCheck struct {
+	Token         Token `json:"token"`
+	Decimals      int   `json:"decimals"`
+	SymbolMatch   bool  `json:"symbol_match"`
+	DecimalsMatch bool  `json:"decimals_match"`
+}
@@ -0,0 +1,49 @@
+package entities
+
+type TxStatus string
+
+const (
+	TxStatusSuccess TxStatus = "success"
+	TxStatusFailed  TxStatus = "failed"
+	// TxStatusPending is transaction known to node but not included in a block yet
+	TxStatusPending TxStatus = "pending"
+)
+
+type TxEventType string
+
+const (
+	TxEventTransfer TxEventType = "transfer"
+	TxEventApproval TxEventType = "approval"
+)
+
+// TxERC20Event is decoded erc20 Transfer or Approval log. Token and Amount are set for contracts from token registry only.
+type TxERC20Event struct {
+	Event     TxEventType `json:"event"`
+	LogIndex  uint        `json:"log_index"`
+	Contract  string      `json:"contract"`
+	Token     Token       `json:"token,omitempty"`
+	From      string      `json:"from,omitempty"`
+	To        string      `json:"to,omitempty"`
+	Owner     string      `json:"owner,omitempty"`
+	Spender   string      `json:"spender,omitempty"`
+	Amount    string      `json:"amount,omitempty"`
+	AmountWei string      `json:"amount_wei"`
+}
+
+// TransactionInfo is status of transaction, receipt fields are empty while it is pending
+type TransactionInfo struct {
+	Chain             Chain          `json:"chain"`
+	ChainName         string         `json:"chain_name"`
+	Hash              string         `json:"hash"`
+	Status            TxStatus       `json:"status"`
+	From              string         `json:"from"`
+	To                string         `json:"to,omitempty"`
+	ContractAddress   string         `json:"contract_address,omitempty"`
+	BlockNumber       uint64         `json:"block_number,omitempty"`
+	BlockHash         string         `json:"block_hash,omitempty"`
+	Confirmations     uint64         `json:"confirmations"`
+	GasUsed           uint64         `json:"gas_used,omitempty"`
+	EffectiveGasPrice *GasFee        `json:"effective_gas_price,omitempty"`
+	ERC20Events       []TxERC20Event `json:"erc20_events"`
+	ExplorerURL       string         `json:"explorer_url"`
+}
@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type TransferDirection string
+
+const (
+	TransferDirectionIn  TransferDirection = "in"
+	TransferDirectionOut TransferDirection = "out"
+	TransferDirectionAll TransferDirection = "all"
+)
+
+var (
+	ErrUnknownTransferDirection = errors.New("unknown transfer direction")
+	ErrInvalidTransferCursor    = errors.New("invalid transfer cursor")
+)
+
+// TransferDirectionFromString parses direction relative to address, empty string means all.
+func TransferDirectionFromString(src string) (TransferDirection, error) {
+	if src == "" {
+		return TransferDirectionAll, nil
+	}
+	switch direction := TransferDirection(strings.ToLower(src)); direction {
+	case TransferDirectionIn, TransferDirectionOut, TransferDirectionAll:
+		return direction, nil
+	}
+	return "", ErrUnknownTransferDirection
+}
+
+// TransferCursor is position in chain, page continues with transfers strictly before it
+type TransferCursor struct {
+	BlockNumber uint64
+	LogIndex    uint
+}
+
+func (c TransferCursor) String() string {
+	return fmt.Sprintf("%d-%d", c.BlockNumber, c.LogIndex)
+}
+
+// Before reports whether transfer at position goes before cursor in chain order
+func (c TransferCursor) Before(blockNumber uint64, logIndex uint) bool {
+	return blockNumber < c.BlockNumber || (blockNumber == c.BlockNumber && logIndex < c.LogIndex)
+}
+
+func TransferCursorFromString(src string) (*TransferCursor, error) {
+	block, index, ok := strings.Cut(src, "-")
+	if !ok {
+		return nil, ErrInvalidTransferCursor
+	}
+	blockNumber, err := strconv.ParseUint(block, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidTransferCursor
+	}
+	logIndex, err := strconv.ParseUint(index, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidTransferCursor
+	}
+	return &TransferCursor{BlockNumber: blockNumber, LogIndex: uint(logIndex)}, nil
+}
+
+type Transfer struct {
+	TxHash      string            `json:"tx_hash"`
+	LogIndex    uint              `json:"log_index"`
+	BlockNumber uint64            `json:"block_number"`
+	From        string            `json:"from"`
+	To          string            `json:"to"`
+	Direction   TransferDirection `json:"direction"`
+	Amount      string            `json:"amount"`
+	AmountWei   string            `json:"amount_wei"`
+}
+
+// TransferPage is page of transfers from newest to oldest. Empty NextCursor means history down to FromBlock is exhausted.
+type TransferPage struct {
+	Chain      Chain      `json:"chain"`
+	ChainName  string     `json:"chain_name"`
+	Token      Token      `json:"token"`
+	Address    string     `json:"address"`
+	FromBlock  uint64     `json:"from_block"`
+	Transfers  []Transfer `json:"transfers"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// TransferQuery selects page of transfers. Nil cursor starts from ToBlock, zero ToBlock means latest block.
+type TransferQuery struct {
+	Direction TransferDirection
+	Cursor    *TransferCursor
+	FromBlock uint64
+	ToBlock   uint64
+	Limit     int
+}
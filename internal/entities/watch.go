@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+)
+
+type WatchCondition string
+
+const (
+	WatchConditionBelow     WatchCondition = "below"
+	WatchConditionAbove     WatchCondition = "above"
+	WatchConditionAnyChange WatchCondition = "any_change"
+)
+
+var ErrUnknownWatchCondition = errors.New("unknown watch condition")
+
+func WatchConditionFromString(src string) (WatchCondition, error) {
+	switch condition := WatchCondition(strings.ToLower(src)); condition {
+	case WatchConditionBelow, WatchConditionAbove, WatchConditionAnyChange:
+		return condition, nil
+	}
+	return "", ErrUnknownWatchCondition
+}
+
+// Watch is a balance condition which triggers webhook. Empty token means native coin of the chain.
+type Watch struct {
+	ID         string         `json:"id"`
+	Chain      Chain          `json:"chain"`
+	ChainName  string         `json:"chain_name"`
+	Token      Token          `json:"token,omitempty"`
+	Address    string         `json:"address"`
+	Condition  WatchCondition `json:"condition"`
+	Threshold  string         `json:"threshold,omitempty"`
+	WebhookURL string         `json:"webhook_url"`
+	Secret     string         `json:"secret,omitempty"`
+	CreatedAt  int64          `json:"created_at"`
+}
+
+// WatchEvent is a payload of webhook
+type WatchEvent struct {
+	ID              string         `json:"id"`
+	WatchID         string         `json:"watch_id"`
+	Condition       WatchCondition `json:"condition"`
+	Threshold       string         `json:"threshold,omitempty"`
+	BlockNumber     uint64         `json:"block_number"`
+	PreviousBalance string         `json:"previous_balance,omitempty"`
+	Balance         *Balance       `json:"balance"`
+	TriggeredAt     int64          `json:"triggered_at"`
+}
+
+// DeadLetter is a webhook which was not delivered after all retries
+type DeadLetter struct {
+	Event      *WatchEvent `json:"event"`
+	WebhookURL string      `json:"webhook_url"`
+	Attempts   int         `json:"attempts"`
+	LastError  string      `json:"last_error"`
+	FailedAt   int64       `json:"failed_at"`
+}
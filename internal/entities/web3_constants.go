@@ -163,6 +163,16 @@ func GetTokenAddress(chainID Chain, token Token) (common.Address, error) {
 	return Tokens[token][chainID], nil
 }
 
+// GetTokenByAddress returns known token deployed at address on chain
+func GetTokenByAddress(chain Chain, address common.Address) (Token, bool) {
+	for token, deployments := range Tokens {
+		if deployment, ok := deployments[chain]; ok && deployment == address {
+			return token, true
+		}
+	}
+	return "", false
+}
+
 func GetChain(chainID *big.Int) Chain {
 	data := map[uint64]Chain{
 		1:          ChainEthereum,
@@ -185,20 +195,22 @@ func GetChain(chainID *big.Int) Chain {
 
 func TokenFromString(data string) (Token, error) {
 	dataMap := map[string]Token{
-		"ETH":   ETH,
-		"USDC":  USDC,
-		"DAI":   DAI,
-		"USDT":  USDT,
-		"MATIC": MATIC,
-		"FTM":   FTM,
-		"AVAX":  AVAX,
-		"BNB":   BNB,
-		"BTC.b": BTC_b,
-		"BTC":   BTC_b,
-		"ONE":   One,
-		"STG":   STG,
-		"CELO":  CELO,
-		"XDAI":  XDAI,
+		"ETH":     ETH,
+		"USDC":    USDC,
+		"DAI":     DAI,
+		"USDT":    USDT,
+		"MATIC":   MATIC,
+		"FTM":     FTM,
+		"AVAX":    AVAX,
+		"BNB":     BNB,
+		"BTC.b":   BTC_b,
+		"BTC":     BTC_b,
+		"ONE":     One,
+		"STG":     STG,
+		"CELO":    CELO,
+		"XDAI":    XDAI,
+		"AGEUR":   AgEUR,
+		"LZAGEUR": LZAgEUR,
 	}
 	if coin, ok := dataMap[strings.ToUpper(data)]; ok {
 		return coin, nil
@@ -206,32 +218,36 @@ func TokenFromString(data string) (Token, error) {
 	return "", ErrUnknownToken
 }
 
+var chainFuel = map[Chain]Token{
+	ChainArbitrum:  ETH,
+	ChainOptimism:  ETH,
+	ChainEthereum:  ETH,
+	ChainPolygon:   MATIC,
+	ChainFantom:    FTM,
+	ChainAvalanche: AVAX,
+	ChainHarmony:   One,
+	ChainBNB:       BNB,
+	ChainCelo:      CELO,
+	ChainGnosis:    XDAI,
+}
+
 func MapChainToFuel(chain Chain) Token {
-	switch chain {
-	case ChainArbitrum, ChainOptimism, ChainEthereum:
-		return ETH
-	case ChainPolygon:
-		return MATIC
-	case ChainFantom:
-		return FTM
-	case ChainAvalanche:
-		return AVAX
-	case ChainHarmony:
-		return One
-	case ChainBNB:
-		return BNB
-	case ChainCelo:
-		return CELO
-	case ChainGnosis:
-		return XDAI
+	if fuel, ok := chainFuel[chain]; ok {
+		return fuel
 	}
 	log.Fatal("no fuel for chain", chain)
 	return ETH
 }
 
+// IsFuel reports whether token is native coin of chain
+func IsFuel(chain Chain, token Token) bool {
+	fuel, ok := chainFuel[chain]
+	return ok && fuel == token
+}
+
 func GetTokenDecimals(token Token) int {
 	switch token {
-	case MATIC, ETH, FTM, DAI, AVAX, BNB, AgEUR, One, STG, CELO, XDAI:
+	case MATIC, ETH, FTM, DAI, AVAX, BNB, AgEUR, LZAgEUR, One, STG, CELO, XDAI:
 		return 18
 	case BTC_b:
 		return 8
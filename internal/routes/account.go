@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getAccountInfo gets classification of address: eoa, contract, proxy with implementation or known smart account like Safe
+func (s *Server) getAccountInfo(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	address, err := s.resolveHolder(ctx)
+	if err != nil {
+		return holderFailed(ctx, err)
+	}
+	info, err := s.serviceBalancer.GetAccountInfo(ctx.UserContext(), chain, address)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(info)
+}
@@ -0,0 +1,49 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetAccountInfo(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("proxy contract", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/account/%s", chain.String(), usdcContract))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.AccountInfo
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, entities.AccountTypeProxy, response.Type)
+		require.Equal(t, entities.ProxyZeppelinOS, response.ProxyStandard)
+		require.NotEmpty(t, response.Implementation)
+	})
+
+	t.Run("balance with account", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/balance/%s?account=true", chain.String(), address))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.Balance
+		resp.RequireUnmarshal(t, &response)
+		require.NotNil(t, response.Account)
+		require.Equal(t, entities.AccountTypeEOA, response.Account.Type)
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/unknown/account/%s", address)).RequireNotFound(t)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/%s/account/0x1234", chain.String())).RequireBadRequest(t)
+	})
+}
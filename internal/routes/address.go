@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"altt/internal/utils"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+// invalidAddressError is rejected address of request field
+type invalidAddressError struct {
+	field string
+	err   *utils.AddressError
+}
+
+func (e *invalidAddressError) Error() string {
+	return fmt.Sprintf("%s: %s", e.field, e.err.Error())
+}
+
+func (e *invalidAddressError) Unwrap() error {
+	return e.err
+}
+
+// invalidAddressResponse is body of 400 response for rejected address
+type invalidAddressResponse struct {
+	Error  string              `json:"error"`
+	Field  string              `json:"field"`
+	Reason utils.AddressReason `json:"reason"`
+}
+
+// parseAddress parses address of request field, zero address is rejected as well
+func (s *Server) parseAddress(field, raw string) (common.Address, error) {
+	address, err := utils.ParseAddress(raw, s.lenientAddresses)
+	if err != nil {
+		var addressErr *utils.AddressError
+		errors.As(err, &addressErr)
+		return common.Address{}, &invalidAddressError{field: field, err: addressErr}
+	}
+	if address == (common.Address{}) {
+		return common.Address{}, &invalidAddressError{field: field, err: &utils.AddressError{Input: raw, Reason: utils.AddressReasonZeroAddress}}
+	}
+	return address, nil
+}
+
+// invalidAddress replies 400 with field and reason of rejected address
+func invalidAddress(ctx *fiber.Ctx, err error) error {
+	var addressErr *invalidAddressError
+	if !errors.As(err, &addressErr) {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	return ctx.Status(http.StatusBadRequest).JSON(invalidAddressResponse{
+		Error:  addressErr.Error(),
+		Field:  addressErr.field,
+		Reason: addressErr.err.Reason,
+	})
+}
@@ -0,0 +1,69 @@
+package routes_test
+
+import (
+	testhelpers "altt/internal/test_helpers"
+	"altt/internal/utils"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type invalidAddressResponse struct {
+	Error  string              `json:"error"`
+	Field  string              `json:"field"`
+	Reason utils.AddressReason `json:"reason"`
+}
+
+func TestServer_InvalidAddress(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("reasons", func(t *testing.T) {
+		table := map[string]utils.AddressReason{
+			"asda":                         utils.AddressReasonInvalidCharacter,
+			address[2:]:                    utils.AddressReasonMissingPrefix,
+			"0xd8dA6BF26964aF9D7eEd9e03E5": utils.AddressReasonInvalidLength,
+			strings.ToLower(address):       utils.AddressReasonMissingChecksum,
+			"0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045": utils.AddressReasonInvalidChecksum,
+			zeroAddress: utils.AddressReasonZeroAddress,
+		}
+		for raw, reason := range table {
+			// when
+			resp := srv.Get(t, fmt.Sprintf("/%s/balance/%s", chain.String(), raw))
+			resp.RequireBadRequest(t)
+
+			// then
+			var response invalidAddressResponse
+			resp.RequireUnmarshal(t, &response)
+			require.Equal(t, "address", response.Field, raw)
+			require.Equal(t, reason, response.Reason, raw)
+		}
+	})
+
+	t.Run("field of rejected address", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/%s/allowance/%s/%s", chain.String(), token, address, strings.ToLower(spender)))
+		resp.RequireBadRequest(t)
+
+		// then
+		var response invalidAddressResponse
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, "spender", response.Field)
+		require.Equal(t, utils.AddressReasonMissingChecksum, response.Reason)
+	})
+}
+
+func TestServer_LenientAddress(t *testing.T) {
+	// given server which accepts addresses without checksum
+	tCtx := testhelpers.GetClean(t)
+	tCtx.Conf.Addresses.Lenient = true
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	// when address passes parsing, unknown chain is reported
+	srv.Get(t, fmt.Sprintf("/exposures/%s?chain=unknown", strings.ToLower(address))).RequireNotFound(t)
+	srv.Get(t, fmt.Sprintf("/exposures/%s?chain=unknown", strings.ToUpper(address[2:]))).RequireBadRequest(t)
+	srv.Get(t, "/exposures/0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045?chain=unknown").RequireBadRequest(t)
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/allowance"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+)
+
+// getAllowance gets allowance of known token given by owner to spender. returns 404 if the token is not known.
+func (s *Server) getAllowance(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	token, err := entities.TokenFromString(ctx.Params("token"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	if entities.IsFuel(chain, token) {
+		return ctx.Status(http.StatusBadRequest).SendString(allowance.ErrNativeToken.Error())
+	}
+	if _, err = entities.GetTokenAddress(chain, token); err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	owner, spender, err := s.parseOwnerSpender(ctx)
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	result, err := s.serviceAllowance.GetAllowance(ctx.UserContext(), chain, token, owner, spender)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
+
+// getContractAllowance gets allowance of arbitrary erc20 contract given by owner to spender.
+func (s *Server) getContractAllowance(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	contract, err := s.parseAddress("contract", ctx.Params("contract"))
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	owner, spender, err := s.parseOwnerSpender(ctx)
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	result, err := s.serviceAllowance.GetContractAllowance(ctx.UserContext(), chain, contract, owner, spender)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(result)
+}
+
+func (s *Server) parseOwnerSpender(ctx *fiber.Ctx) (owner, spender common.Address, err error) {
+	if owner, err = s.parseAddress("owner", ctx.Params("owner")); err != nil {
+		return owner, spender, err
+	}
+	if spender, err = s.parseAddress("spender", ctx.Params("spender")); err != nil {
+		return owner, spender, err
+	}
+	return owner, spender, nil
+}
@@ -0,0 +1,62 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	spender      = "0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"
+	usdcContract = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	zeroAddress  = "0x0000000000000000000000000000000000000000"
+)
+
+func TestServer_GetAllowance(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("known token", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/%s/allowance/%s/%s", chain.String(), token, address, spender))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.Allowance
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, token, response.Token)
+		require.NotEmpty(t, response.AllowanceWei)
+	})
+
+	t.Run("arbitrary contract", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/erc20/%s/allowance/%s/%s", chain.String(), usdcContract, address, spender))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.Allowance
+		resp.RequireUnmarshal(t, &response)
+		require.Empty(t, response.Token)
+		require.NotEmpty(t, response.AllowanceWei)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		resp := srv.Get(t, fmt.Sprintf("/%s/unknown/allowance/%s/%s", chain.String(), address, spender))
+		resp.RequireNotFound(t)
+	})
+
+	t.Run("native token", func(t *testing.T) {
+		resp := srv.Get(t, fmt.Sprintf("/%s/%s/allowance/%s/%s", chain.String(), entities.MapChainToFuel(chain), address, spender))
+		resp.RequireBadRequest(t)
+	})
+
+	t.Run("invalid addresses", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/%s/%s/allowance/%s/%s", chain.String(), token, zeroAddress, spender)).RequireBadRequest(t)
+		srv.Get(t, fmt.Sprintf("/%s/%s/allowance/%s/%s", chain.String(), token, address, zeroAddress)).RequireBadRequest(t)
+		srv.Get(t, fmt.Sprintf("/%s/erc20/%s/allowance/%s/%s", chain.String(), zeroAddress, address, spender)).RequireBadRequest(t)
+	})
+}
@@ -2,27 +2,46 @@ package routes
 
 import (
 	"altt/internal/entities"
+	"altt/internal/service/web3/verifier"
+	"errors"
+	"fmt"
+	"math/big"
 	"net/http"
+	"strings"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/gofiber/fiber/v2"
 )
 
+const maxTokenIDs = 100
+
 // getNativeBalance gets the native balance of an address. returns 404 if the chain is not supported.
+// optional `tag` query param selects block tag: latest, pending, safe or finalized.
+// `verified=true` query param checks balance with eth_getProof against block header agreed by all rpc endpoints.
 func (s *Server) getNativeBalance(ctx *fiber.Ctx) error {
 	chain, err := entities.ChainFromString(ctx.Params("chain"))
 	if err != nil {
 		return ctx.Status(http.StatusNotFound).SendString(err.Error())
 	}
-	address := common.HexToAddress(ctx.Params("address"))
-	if !checkAddressValid(address) {
-		return ctx.Status(http.StatusBadRequest).SendString("invalid address")
+	address, err := s.resolveHolder(ctx)
+	if err != nil {
+		return holderFailed(ctx, err)
 	}
-	balance, err := s.serviceBalancer.GetNativeBalance(ctx.UserContext(), chain, address)
+	tag, err := entities.BlockTagFromString(ctx.Query("tag"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	if ctx.QueryBool("verified") {
+		balance, errVerify := s.serviceBalancer.GetVerifiedNativeBalance(ctx.UserContext(), chain, address, tag)
+		if errVerify != nil {
+			return verificationFailed(ctx, errVerify)
+		}
+		return ctx.JSON(s.withHolder(ctx, balance, address))
+	}
+	balance, err := s.serviceBalancer.GetNativeBalance(ctx.UserContext(), chain, address, tag)
 	if err != nil {
 		return err
 	}
-	return ctx.JSON(balance)
+	return ctx.JSON(s.withHolder(ctx, balance, address))
 }
 
 // getKnownTokenBalance gets the balance of a known token. returns 404 if the token is not known.
@@ -39,17 +58,102 @@ func (s *Server) getKnownTokenBalance(ctx *fiber.Ctx) error {
 	if _, err = entities.GetTokenAddress(chain, token); err != nil {
 		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
 	}
-	address := common.HexToAddress(ctx.Params("address"))
-	if !checkAddressValid(address) {
-		return ctx.Status(http.StatusBadRequest).SendString("invalid address")
+	address, err := s.resolveHolder(ctx)
+	if err != nil {
+		return holderFailed(ctx, err)
+	}
+	tag, err := entities.BlockTagFromString(ctx.Query("tag"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	if ctx.QueryBool("verified") {
+		balance, errVerify := s.serviceBalancer.GetVerifiedKnownTokenBalance(ctx.UserContext(), token, chain, address, tag)
+		if errVerify != nil {
+			return verificationFailed(ctx, errVerify)
+		}
+		return ctx.JSON(s.withHolder(ctx, balance, address))
 	}
-	balance, err := s.serviceBalancer.GetKnownTokenBalance(ctx.UserContext(), token, chain, address)
+	balance, err := s.serviceBalancer.GetKnownTokenBalance(ctx.UserContext(), token, chain, address, tag)
 	if err != nil {
 		return err
 	}
+	return ctx.JSON(s.withHolder(ctx, balance, address))
+}
+
+// getAssetBalance gets balance of canonical asset summed across chains with per-chain breakdown.
+// bridged variants are counted in canonical asset, i.e. /assets/lzageur/... returns agEUR total.
+func (s *Server) getAssetBalance(ctx *fiber.Ctx) error {
+	token, err := entities.TokenFromString(ctx.Params("asset"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	address, err := s.resolveHolder(ctx)
+	if err != nil {
+		return holderFailed(ctx, err)
+	}
+	tag, err := entities.BlockTagFromString(ctx.Query("tag"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	balance := s.serviceBalancer.GetAssetBalance(ctx.UserContext(), entities.CanonicalAsset(token), address, tag)
+	balance.HolderName = s.holderName(ctx, address)
 	return ctx.JSON(balance)
 }
 
-func checkAddressValid(address common.Address) bool {
-	return address.String() != "0x0000000000000000000000000000000000000000"
+// verificationFailed replies 400 for requests which can not be verified and 502 when rpc answers do not prove balance
+func verificationFailed(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, verifier.ErrUnverifiableTag) {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	return ctx.Status(http.StatusBadGateway).SendString(err.Error())
+}
+
+// getMultiTokenBalance gets ERC-1155 balances of an address for ids passed as comma separated `ids` query param.
+func (s *Server) getMultiTokenBalance(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	contract, err := s.parseAddress("contract", ctx.Params("contract"))
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	address, err := s.resolveHolder(ctx)
+	if err != nil {
+		return holderFailed(ctx, err)
+	}
+	ids, err := parseTokenIDs(ctx.Query("ids"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	tag, err := entities.BlockTagFromString(ctx.Query("tag"))
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	balance, err := s.serviceBalancer.GetMultiTokenBalances(ctx.UserContext(), chain, contract, address, ids, tag)
+	if err != nil {
+		return err
+	}
+	balance.HolderName = s.holderName(ctx, address)
+	return ctx.JSON(balance)
+}
+
+// parseTokenIDs parses comma separated list of decimal or 0x-prefixed hex token ids.
+func parseTokenIDs(raw string) ([]*big.Int, error) {
+	if raw == "" {
+		return nil, errors.New("ids are required")
+	}
+	parts := strings.Split(raw, ",")
+	if len(parts) > maxTokenIDs {
+		return nil, fmt.Errorf("too many ids, max %d", maxTokenIDs)
+	}
+	ids := make([]*big.Int, 0, len(parts))
+	for _, part := range parts {
+		id, ok := new(big.Int).SetString(strings.TrimSpace(part), 0)
+		if !ok || id.Sign() < 0 {
+			return nil, fmt.Errorf("invalid id: %s", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
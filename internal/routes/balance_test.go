@@ -32,6 +32,60 @@ func TestServer_GetNativeBalance(t *testing.T) {
 		t.Logf("balance is %s %s", response.TokenBalance, entities.MapChainToFuel(chain))
 	})
 
+	t.Run("finalized tag", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/balance/%s?tag=finalized", chain.String(), address))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.Balance
+		resp.RequireUnmarshal(t, &response)
+		require.NotEmpty(t, response.BlockTag)
+		t.Logf("balance is %s %s at %s", response.TokenBalance, entities.MapChainToFuel(chain), response.BlockTag)
+	})
+
+	t.Run("verified", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/balance/%s?verified=true", chain.String(), address))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.Balance
+		resp.RequireUnmarshal(t, &response)
+		require.True(t, response.Verified)
+		require.NotZero(t, response.BlockNumber)
+	})
+
+	t.Run("ens name", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/balance/vitalik.eth?ens=true", chain.String()))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.Balance
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, address, response.Holder)
+		require.Equal(t, "vitalik.eth", response.HolderName)
+	})
+
+	t.Run("invalid ens name", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/balance/vitalik..eth", chain.String()))
+		resp.RequireBadRequest(t)
+	})
+
+	t.Run("verified pending tag", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/balance/%s?verified=true&tag=pending", chain.String(), address))
+		resp.RequireBadRequest(t)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/balance/%s?tag=earliest", chain.String(), address))
+		resp.RequireBadRequest(t)
+	})
+
 	t.Run("unknown chain", func(t *testing.T) {
 		// when
 		resp := srv.Get(t, "/abc/balance/"+address)
@@ -56,6 +110,12 @@ func TestServer_GetKnownTokenBalanceByAddress(t *testing.T) {
 		t.Logf("balance is %s %s", response.TokenBalance, token)
 		t.Log("https://etherscan.io/tokenholdings?a=" + address)
 	})
+	t.Run("unknown tag", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/%s/balance/%s?tag=earliest", chain.String(), token, address))
+		// then
+		resp.RequireBadRequest(t)
+	})
 	t.Run("unknown chain", func(t *testing.T) {
 		// when
 		resp := srv.Get(t, fmt.Sprintf("/abc/%s/balance/%s", token, address))
@@ -69,3 +129,73 @@ func TestServer_GetKnownTokenBalanceByAddress(t *testing.T) {
 		resp.RequireServerError(t)
 	})
 }
+
+func TestServer_GetMultiTokenBalance(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+	contract := "0x495f947276749Ce646f68AC8c248420045cb7b5e" // opensea shared storefront
+
+	t.Run("known chain", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/erc1155/%s/balance/%s?ids=1,2", chain.String(), contract, address))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.MultiTokenBalance
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, entities.ChainEthereum, response.Chain)
+		require.Len(t, response.Tokens, 2)
+	})
+	t.Run("missing ids", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/erc1155/%s/balance/%s", chain.String(), contract, address))
+		// then
+		resp.RequireBadRequest(t)
+	})
+	t.Run("invalid ids", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/erc1155/%s/balance/%s?ids=1,abc", chain.String(), contract, address))
+		// then
+		resp.RequireBadRequest(t)
+	})
+	t.Run("unknown chain", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/abc/erc1155/%s/balance/%s?ids=1", contract, address))
+		// then
+		resp.RequireNotFound(t)
+	})
+}
+
+func TestServer_GetAssetBalance(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("bridged variant is counted in canonical asset", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, "/assets/lzageur/balance/"+address)
+		resp.RequireOk(t)
+
+		// then
+		var response entities.AssetBalance
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, entities.AgEUR, response.Asset)
+		for _, balance := range response.Chains {
+			require.Contains(t, []entities.Token{entities.AgEUR, entities.LZAgEUR}, balance.Token)
+		}
+		t.Logf("total is %s agEUR on %d chains, failed %v", response.TotalBalance, len(response.Chains), response.FailedChains)
+	})
+	t.Run("unknown asset", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, "/assets/abc/balance/"+address)
+		// then
+		resp.RequireNotFound(t)
+	})
+	t.Run("invalid address", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, "/assets/usdc/balance/0x0")
+		// then
+		resp.RequireBadRequest(t)
+	})
+}
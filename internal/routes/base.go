@@ -2,28 +2,66 @@ package routes
 
 import (
 	"altt/internal/logger"
+	"altt/internal/service/web3/allowance"
 	"altt/internal/service/web3/balancer"
+	"altt/internal/service/web3/ens"
+	"altt/internal/service/web3/gas"
+	"altt/internal/service/web3/streamer"
+	"altt/internal/service/web3/transactions"
+	"altt/internal/service/web3/transfers"
+	"altt/internal/service/web3/watchlist"
 
 	fiberprometheus "github.com/ansrivas/fiberprometheus/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/websocket/v2"
 	"go.uber.org/zap"
 )
 
 type Server struct {
-	appAddr         string
-	log             logger.AppLogger
-	serviceBalancer *balancer.Service
-	httpEngine      *fiber.App
+	appAddr             string
+	log                 logger.AppLogger
+	serviceBalancer     *balancer.Service
+	serviceStreamer     *streamer.Service
+	serviceWatchlist    *watchlist.Service
+	serviceTransfers    *transfers.Service
+	serviceAllowance    *allowance.Service
+	serviceENS          *ens.Service
+	serviceGas          *gas.Service
+	serviceTransactions *transactions.Service
+	httpEngine          *fiber.App
+	// lenientAddresses accepts addresses without checksum
+	lenientAddresses bool
 }
 
 // InitAppRouter initializes the HTTP Server.
-func InitAppRouter(log logger.AppLogger, serviceBalancer *balancer.Service, address string, disableMetrics bool) *Server {
+func InitAppRouter(
+	log logger.AppLogger,
+	serviceBalancer *balancer.Service,
+	serviceStreamer *streamer.Service,
+	serviceWatchlist *watchlist.Service,
+	serviceTransfers *transfers.Service,
+	serviceAllowance *allowance.Service,
+	serviceENS *ens.Service,
+	serviceGas *gas.Service,
+	serviceTransactions *transactions.Service,
+	address string,
+	disableMetrics bool,
+	lenientAddresses bool,
+) *Server {
 	app := &Server{
-		appAddr:         address,
-		httpEngine:      fiber.New(fiber.Config{}),
-		serviceBalancer: serviceBalancer,
-		log:             log.With(zap.String("service", "http")),
+		appAddr:             address,
+		httpEngine:          fiber.New(fiber.Config{}),
+		serviceBalancer:     serviceBalancer,
+		serviceStreamer:     serviceStreamer,
+		serviceWatchlist:    serviceWatchlist,
+		serviceTransfers:    serviceTransfers,
+		serviceAllowance:    serviceAllowance,
+		serviceENS:          serviceENS,
+		serviceGas:          serviceGas,
+		serviceTransactions: serviceTransactions,
+		lenientAddresses:    lenientAddresses,
+		log:                 log.With(zap.String("service", "http")),
 	}
 	app.httpEngine.Use(recover.New())
 	app.initRoutes(disableMetrics)
@@ -42,8 +80,26 @@ func (s *Server) initRoutes(disableMetrics bool) {
 	s.httpEngine.Get("/ready", func(ctx *fiber.Ctx) error {
 		return ctx.SendString("ok")
 	})
+	s.httpEngine.Get("/ws/balance", requireWebSocket, websocket.New(s.streamBalances))
+	s.httpEngine.Post("/watches", s.createWatch)
+	s.httpEngine.Get("/watches", s.getWatches)
+	s.httpEngine.Get("/watches/dead-letters", s.getWatchDeadLetters)
+	s.httpEngine.Get("/watches/:id", s.getWatch)
+	s.httpEngine.Delete("/watches/:id", s.deleteWatch)
+	s.httpEngine.Get("/assets/:asset/balance/:address", s.getAssetBalance)
+	s.httpEngine.Get("/exposures/:address", s.getExposures)
+	s.httpEngine.Get("/indexer/status", s.getIndexerStatus)
 	s.httpEngine.Get("/:chain/balance/:address", s.getNativeBalance)
+	s.httpEngine.Get("/:chain/gas", s.getGas)
+	s.httpEngine.Post("/:chain/fee", s.estimateFee)
+	s.httpEngine.Get("/:chain/tx/:hash", s.getTransaction)
+	s.httpEngine.Get("/:chain/account/:address", s.getAccountInfo)
 	s.httpEngine.Get("/:chain/:token/balance/:address", s.getKnownTokenBalance)
+	s.httpEngine.Get("/:chain/:token/transfers/:address", s.getTransfers)
+	s.httpEngine.Get("/:chain/token/:contract", s.getTokenInfo)
+	s.httpEngine.Get("/:chain/erc20/:contract/allowance/:owner/:spender", s.getContractAllowance)
+	s.httpEngine.Get("/:chain/:token/allowance/:owner/:spender", s.getAllowance)
+	s.httpEngine.Get("/:chain/erc1155/:contract/balance/:address", s.getMultiTokenBalance)
 }
 
 // Run starts the HTTP Server.
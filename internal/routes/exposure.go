@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getExposures gets current non-zero allowances given by an address on all available chains.
+// optional query params: `chain` to scan single chain and `from_block` (only together with `chain`) to scan from that block.
+func (s *Server) getExposures(ctx *fiber.Ctx) error {
+	holder, err := s.parseAddress("address", ctx.Params("address"))
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	chains := rpc.AvailableChains()
+	if raw := ctx.Query("chain"); raw != "" {
+		chain, errChain := entities.ChainFromString(raw)
+		if errChain != nil {
+			return ctx.Status(http.StatusNotFound).SendString(errChain.Error())
+		}
+		if !rpc.ChainAvailable(chain) {
+			return ctx.Status(http.StatusNotFound).SendString("chain is not available")
+		}
+		chains = []entities.Chain{chain}
+	}
+	var fromBlock uint64
+	if raw := ctx.Query("from_block"); raw != "" {
+		if ctx.Query("chain") == "" {
+			return ctx.Status(http.StatusBadRequest).SendString("from_block requires chain")
+		}
+		if fromBlock, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return ctx.Status(http.StatusBadRequest).SendString("invalid from_block")
+		}
+	}
+	return ctx.JSON(s.serviceAllowance.ScanExposures(ctx.UserContext(), holder, chains, fromBlock))
+}
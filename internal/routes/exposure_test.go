@@ -0,0 +1,42 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetExposures(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("single chain", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/exposures/%s?chain=%s", address, chain.String()))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.ExposureReport
+		resp.RequireUnmarshal(t, &response)
+		require.Empty(t, response.FailedChains)
+		require.Len(t, response.Scanned, 1)
+		require.Equal(t, chain, response.Scanned[0].Chain)
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/exposures/%s?chain=unknown", address)).RequireNotFound(t)
+	})
+
+	t.Run("from_block without chain", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/exposures/%s?from_block=100", address)).RequireBadRequest(t)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/exposures/%s", zeroAddress)).RequireBadRequest(t)
+		srv.Get(t, fmt.Sprintf("/exposures/%s?chain=%s&from_block=abc", address, chain.String())).RequireBadRequest(t)
+	})
+}
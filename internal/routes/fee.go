@@ -0,0 +1,105 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/gas"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+// estimateFeeRequest is either signed raw transaction or call fields, empty `to` means contract creation
+type estimateFeeRequest struct {
+	RawTx string `json:"raw_tx"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Data  string `json:"data"`
+	Value string `json:"value"`
+}
+
+// estimateFee returns total fee of transaction on chain. On optimism and arbitrum L1 data fee is included,
+// it is usually the bigger part of the fee.
+func (s *Server) estimateFee(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	var req estimateFeeRequest
+	if err = ctx.BodyParser(&req); err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString("invalid body")
+	}
+	var msg ethereum.CallMsg
+	if req.RawTx != "" {
+		msg, err = callFromRawTx(chain, req.RawTx)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+		}
+	} else if msg, err = s.callFromRequest(&req); err != nil {
+		// replies plain text for errors other than rejected address
+		return invalidAddress(ctx, err)
+	}
+	fee, err := s.serviceGas.EstimateFee(ctx.UserContext(), chain, msg)
+	if err != nil {
+		if errors.Is(err, gas.ErrChainNotAvailable) {
+			return ctx.Status(http.StatusNotFound).SendString(err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(fee)
+}
+
+func (s *Server) callFromRequest(req *estimateFeeRequest) (ethereum.CallMsg, error) {
+	var msg ethereum.CallMsg
+	if req.From != "" {
+		from, err := s.parseAddress("from", req.From)
+		if err != nil {
+			return msg, err
+		}
+		msg.From = from
+	}
+	if req.To != "" {
+		to, err := s.parseAddress("to", req.To)
+		if err != nil {
+			return msg, err
+		}
+		msg.To = &to
+	}
+	if req.Data != "" {
+		data, err := hexutil.Decode(req.Data)
+		if err != nil {
+			return msg, fmt.Errorf("invalid data: %s", err)
+		}
+		msg.Data = data
+	}
+	if req.Value != "" {
+		value, ok := new(big.Int).SetString(req.Value, 0)
+		if !ok || value.Sign() < 0 {
+			return msg, errors.New("invalid value")
+		}
+		msg.Value = value
+	}
+	return msg, nil
+}
+
+// callFromRawTx decodes signed transaction, sender is recovered from signature
+func callFromRawTx(chain entities.Chain, rawTx string) (ethereum.CallMsg, error) {
+	raw, err := hexutil.Decode(rawTx)
+	if err != nil {
+		return ethereum.CallMsg{}, fmt.Errorf("invalid raw_tx: %s", err)
+	}
+	var tx types.Transaction
+	if err = tx.UnmarshalBinary(raw); err != nil {
+		return ethereum.CallMsg{}, fmt.Errorf("invalid raw_tx: %s", err)
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(chain.ChainIDBI()), &tx)
+	if err != nil {
+		return ethereum.CallMsg{}, fmt.Errorf("invalid raw_tx signature: %s", err)
+	}
+	return ethereum.CallMsg{From: from, To: tx.To(), Value: tx.Value(), Data: tx.Data()}, nil
+}
@@ -0,0 +1,58 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"altt/internal/utils"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_EstimateFee(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+	path := fmt.Sprintf("/%s/fee", chain.String())
+
+	t.Run("native transfer", func(t *testing.T) {
+		// when
+		resp := srv.Post(t, path, map[string]string{"from": address, "to": address, "value": "1"})
+		resp.RequireOk(t)
+
+		// then
+		var response entities.FeeEstimate
+		resp.RequireUnmarshal(t, &response)
+		require.Empty(t, response.Rollup)
+		require.Equal(t, uint64(21_000), response.GasLimit)
+		require.Equal(t, "0", response.L1DataFee.Wei)
+		require.Equal(t, response.ExecutionFee.Wei, response.TotalFee.Wei)
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		srv.Post(t, "/unknown/fee", map[string]string{"to": spender}).RequireNotFound(t)
+	})
+
+	t.Run("chain without rpc", func(t *testing.T) {
+		srv.Post(t, fmt.Sprintf("/%s/fee", entities.ChainOptimism.String()), map[string]string{"to": spender}).RequireNotFound(t)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		// when
+		resp := srv.Post(t, path, map[string]string{"to": "0x1234"})
+		resp.RequireBadRequest(t)
+
+		// then
+		var response invalidAddressResponse
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, "to", response.Field)
+		require.Equal(t, utils.AddressReasonInvalidLength, response.Reason)
+	})
+
+	t.Run("invalid call", func(t *testing.T) {
+		srv.Post(t, path, map[string]string{"to": spender, "data": "0xzz"}).RequireBadRequest(t)
+		srv.Post(t, path, map[string]string{"to": spender, "value": "-1"}).RequireBadRequest(t)
+		srv.Post(t, path, map[string]string{"raw_tx": "0x1234"}).RequireBadRequest(t)
+	})
+}
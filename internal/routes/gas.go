@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/gas"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getGas gets base fee, recent priority fee percentiles and slow/normal/fast fee suggestions of chain.
+// chains without EIP-1559 have legacy `gas_price` only.
+func (s *Server) getGas(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	info, err := s.serviceGas.GetGasInfo(ctx.UserContext(), chain)
+	if err != nil {
+		if errors.Is(err, gas.ErrChainNotAvailable) {
+			return ctx.Status(http.StatusNotFound).SendString(err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(info)
+}
@@ -0,0 +1,38 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetGas(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("eip1559 chain", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/gas", chain.String()))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.GasInfo
+		resp.RequireUnmarshal(t, &response)
+		require.True(t, response.EIP1559)
+		require.NotNil(t, response.BaseFee)
+		require.NotNil(t, response.Suggestions)
+		require.NotEmpty(t, response.GasPrice.Wei)
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		srv.Get(t, "/unknown/gas").RequireNotFound(t)
+	})
+
+	t.Run("chain without rpc", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/%s/gas", entities.ChainPolygon.String())).RequireNotFound(t)
+	})
+}
@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/ens"
+	"altt/internal/utils"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// holderNameTimeout bounds primary name lookup, balance is returned without name when lookup takes longer
+const holderNameTimeout = 2 * time.Second
+
+// resolveHolder reads `address` param, which is hex address or ENS name resolved on ethereum mainnet
+func (s *Server) resolveHolder(ctx *fiber.Ctx) (common.Address, error) {
+	raw, err := url.PathUnescape(ctx.Params("address"))
+	if err != nil {
+		raw = ctx.Params("address")
+	}
+	if ens.IsName(raw) {
+		return s.serviceENS.Resolve(ctx.UserContext(), raw)
+	}
+	return s.parseAddress("address", raw)
+}
+
+// holderFailed replies 400 for invalid address or name, 404 for name without address and 502 when name can not be resolved
+func holderFailed(ctx *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrInvalidAddress):
+		return invalidAddress(ctx, err)
+	case errors.Is(err, ens.ErrInvalidName):
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	case errors.Is(err, ens.ErrNameNotFound):
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	return ctx.Status(http.StatusBadGateway).SendString(err.Error())
+}
+
+// holderName returns primary ENS name of holder when `ens=true` query param is set. Lookup is bounded by
+// holderNameTimeout, failed lookup only omits the name.
+func (s *Server) holderName(ctx *fiber.Ctx, holder common.Address) string {
+	if !ctx.QueryBool("ens") {
+		return ""
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx.UserContext(), holderNameTimeout)
+	defer cancel()
+	name, err := s.serviceENS.LookupAddress(lookupCtx, holder)
+	if err != nil && !errors.Is(err, ens.ErrUnavailable) {
+		s.log.Error("unable to lookup ens name", err, zap.String("address", holder.String()))
+	}
+	return name
+}
+
+// withHolder sets holder fields of balance, `ens=true` query param adds primary name of holder and `account=true`
+// classification of holder account, failed lookups only omit them
+func (s *Server) withHolder(ctx *fiber.Ctx, balance *entities.Balance, holder common.Address) *entities.Balance {
+	balance.Holder = holder.String()
+	balance.HolderName = s.holderName(ctx, holder)
+	if ctx.QueryBool("account") {
+		account, err := s.serviceBalancer.GetAccountInfo(ctx.UserContext(), balance.Chain, holder)
+		if err != nil {
+			s.log.Error("unable to get account info", err, zap.String("address", holder.String()))
+		}
+		balance.Account = account
+	}
+	return balance
+}
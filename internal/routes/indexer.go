@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// getIndexerStatus gets indexed head of chains followed by indexer with its age.
+// balances of `stale` chains are read from rpc until indexer catches up.
+func (s *Server) getIndexerStatus(ctx *fiber.Ctx) error {
+	return ctx.JSON(s.serviceBalancer.GetIndexerStatus())
+}
@@ -0,0 +1,24 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetIndexerStatus(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	// when
+	resp := srv.Get(t, "/indexer/status")
+	resp.RequireOk(t)
+
+	// then
+	var response []entities.IndexerStatus
+	resp.RequireUnmarshal(t, &response)
+	require.NotNil(t, response)
+}
@@ -0,0 +1,128 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/streamer"
+	"altt/internal/utils"
+	"errors"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	streamActionSubscribe   = "subscribe"
+	streamActionUnsubscribe = "unsubscribe"
+
+	streamMessageBalance      = "balance"
+	streamMessageSubscribed   = "subscribed"
+	streamMessageUnsubscribed = "unsubscribed"
+	streamMessageError        = "error"
+)
+
+// streamRequest is a message from client. Empty token means native coin of the chain.
+type streamRequest struct {
+	Action  string `json:"action"`
+	Chain   string `json:"chain"`
+	Token   string `json:"token,omitempty"`
+	Address string `json:"address"`
+}
+
+type streamMessage struct {
+	Type    string            `json:"type"`
+	Balance *entities.Balance `json:"balance,omitempty"`
+	Request *streamRequest    `json:"request,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	// Reason is machine-readable reason of rejected address
+	Reason utils.AddressReason `json:"reason,omitempty"`
+}
+
+// requireWebSocket rejects plain http requests to websocket endpoints
+func requireWebSocket(ctx *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(ctx) {
+		return fiber.ErrUpgradeRequired
+	}
+	return ctx.Next()
+}
+
+// streamBalances streams balance changes of subscribed (chain, token, address) tuples over websocket.
+func (s *Server) streamBalances(conn *websocket.Conn) {
+	session := s.serviceStreamer.NewSession()
+	defer session.Close()
+
+	var writeMU sync.Mutex
+	write := func(msg *streamMessage) {
+		writeMU.Lock()
+		defer writeMU.Unlock()
+		if err := conn.WriteJSON(msg); err != nil {
+			session.Close()
+		}
+	}
+	go func() {
+		for {
+			select {
+			case <-session.Done():
+				return
+			case balance := <-session.Updates():
+				write(&streamMessage{Type: streamMessageBalance, Balance: balance})
+			}
+		}
+	}()
+
+	for {
+		var req streamRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		sub, err := s.parseStreamRequest(&req)
+		if err != nil {
+			msg := &streamMessage{Type: streamMessageError, Request: &req, Error: err.Error()}
+			var addressErr *utils.AddressError
+			if errors.As(err, &addressErr) {
+				msg.Reason = addressErr.Reason
+			}
+			write(msg)
+			continue
+		}
+		switch req.Action {
+		case streamActionSubscribe:
+			err = session.Subscribe(sub, func() {
+				write(&streamMessage{Type: streamMessageSubscribed, Request: &req})
+			})
+			if err != nil {
+				s.log.Info("stream subscription rejected", zap.String("reason", err.Error()))
+				write(&streamMessage{Type: streamMessageError, Request: &req, Error: err.Error()})
+			}
+		case streamActionUnsubscribe:
+			session.Unsubscribe(sub)
+			write(&streamMessage{Type: streamMessageUnsubscribed, Request: &req})
+		}
+	}
+}
+
+func (s *Server) parseStreamRequest(req *streamRequest) (streamer.Subscription, error) {
+	if req.Action != streamActionSubscribe && req.Action != streamActionUnsubscribe {
+		return streamer.Subscription{}, errors.New("unknown action")
+	}
+	chain, err := entities.ChainFromString(req.Chain)
+	if err != nil {
+		return streamer.Subscription{}, err
+	}
+	var token entities.Token
+	if req.Token != "" {
+		if token, err = entities.TokenFromString(req.Token); err != nil {
+			return streamer.Subscription{}, err
+		}
+	}
+	address, err := s.parseAddress("address", req.Address)
+	if err != nil {
+		return streamer.Subscription{}, err
+	}
+	return streamer.Subscription{
+		Chain:   chain,
+		Token:   token,
+		Address: address,
+	}, nil
+}
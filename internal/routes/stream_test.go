@@ -0,0 +1,47 @@
+package routes_test
+
+import (
+	testhelpers "altt/internal/test_helpers"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type streamMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+func TestServer_StreamBalances(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("plain http request", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, "/ws/balance")
+		// then
+		resp.RequireStatus(t, 426)
+	})
+	t.Run("invalid subscriptions", func(t *testing.T) {
+		conn := srv.Dial(t, "/ws/balance")
+		table := map[string]map[string]string{
+			"unknown action":     {"action": "watch", "chain": chain.String(), "address": address},
+			"unknown chain":      {"action": "subscribe", "chain": "abc", "address": address},
+			"unknown token":      {"action": "subscribe", "chain": chain.String(), "token": "abc", "address": address},
+			"disabled chain":     {"action": "subscribe", "chain": "optimism", "address": address},
+			"invalid address":    {"action": "subscribe", "chain": chain.String(), "address": "0x0"},
+			"token not on chain": {"action": "subscribe", "chain": chain.String(), "token": "BTC_b", "address": address},
+		}
+		for name, req := range table {
+			// when
+			require.NoError(t, conn.WriteJSON(req))
+
+			// then
+			var msg streamMessage
+			require.NoError(t, conn.ReadJSON(&msg))
+			require.Equal(t, "error", msg.Type, name)
+			require.NotEmpty(t, msg.Error, name)
+		}
+	})
+}
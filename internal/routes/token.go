@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/balancer"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// getTokenInfo gets name, symbol, decimals and total supply of erc20 contract. returns 404 if address has no code.
+// known tokens have `registry` with result of comparison with token registry.
+func (s *Server) getTokenInfo(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	contract, err := s.parseAddress("contract", ctx.Params("contract"))
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	info, err := s.serviceBalancer.GetTokenInfo(ctx.UserContext(), chain, contract)
+	if err != nil {
+		if errors.Is(err, balancer.ErrNotContract) {
+			return ctx.Status(http.StatusNotFound).SendString(err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(info)
+}
@@ -0,0 +1,40 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetTokenInfo(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("known token", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/token/%s", chain.String(), usdcContract))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.TokenInfo
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, "USDC", response.Symbol)
+		require.Equal(t, uint8(6), response.Decimals)
+		require.NotEmpty(t, response.TotalSupplyWei)
+		require.NotNil(t, response.Registry)
+		require.True(t, response.Registry.SymbolMatch)
+		require.True(t, response.Registry.DecimalsMatch)
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/unknown/token/%s", usdcContract)).RequireNotFound(t)
+	})
+
+	t.Run("invalid contract", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/%s/token/%s", chain.String(), zeroAddress)).RequireBadRequest(t)
+	})
+}
@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/transfers"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultTransfersLimit = 50
+	maxTransfersLimit     = 200
+)
+
+// getTransfers gets token transfers of an address, newest first.
+// optional query params: `direction` (in, out or all), `from_block`, `to_block`, `limit` and `cursor` from next_cursor of previous page.
+func (s *Server) getTransfers(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	token, err := entities.TokenFromString(ctx.Params("token"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	if entities.IsFuel(chain, token) {
+		return ctx.Status(http.StatusBadRequest).SendString(transfers.ErrNativeToken.Error())
+	}
+	if _, err = entities.GetTokenAddress(chain, token); err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	address, err := s.parseAddress("address", ctx.Params("address"))
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	query, err := parseTransferQuery(ctx)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	page, err := s.serviceTransfers.GetTransfers(ctx.UserContext(), chain, token, address, query)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(page)
+}
+
+func parseTransferQuery(ctx *fiber.Ctx) (entities.TransferQuery, error) {
+	query := entities.TransferQuery{Limit: defaultTransfersLimit}
+	direction, err := entities.TransferDirectionFromString(ctx.Query("direction"))
+	if err != nil {
+		return query, err
+	}
+	query.Direction = direction
+	if raw := ctx.Query("cursor"); raw != "" {
+		if query.Cursor, err = entities.TransferCursorFromString(raw); err != nil {
+			return query, err
+		}
+	}
+	if raw := ctx.Query("from_block"); raw != "" {
+		if query.FromBlock, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return query, errors.New("invalid from_block")
+		}
+	}
+	if raw := ctx.Query("to_block"); raw != "" {
+		if query.ToBlock, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			return query, errors.New("invalid to_block")
+		}
+	}
+	if query.ToBlock != 0 && query.FromBlock > query.ToBlock {
+		return query, errors.New("from_block is after to_block")
+	}
+	if raw := ctx.Query("limit"); raw != "" {
+		limit, errLimit := strconv.Atoi(raw)
+		if errLimit != nil || limit <= 0 || limit > maxTransfersLimit {
+			return query, errors.New("limit must be between 1 and " + strconv.Itoa(maxTransfersLimit))
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
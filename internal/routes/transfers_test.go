@@ -0,0 +1,48 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_GetTransfers(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+
+	t.Run("known token", func(t *testing.T) {
+		// when
+		resp := srv.Get(t, fmt.Sprintf("/%s/%s/transfers/%s?limit=5&direction=in", chain.String(), token, address))
+		resp.RequireOk(t)
+
+		// then
+		var response entities.TransferPage
+		resp.RequireUnmarshal(t, &response)
+		require.Equal(t, entities.ChainEthereum, response.Chain)
+		require.LessOrEqual(t, len(response.Transfers), 5)
+		for _, transfer := range response.Transfers {
+			require.Equal(t, entities.TransferDirectionIn, transfer.Direction)
+		}
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		resp := srv.Get(t, fmt.Sprintf("/unknown/%s/transfers/%s", token, address))
+		resp.RequireNotFound(t)
+	})
+
+	t.Run("native token", func(t *testing.T) {
+		resp := srv.Get(t, fmt.Sprintf("/%s/%s/transfers/%s", chain.String(), entities.MapChainToFuel(chain), address))
+		resp.RequireBadRequest(t)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		for _, query := range []string{"direction=sideways", "cursor=abc", "limit=0", "limit=1000", "from_block=10&to_block=5"} {
+			resp := srv.Get(t, fmt.Sprintf("/%s/%s/transfers/%s?%s", chain.String(), token, address, query))
+			resp.RequireBadRequest(t)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/transactions"
+	"errors"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gofiber/fiber/v2"
+)
+
+var errInvalidTxHash = errors.New("invalid transaction hash")
+
+// getTransaction gets status of transaction with decoded erc20 Transfer and Approval events.
+// Transaction from mempool has `pending` status, unknown hash is 404.
+func (s *Server) getTransaction(ctx *fiber.Ctx) error {
+	chain, err := entities.ChainFromString(ctx.Params("chain"))
+	if err != nil {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	raw, err := hexutil.Decode(ctx.Params("hash"))
+	if err != nil || len(raw) != common.HashLength {
+		return ctx.Status(http.StatusBadRequest).SendString(errInvalidTxHash.Error())
+	}
+	info, err := s.serviceTransactions.GetTransaction(ctx.UserContext(), chain, common.BytesToHash(raw))
+	if err != nil {
+		if errors.Is(err, transactions.ErrChainNotAvailable) || errors.Is(err, transactions.ErrTxNotFound) {
+			return ctx.Status(http.StatusNotFound).SendString(err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(info)
+}
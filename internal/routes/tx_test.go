@@ -0,0 +1,34 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestServer_GetTransaction(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+	unknownHash := "0x" + strings.Repeat("0", 63) + "1"
+
+	t.Run("unknown hash", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/%s/tx/%s", chain.String(), unknownHash)).RequireNotFound(t)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		for _, hash := range []string{"123", "0x1234", unknownHash + "00", "0x" + strings.Repeat("z", 64)} {
+			srv.Get(t, fmt.Sprintf("/%s/tx/%s", chain.String(), hash)).RequireBadRequest(t)
+		}
+	})
+
+	t.Run("unknown chain", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/unknown/tx/%s", unknownHash)).RequireNotFound(t)
+	})
+
+	t.Run("chain without rpc", func(t *testing.T) {
+		srv.Get(t, fmt.Sprintf("/%s/tx/%s", entities.ChainPolygon.String(), unknownHash)).RequireNotFound(t)
+	})
+}
@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/watchlist"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type createWatchRequest struct {
+	Chain      string `json:"chain"`
+	Token      string `json:"token"`
+	Address    string `json:"address"`
+	Condition  string `json:"condition"`
+	Threshold  string `json:"threshold"`
+	WebhookURL string `json:"webhook_url"`
+}
+
+// createWatch registers balance watch. Response contains webhook secret, it is not returned anymore after that.
+func (s *Server) createWatch(ctx *fiber.Ctx) error {
+	var req createWatchRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString("invalid body")
+	}
+	chain, err := entities.ChainFromString(req.Chain)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	var token entities.Token
+	if req.Token != "" {
+		if token, err = entities.TokenFromString(req.Token); err != nil {
+			return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+		}
+	}
+	address, err := s.parseAddress("address", req.Address)
+	if err != nil {
+		return invalidAddress(ctx, err)
+	}
+	condition, err := entities.WatchConditionFromString(req.Condition)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	watch, err := s.serviceWatchlist.AddWatch(&entities.Watch{
+		Chain:      chain,
+		Token:      token,
+		Address:    address.String(),
+		Condition:  condition,
+		Threshold:  req.Threshold,
+		WebhookURL: req.WebhookURL,
+	})
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+	}
+	return ctx.Status(http.StatusCreated).JSON(watch)
+}
+
+func (s *Server) getWatches(ctx *fiber.Ctx) error {
+	return ctx.JSON(s.serviceWatchlist.GetWatches())
+}
+
+func (s *Server) getWatch(ctx *fiber.Ctx) error {
+	watch, err := s.serviceWatchlist.GetWatch(ctx.Params("id"))
+	if errors.Is(err, watchlist.ErrWatchNotFound) {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(watch)
+}
+
+func (s *Server) deleteWatch(ctx *fiber.Ctx) error {
+	err := s.serviceWatchlist.DeleteWatch(ctx.Params("id"))
+	if errors.Is(err, watchlist.ErrWatchNotFound) {
+		return ctx.Status(http.StatusNotFound).SendString(err.Error())
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (s *Server) getWatchDeadLetters(ctx *fiber.Ctx) error {
+	return ctx.JSON(s.serviceWatchlist.GetDeadLetters())
+}
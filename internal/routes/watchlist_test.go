@@ -0,0 +1,75 @@
+package routes_test
+
+import (
+	"altt/internal/entities"
+	testhelpers "altt/internal/test_helpers"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Watches(t *testing.T) {
+	// given
+	tCtx := testhelpers.GetClean(t)
+	srv := testhelpers.NewTestServer(t, tCtx)
+	request := map[string]string{
+		"chain":       chain.String(),
+		"token":       string(token),
+		"address":     address,
+		"condition":   "below",
+		"threshold":   "100.5",
+		"webhook_url": "https://example.com/hook",
+	}
+
+	// when
+	resp := srv.Post(t, "/watches", request)
+	resp.RequireCreated(t)
+	var created entities.Watch
+	resp.RequireUnmarshal(t, &created)
+
+	// then
+	require.NotEmpty(t, created.ID)
+	require.NotEmpty(t, created.Secret)
+	require.Equal(t, entities.WatchConditionBelow, created.Condition)
+
+	t.Run("list", func(t *testing.T) {
+		resp := srv.Get(t, "/watches")
+		resp.RequireOk(t)
+		var watches []entities.Watch
+		resp.RequireUnmarshal(t, &watches)
+		require.Len(t, watches, 1)
+		require.Equal(t, created.ID, watches[0].ID)
+		require.Empty(t, watches[0].Secret)
+	})
+	t.Run("get", func(t *testing.T) {
+		resp := srv.Get(t, "/watches/"+created.ID)
+		resp.RequireOk(t)
+		var watch entities.Watch
+		resp.RequireUnmarshal(t, &watch)
+		require.Equal(t, created.ID, watch.ID)
+		require.Empty(t, watch.Secret)
+	})
+	t.Run("invalid watches", func(t *testing.T) {
+		table := map[string]map[string]string{
+			"unknown chain":     {"chain": "abc", "address": address, "condition": "any_change", "webhook_url": "https://example.com"},
+			"disabled chain":    {"chain": "optimism", "address": address, "condition": "any_change", "webhook_url": "https://example.com"},
+			"unknown condition": {"chain": chain.String(), "address": address, "condition": "equal", "webhook_url": "https://example.com"},
+			"missing threshold": {"chain": chain.String(), "address": address, "condition": "above", "webhook_url": "https://example.com"},
+			"invalid webhook":   {"chain": chain.String(), "address": address, "condition": "any_change", "webhook_url": "example.com"},
+			"invalid address":   {"chain": chain.String(), "address": "0x0", "condition": "any_change", "webhook_url": "https://example.com"},
+		}
+		for name, req := range table {
+			resp := srv.Post(t, "/watches", req)
+			require.Equal(t, 400, resp.Res.StatusCode, name)
+		}
+	})
+	t.Run("dead letters", func(t *testing.T) {
+		resp := srv.Get(t, "/watches/dead-letters")
+		resp.RequireOk(t)
+	})
+	t.Run("delete", func(t *testing.T) {
+		srv.Delete(t, "/watches/"+created.ID).RequireNoContent(t)
+		srv.Get(t, "/watches/"+created.ID).RequireNotFound(t)
+		srv.Delete(t, "/watches/"+created.ID).RequireNotFound(t)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"container/list"
 	"errors"
 	"log"
+	"sort"
 	"sync"
 
 	"go.uber.org/zap"
@@ -47,6 +48,18 @@ func NewService(rpcEndpoints map[string][]string) {
 	}
 }
 
+// AvailableChains returns chains with configured rpc endpoints in chain id order
+func AvailableChains() []entities.Chain {
+	chains := make([]entities.Chain, 0, len(s.configuredChains))
+	for chain := range s.configuredChains {
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i] < chains[j]
+	})
+	return chains
+}
+
 func ChainAvailable(chain entities.Chain) bool {
 	_, ok := s.configuredChains[chain]
 	return ok
@@ -71,3 +84,11 @@ func GetRPC(chain entities.Chain) (string, error) {
 	s.usage[chain].MoveToBack(e)
 	return e.Value.(string), nil
 }
+
+// GetRPCs returns all configured endpoints of chain, used when answers of independent nodes have to be compared
+func GetRPCs(chain entities.Chain) []string {
+	if s == nil {
+		log.Fatal("rpc service not initialized")
+	}
+	return append([]string(nil), s.rpcs[chain]...)
+}
@@ -0,0 +1,239 @@
+package allowance
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/approver"
+	"altt/internal/utils"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+type approvalPair struct {
+	contract common.Address
+	spender  common.Address
+}
+
+// ScanExposures finds current non-zero allowances given by holder on chains. Approval logs of holder are read from
+// fromBlock, or from blocks of last `allowance.scan_period` when fromBlock is 0, and allowance of every approved
+// (contract, spender) pair is read again. Chains which failed are listed in FailedChains, pairs which allowance
+// could not be read in FailedPairs.
+func (s *Service) ScanExposures(ctx context.Context, holder common.Address, chains []entities.Chain, fromBlock uint64) *entities.ExposureReport {
+	exposures := make([][]entities.Exposure, len(chains))
+	failed := make([][]entities.FailedExposure, len(chains))
+	scans := make([]*entities.ExposureScan, len(chains))
+	var wg sync.WaitGroup
+	for i := range chains {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			exposures[i], failed[i], scans[i], err = s.scanChain(ctx, chains[i], holder, fromBlock)
+			if err != nil {
+				s.log.Error("unable to scan approvals", err, zap.String("chain", chains[i].String()), zap.String("holder", holder.String()))
+				scans[i] = nil
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	result := &entities.ExposureReport{
+		Holder:    holder.String(),
+		Exposures: make([]entities.Exposure, 0),
+		Scanned:   make([]entities.ExposureScan, 0, len(chains)),
+	}
+	for i, scan := range scans {
+		if scan == nil {
+			result.FailedChains = append(result.FailedChains, chains[i].String())
+			continue
+		}
+		result.Scanned = append(result.Scanned, *scan)
+		result.Exposures = append(result.Exposures, exposures[i]...)
+		result.FailedPairs = append(result.FailedPairs, failed[i]...)
+	}
+	return result
+}
+
+func (s *Service) scanChain(
+	ctx context.Context,
+	chain entities.Chain,
+	holder common.Address,
+	fromBlock uint64,
+) ([]entities.Exposure, []entities.FailedExposure, *entities.ExposureScan, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, nil, nil, ErrChainNotAvailable
+	}
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer client.Close()
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to get latest block: %w", err)
+	}
+	toBlock := latest.Number.Uint64()
+	if fromBlock == 0 {
+		scanBlocks, errWindow := s.scanWindow(ctx, client, latest)
+		if errWindow != nil {
+			return nil, nil, nil, errWindow
+		}
+		if toBlock >= scanBlocks {
+			fromBlock = toBlock - scanBlocks + 1
+		}
+	}
+	scan := &entities.ExposureScan{Chain: chain, ChainName: chain.String(), FromBlock: fromBlock, ToBlock: toBlock}
+	if fromBlock > toBlock {
+		return []entities.Exposure{}, nil, scan, nil
+	}
+
+	approvals := make([]*approver.Erc20Approval, 0)
+	err = s.ranges.Walk(ctx, chain, fromBlock, toBlock, func(ctx context.Context, start, end uint64) error {
+		found, errFilter := s.erc20.FilterApprovals(ctx, client, holder, start, end)
+		if errFilter != nil {
+			return errFilter
+		}
+		approvals = append(approvals, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	exposures, failed := s.collectExposures(chain, latestApprovals(approvals), func(pair approvalPair, approvedAt uint64) (*entities.Exposure, error) {
+		return s.readExposure(ctx, client, chain, holder, pair, approvedAt)
+	})
+	return exposures, failed, scan, nil
+}
+
+// collectExposures reads current allowance of every pair with non-zero latest approval. Pair which allowance could
+// not be read is reported as failed, so one broken token contract does not hide other approvals of chain.
+func (s *Service) collectExposures(
+	chain entities.Chain,
+	approvals map[approvalPair]*approver.Erc20Approval,
+	read func(pair approvalPair, approvedAt uint64) (*entities.Exposure, error),
+) ([]entities.Exposure, []entities.FailedExposure) {
+	exposures := make([]entities.Exposure, 0, len(approvals))
+	failed := make([]entities.FailedExposure, 0)
+	for _, pair := range sortedPairs(approvals) {
+		approval := approvals[pair]
+		if approval.Value.Sign() == 0 {
+			continue
+		}
+		exposure, err := read(pair, approval.Raw.BlockNumber)
+		if err != nil {
+			s.log.Error("unable to read exposure", err, zap.String("chain", chain.String()), zap.String("contract", pair.contract.String()))
+			failed = append(failed, entities.FailedExposure{
+				Chain:     chain,
+				ChainName: chain.String(),
+				Contract:  pair.contract.String(),
+				Spender:   pair.spender.String(),
+				Error:     err.Error(),
+			})
+			continue
+		}
+		if exposure != nil {
+			exposures = append(exposures, *exposure)
+		}
+	}
+	return exposures, failed
+}
+
+// scanWindow returns number of blocks produced on chain during scan period, block time is estimated from recent blocks.
+// Fixed `allowance.scan_blocks` is used when it is configured.
+func (s *Service) scanWindow(ctx context.Context, client *ethclient.Client, latest *types.Header) (uint64, error) {
+	if s.scanBlocks > 0 {
+		return s.scanBlocks, nil
+	}
+	if latest.Number.Uint64() <= blockTimeSample {
+		return latest.Number.Uint64() + 1, nil
+	}
+	sample, err := client.HeaderByNumber(ctx, new(big.Int).Sub(latest.Number, big.NewInt(blockTimeSample)))
+	if err != nil {
+		return 0, fmt.Errorf("unable to get sample header: %w", err)
+	}
+	return blocksInPeriod(latest.Time-sample.Time, blockTimeSample, s.scanPeriod), nil
+}
+
+// blocksInPeriod returns number of blocks produced during period when sample blocks took given number of seconds
+func blocksInPeriod(sampleSeconds, sampleBlocks uint64, period time.Duration) uint64 {
+	if sampleSeconds == 0 {
+		sampleSeconds = 1
+	}
+	return uint64(math.Ceil(period.Seconds() * float64(sampleBlocks) / float64(sampleSeconds)))
+}
+
+// readExposure reads current allowance of pair, nil is returned when it is already spent or revoked
+func (s *Service) readExposure(
+	ctx context.Context,
+	client *ethclient.Client,
+	chain entities.Chain,
+	holder common.Address,
+	pair approvalPair,
+	approvedAt uint64,
+) (*entities.Exposure, error) {
+	value, err := s.erc20.GetAllowance(ctx, client, pair.contract, holder, pair.spender)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read allowance of %s: %w", pair.contract.String(), err)
+	}
+	if value.Sign() == 0 {
+		return nil, nil
+	}
+	exposure := &entities.Exposure{
+		Allowance:       *s.newAllowance(chain, pair.contract, holder, pair.spender, value),
+		SpenderLabel:    s.SpenderLabel(chain, pair.spender),
+		ApprovedAtBlock: approvedAt,
+	}
+	if token, ok := entities.GetTokenByAddress(chain, pair.contract); ok {
+		exposure.Token = token
+		exposure.Allowance.Allowance = entities.CoinFromWEI(token, value)
+		return exposure, nil
+	}
+	metadata, err := s.balancer.GetTokenMetadata(ctx, chain, pair.contract)
+	if err != nil {
+		s.log.Error("unable to get token metadata, allowance is not formatted", err, zap.String("contract", pair.contract.String()))
+		return exposure, nil
+	}
+	exposure.Allowance.Allowance = utils.CustomFromWei(value, int(metadata.Decimals))
+	return exposure, nil
+}
+
+// latestApprovals keeps the latest Approval log of every (contract, spender) pair, it sets current allowance
+func latestApprovals(approvals []*approver.Erc20Approval) map[approvalPair]*approver.Erc20Approval {
+	result := make(map[approvalPair]*approver.Erc20Approval)
+	for _, approval := range approvals {
+		pair := approvalPair{contract: approval.Raw.Address, spender: approval.Spender}
+		current, ok := result[pair]
+		if ok && (current.Raw.BlockNumber > approval.Raw.BlockNumber ||
+			current.Raw.BlockNumber == approval.Raw.BlockNumber && current.Raw.Index > approval.Raw.Index) {
+			continue
+		}
+		result[pair] = approval
+	}
+	return result
+}
+
+func sortedPairs(approvals map[approvalPair]*approver.Erc20Approval) []approvalPair {
+	pairs := make([]approvalPair, 0, len(approvals))
+	for pair := range approvals {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].contract != pairs[j].contract {
+			return pairs[i].contract.Hex() < pairs[j].contract.Hex()
+		}
+		return pairs[i].spender.Hex() < pairs[j].spender.Hex()
+	})
+	return pairs
+}
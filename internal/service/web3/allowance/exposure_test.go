@@ -0,0 +1,115 @@
+package allowance
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/web3/approver"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestApprovals(t *testing.T) {
+	// given
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	router := common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45")
+	permit := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	approval := func(spender common.Address, value int64, block uint64, index uint) *approver.Erc20Approval {
+		return &approver.Erc20Approval{Spender: spender, Value: big.NewInt(value), Raw: types.Log{Address: token, BlockNumber: block, Index: index}}
+	}
+
+	// when logs come in walk order: newer chunk first, ascending inside chunk
+	latest := latestApprovals([]*approver.Erc20Approval{
+		approval(router, 0, 200, 1),
+		approval(router, 5, 200, 0),
+		approval(permit, 7, 150, 3),
+		approval(router, 100, 10, 0),
+		approval(permit, 1, 20, 0),
+	})
+
+	// then
+	require.Len(t, latest, 2)
+	require.Equal(t, int64(0), latest[approvalPair{contract: token, spender: router}].Value.Int64())
+	require.Equal(t, int64(7), latest[approvalPair{contract: token, spender: permit}].Value.Int64())
+	require.Equal(t, []approvalPair{{contract: token, spender: permit}, {contract: token, spender: router}}, sortedPairs(latest))
+}
+
+func TestService_CollectExposures(t *testing.T) {
+	// given approvals of three pairs, allowance of one of them can not be read
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := &Service{log: appLog}
+	broken := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	dai := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	router := common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45")
+	approvals := map[approvalPair]*approver.Erc20Approval{}
+	for i, contract := range []common.Address{broken, usdc, dai} {
+		approvals[approvalPair{contract: contract, spender: router}] = &approver.Erc20Approval{
+			Spender: router,
+			Value:   big.NewInt(1),
+			Raw:     types.Log{Address: contract, BlockNumber: uint64(10 + i)},
+		}
+	}
+
+	// when
+	exposures, failed := srv.collectExposures(entities.ChainEthereum, approvals, func(pair approvalPair, approvedAt uint64) (*entities.Exposure, error) {
+		if pair.contract == broken {
+			return nil, errors.New("execution reverted")
+		}
+		return &entities.Exposure{Allowance: entities.Allowance{Contract: pair.contract.String()}, ApprovedAtBlock: approvedAt}, nil
+	})
+
+	// then other pairs are still reported
+	require.Len(t, exposures, 2)
+	require.Equal(t, []entities.FailedExposure{{
+		Chain:     entities.ChainEthereum,
+		ChainName: "eth",
+		Contract:  broken.String(),
+		Spender:   router.String(),
+		Error:     "execution reverted",
+	}}, failed)
+}
+
+func TestBlocksInPeriod(t *testing.T) {
+	period := 180 * 24 * time.Hour
+	require.Equal(t, uint64(1_296_000), blocksInPeriod(12_000, 1_000, period)) // ethereum, 12s blocks
+	require.Equal(t, uint64(7_776_000), blocksInPeriod(2_000, 1_000, period))  // polygon, 2s blocks
+	require.Equal(t, uint64(62_208_000), blocksInPeriod(250, 1_000, period))   // arbitrum, 0.25s blocks
+	require.Equal(t, uint64(15_552_000_000), blocksInPeriod(0, 1_000, period)) // same timestamps
+}
+
+func TestService_SpenderLabel(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	router := common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45")
+	vault := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	t.Run("configured labels override built-in", func(t *testing.T) {
+		srv, err := NewService(appLog, approver.InitService(appLog, nil), nil, config.AllowanceConfig{
+			SpenderLabels: []config.SpenderLabelConfig{
+				{Chain: "eth", Address: vault.String(), Label: "Treasury vault"},
+				{Chain: "eth", Address: router.String(), Label: "Router"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Treasury vault", srv.SpenderLabel(entities.ChainEthereum, vault))
+		require.Equal(t, "Router", srv.SpenderLabel(entities.ChainEthereum, router))
+		require.Equal(t, "Uniswap V3 Router 2", srv.SpenderLabel(entities.ChainPolygon, router))
+		require.Equal(t, "Uniswap Permit2", srv.SpenderLabel(entities.ChainPolygon, common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")))
+		require.Empty(t, srv.SpenderLabel(entities.ChainPolygon, vault))
+	})
+
+	t.Run("invalid label", func(t *testing.T) {
+		for _, label := range []config.SpenderLabelConfig{{Chain: "unknown", Address: vault.String()}, {Chain: "eth", Address: "0x12"}} {
+			_, err := NewService(appLog, approver.InitService(appLog, nil), nil, config.AllowanceConfig{SpenderLabels: []config.SpenderLabelConfig{label}})
+			require.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package allowance
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/approver"
+	"altt/internal/service/web3/balancer"
+	"altt/internal/service/web3/logrange"
+	"altt/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultScanPeriod = 180 * 24 * time.Hour
+	// blockTimeSample is number of recent blocks used to estimate block time of chain
+	blockTimeSample          = 1_000
+	defaultInitialBlockRange = 5_000
+	defaultMaxBlockRange     = 100_000
+)
+
+// defaultUnlimitedThreshold is 2^96-1, treated as infinite approval by UNI, COMP and similar tokens
+var defaultUnlimitedThreshold = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1))
+
+var (
+	ErrNativeToken       = errors.New("native coin has no allowances")
+	ErrChainNotAvailable = errors.New("chain is not available")
+)
+
+// Service reads erc20 allowances and flags effectively unlimited ones
+type Service struct {
+	log        logger.AppLogger
+	erc20      *approver.Service
+	balancer   *balancer.Service
+	threshold  *big.Int
+	scanPeriod time.Duration
+	scanBlocks uint64
+	ranges     *logrange.Ranges
+	labels     map[entities.Chain]map[common.Address]string
+}
+
+func NewService(log logger.AppLogger, erc20 *approver.Service, balancerService *balancer.Service, conf config.AllowanceConfig) (*Service, error) {
+	threshold := defaultUnlimitedThreshold
+	if conf.UnlimitedThreshold != "" {
+		var ok bool
+		if threshold, ok = new(big.Int).SetString(conf.UnlimitedThreshold, 0); !ok || threshold.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid unlimited allowance threshold: %s", conf.UnlimitedThreshold)
+		}
+	}
+	labels := make(map[entities.Chain]map[common.Address]string, len(conf.SpenderLabels))
+	for _, label := range conf.SpenderLabels {
+		chain, err := entities.ChainFromString(label.Chain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spender label chain: %w", err)
+		}
+		if !common.IsHexAddress(label.Address) {
+			return nil, fmt.Errorf("invalid spender label address: %s", label.Address)
+		}
+		if _, ok := labels[chain]; !ok {
+			labels[chain] = make(map[common.Address]string)
+		}
+		labels[chain][common.HexToAddress(label.Address)] = label.Label
+	}
+	if conf.ScanPeriod <= 0 {
+		conf.ScanPeriod = defaultScanPeriod
+	}
+	log = log.With(zap.String("service", "allowance"))
+	return &Service{
+		log:        log,
+		erc20:      erc20,
+		balancer:   balancerService,
+		threshold:  threshold,
+		scanPeriod: conf.ScanPeriod,
+		scanBlocks: conf.ScanBlocks,
+		ranges:     logrange.New(log, defaultInitialBlockRange, defaultMaxBlockRange),
+		labels:     labels,
+	}, nil
+}
+
+// SpenderLabel returns configured label of spender, built-in one otherwise
+func (s *Service) SpenderLabel(chain entities.Chain, spender common.Address) string {
+	if label, ok := s.labels[chain][spender]; ok {
+		return label
+	}
+	return entities.GetSpenderLabel(chain, spender)
+}
+
+// GetAllowance returns allowance of known token
+func (s *Service) GetAllowance(ctx context.Context, chain entities.Chain, token entities.Token, owner, spender common.Address) (*entities.Allowance, error) {
+	if entities.IsFuel(chain, token) {
+		return nil, ErrNativeToken
+	}
+	tokenAddress, err := entities.GetTokenAddress(chain, token)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.readAllowance(ctx, chain, tokenAddress, owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	result := s.newAllowance(chain, tokenAddress, owner, spender, value)
+	result.Token = token
+	result.Allowance = entities.CoinFromWEI(token, value)
+	return result, nil
+}
+
+// GetContractAllowance returns allowance of arbitrary erc20 contract, decimals for formatted value are read from contract
+func (s *Service) GetContractAllowance(ctx context.Context, chain entities.Chain, contract, owner, spender common.Address) (*entities.Allowance, error) {
+	value, err := s.readAllowance(ctx, chain, contract, owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	result := s.newAllowance(chain, contract, owner, spender, value)
+	metadata, err := s.balancer.GetTokenMetadata(ctx, chain, contract)
+	if err != nil {
+		s.log.Error("unable to get token metadata, allowance is not formatted", err, zap.String("contract", contract.String()))
+		return result, nil
+	}
+	result.Allowance = utils.CustomFromWei(value, int(metadata.Decimals))
+	return result, nil
+}
+
+// IsUnlimited reports whether allowance is max uint256 or at or above configured threshold
+func (s *Service) IsUnlimited(value *big.Int) bool {
+	return s.erc20.IsMaxAllowance(value) || value.Cmp(s.threshold) >= 0
+}
+
+func (s *Service) readAllowance(ctx context.Context, chain entities.Chain, token, owner, spender common.Address) (*big.Int, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, ErrChainNotAvailable
+	}
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	value, err := s.erc20.GetAllowance(ctx, client, token, owner, spender)
+	if err != nil {
+		s.log.Error("failed to get allowance",
+			err,
+			zap.String("chain", chain.String()),
+			zap.String("contract", token.String()),
+			zap.String("owner", owner.String()),
+			zap.String("spender", spender.String()),
+		)
+		return nil, err
+	}
+	return value, nil
+}
+
+func (s *Service) newAllowance(chain entities.Chain, contract, owner, spender common.Address, value *big.Int) *entities.Allowance {
+	return &entities.Allowance{
+		Chain:        chain,
+		ChainName:    chain.String(),
+		Contract:     contract.String(),
+		Owner:        owner.String(),
+		Spender:      spender.String(),
+		AllowanceWei: value.String(),
+		Unlimited:    s.IsUnlimited(value),
+	}
+}
@@ -0,0 +1,41 @@
+package allowance
+
+import (
+	"altt/internal/config"
+	"altt/internal/logger"
+	"altt/internal/service/web3/approver"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_IsUnlimited(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	t.Run("default threshold", func(t *testing.T) {
+		srv, err := NewService(appLog, approver.InitService(appLog, nil), nil, config.AllowanceConfig{})
+		require.NoError(t, err)
+		require.True(t, srv.IsUnlimited(maxUint256))
+		require.True(t, srv.IsUnlimited(defaultUnlimitedThreshold))
+		require.False(t, srv.IsUnlimited(new(big.Int).Sub(defaultUnlimitedThreshold, big.NewInt(1))))
+		require.False(t, srv.IsUnlimited(big.NewInt(0)))
+	})
+
+	t.Run("configured threshold", func(t *testing.T) {
+		srv, err := NewService(appLog, approver.InitService(appLog, nil), nil, config.AllowanceConfig{UnlimitedThreshold: "0x3e8"})
+		require.NoError(t, err)
+		require.True(t, srv.IsUnlimited(big.NewInt(1000)))
+		require.False(t, srv.IsUnlimited(big.NewInt(999)))
+		require.True(t, srv.IsUnlimited(maxUint256))
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		for _, threshold := range []string{"abc", "-1", "0"} {
+			_, err := NewService(appLog, approver.InitService(appLog, nil), nil, config.AllowanceConfig{UnlimitedThreshold: threshold})
+			require.Error(t, err, threshold)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package approver
+
+import (
+	"altt/internal/entities"
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	// eip1967ImplementationSlot is bytes32(uint256(keccak256('eip1967.proxy.implementation')) - 1)
+	eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// eip1967BeaconSlot is bytes32(uint256(keccak256('eip1967.proxy.beacon')) - 1)
+	eip1967BeaconSlot = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	// eip1967AdminSlot is bytes32(uint256(keccak256('eip1967.proxy.admin')) - 1)
+	eip1967AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	// eip1822ProxiableSlot is keccak256('PROXIABLE')
+	eip1822ProxiableSlot = common.HexToHash("0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7")
+	// zeppelinOSImplementationSlot is keccak256('org.zeppelinos.proxy.implementation')
+	zeppelinOSImplementationSlot = common.HexToHash("0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3")
+
+	// eip1167Prefix and eip1167Suffix surround implementation address in code of minimal proxy
+	eip1167Prefix = common.FromHex("0x363d3d373d3d3d363d73")
+	eip1167Suffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+	// eip7702Prefix is delegation designator, it is followed by address of delegated code
+	eip7702Prefix = common.FromHex("0xef0100")
+)
+
+// accountBackend is part of rpc client used to classify account
+type accountBackend interface {
+	bind.ContractCaller
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// GetAccountInfo classifies address by code and nonce as EOA, contract, proxy with implementation read from
+// its storage slots or known smart account
+func (s *Service) GetAccountInfo(ctx context.Context, web3Client *ethclient.Client, address common.Address) (*entities.AccountInfo, error) {
+	return s.getAccountInfo(ctx, web3Client, address)
+}
+
+func (s *Service) getAccountInfo(ctx context.Context, backend accountBackend, address common.Address) (*entities.AccountInfo, error) {
+	code, err := backend.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get code: %w", err)
+	}
+	nonce, err := backend.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get nonce: %w", err)
+	}
+	info := &entities.AccountInfo{
+		Address:  address.String(),
+		Type:     entities.AccountTypeEOA,
+		Nonce:    nonce,
+		CodeSize: len(code),
+	}
+	switch {
+	case len(code) == 0:
+		return info, nil
+	case len(code) == len(eip7702Prefix)+common.AddressLength && bytes.HasPrefix(code, eip7702Prefix):
+		info.DelegatedTo = common.BytesToAddress(code[len(eip7702Prefix):]).String()
+		return info, nil
+	case len(code) == len(eip1167Prefix)+common.AddressLength+len(eip1167Suffix) &&
+		bytes.HasPrefix(code, eip1167Prefix) && bytes.HasSuffix(code, eip1167Suffix):
+		info.Type = entities.AccountTypeProxy
+		info.ProxyStandard = entities.ProxyEIP1167
+		info.Implementation = common.BytesToAddress(code[len(eip1167Prefix) : len(eip1167Prefix)+common.AddressLength]).String()
+		return info, nil
+	}
+
+	info.Type = entities.AccountTypeContract
+	isProxy, err := s.readProxySlots(ctx, backend, address, info)
+	if err != nil || isProxy {
+		return info, err
+	}
+	if err = s.readSafe(ctx, backend, address, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// readProxySlots fills proxy fields from EIP-1967, EIP-1822 and legacy ZeppelinOS storage slots
+func (s *Service) readProxySlots(ctx context.Context, backend accountBackend, address common.Address, info *entities.AccountInfo) (bool, error) {
+	slots := []common.Hash{eip1967ImplementationSlot, eip1967BeaconSlot, eip1967AdminSlot, eip1822ProxiableSlot, zeppelinOSImplementationSlot}
+	values := make([]common.Address, len(slots))
+	for i, slot := range slots {
+		value, err := backend.StorageAt(ctx, address, slot, nil)
+		if err != nil {
+			return false, fmt.Errorf("unable to read storage slot %s: %w", slot.String(), err)
+		}
+		values[i] = common.BytesToAddress(value)
+	}
+	implementation, beacon, admin, proxiable, zeppelinOS := values[0], values[1], values[2], values[3], values[4]
+	if admin != (common.Address{}) {
+		info.Admin = admin.String()
+	}
+	switch {
+	case implementation != (common.Address{}):
+		info.ProxyStandard = entities.ProxyEIP1967
+	case beacon != (common.Address{}):
+		info.ProxyStandard = entities.ProxyEIP1967Beacon
+		info.Beacon = beacon.String()
+		caller, err := NewBeaconCaller(beacon, backend)
+		if err != nil {
+			return false, err
+		}
+		if implementation, err = caller.Implementation(&bind.CallOpts{Context: ctx}); err != nil {
+			return false, fmt.Errorf("unable to get beacon implementation: %w", err)
+		}
+	case proxiable != (common.Address{}):
+		info.ProxyStandard = entities.ProxyEIP1822
+		implementation = proxiable
+	case zeppelinOS != (common.Address{}):
+		info.ProxyStandard = entities.ProxyZeppelinOS
+		implementation = zeppelinOS
+	default:
+		return false, nil
+	}
+	info.Type = entities.AccountTypeProxy
+	info.Implementation = implementation.String()
+	return true, nil
+}
+
+// readSafe fills smart account fields when address answers as Safe. Calls revert on other contracts, such answers
+// mean that contract is not a Safe, other errors are returned.
+func (s *Service) readSafe(ctx context.Context, backend accountBackend, address common.Address, info *entities.AccountInfo) error {
+	safe, err := NewSafeCaller(address, backend)
+	if err != nil {
+		return err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	version, err := safe.VERSION(opts)
+	if err != nil {
+		return notSafe(err)
+	}
+	threshold, err := safe.GetThreshold(opts)
+	if err != nil {
+		return notSafe(err)
+	}
+	owners, err := safe.GetOwners(opts)
+	if err != nil {
+		return notSafe(err)
+	}
+	if !threshold.IsUint64() || len(owners) == 0 {
+		return nil
+	}
+	// Safe proxy answers masterCopy() itself with its singleton, singleton does not have it
+	singleton, err := safe.MasterCopy(opts)
+	if err != nil && !isNotImplemented(err) {
+		return fmt.Errorf("unable to read safe singleton: %w", err)
+	}
+	info.Type = entities.AccountTypeSmartAccount
+	info.SmartAccount = entities.SmartAccountSafe
+	info.Version = version
+	info.Threshold = threshold.Uint64()
+	info.Owners = make([]string, 0, len(owners))
+	for _, owner := range owners {
+		info.Owners = append(info.Owners, owner.String())
+	}
+	if err == nil && singleton != (common.Address{}) {
+		info.Implementation = singleton.String()
+	}
+	return nil
+}
+
+// notSafe returns nil when error of Safe call means that contract is not a Safe, and error of failed read otherwise
+func notSafe(err error) error {
+	if isNotImplemented(err) {
+		return nil
+	}
+	return fmt.Errorf("unable to read safe: %w", err)
+}
@@ -0,0 +1,200 @@
+package approver
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccount serves code, nonce and storage of accounts, calls are answered by method selector. Calls of other
+// methods revert, or fail with callErr when it is set.
+type fakeAccount struct {
+	code    map[common.Address][]byte
+	storage map[common.Hash]common.Address
+	outputs map[string][]byte
+	callErr error
+}
+
+func (f *fakeAccount) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	return f.code[account], nil
+}
+
+func (f *fakeAccount) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	return 3, nil
+}
+
+func (f *fakeAccount) StorageAt(_ context.Context, _ common.Address, key common.Hash, _ *big.Int) ([]byte, error) {
+	return common.BytesToHash(f.storage[key].Bytes()).Bytes(), nil
+}
+
+func (f *fakeAccount) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	output, ok := f.outputs[common.Bytes2Hex(call.Data[:4])]
+	if !ok && f.callErr != nil {
+		return nil, f.callErr
+	}
+	if !ok {
+		return nil, errors.New("execution reverted")
+	}
+	return output, nil
+}
+
+func TestService_GetAccountInfo(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+	account := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	target := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	contractCode := common.FromHex("0x6080604052")
+
+	safeABI, err := SafeMetaData.GetAbi()
+	require.NoError(t, err)
+	beaconABI, err := BeaconMetaData.GetAbi()
+	require.NoError(t, err)
+	output := func(method string, values ...interface{}) (string, []byte) {
+		abiMethod, ok := safeABI.Methods[method]
+		if !ok {
+			abiMethod = beaconABI.Methods[method]
+		}
+		packed, errPack := abiMethod.Outputs.Pack(values...)
+		require.NoError(t, errPack)
+		return common.Bytes2Hex(abiMethod.ID), packed
+	}
+	outputs := func(methods ...[]interface{}) map[string][]byte {
+		result := make(map[string][]byte)
+		for _, method := range methods {
+			selector, packed := output(method[0].(string), method[1:]...)
+			result[selector] = packed
+		}
+		return result
+	}
+
+	table := map[string]struct {
+		backend  *fakeAccount
+		expected entities.AccountInfo
+	}{
+		"eoa": {
+			backend:  &fakeAccount{},
+			expected: entities.AccountInfo{Type: entities.AccountTypeEOA},
+		},
+		"eip-7702 delegated eoa": {
+			backend:  &fakeAccount{code: map[common.Address][]byte{account: append(common.FromHex("0xef0100"), target.Bytes()...)}},
+			expected: entities.AccountInfo{Type: entities.AccountTypeEOA, CodeSize: 23, DelegatedTo: target.String()},
+		},
+		"eip-1167 minimal proxy": {
+			backend: &fakeAccount{code: map[common.Address][]byte{
+				account: common.FromHex("0x363d3d373d3d3d363d73" + common.Bytes2Hex(target.Bytes()) + "5af43d82803e903d91602b57fd5bf3"),
+			}},
+			expected: entities.AccountInfo{Type: entities.AccountTypeProxy, CodeSize: 45, ProxyStandard: entities.ProxyEIP1167, Implementation: target.String()},
+		},
+		"eip-1967 proxy": {
+			backend: &fakeAccount{
+				code:    map[common.Address][]byte{account: contractCode},
+				storage: map[common.Hash]common.Address{eip1967ImplementationSlot: target, eip1967AdminSlot: account},
+			},
+			expected: entities.AccountInfo{
+				Type: entities.AccountTypeProxy, CodeSize: 5, ProxyStandard: entities.ProxyEIP1967, Implementation: target.String(), Admin: account.String(),
+			},
+		},
+		"eip-1967 beacon proxy": {
+			backend: &fakeAccount{
+				code:    map[common.Address][]byte{account: contractCode},
+				storage: map[common.Hash]common.Address{eip1967BeaconSlot: account},
+				outputs: outputs([]interface{}{"implementation", target}),
+			},
+			expected: entities.AccountInfo{
+				Type: entities.AccountTypeProxy, CodeSize: 5, ProxyStandard: entities.ProxyEIP1967Beacon, Implementation: target.String(), Beacon: account.String(),
+			},
+		},
+		"eip-1822 proxy": {
+			backend: &fakeAccount{
+				code:    map[common.Address][]byte{account: contractCode},
+				storage: map[common.Hash]common.Address{eip1822ProxiableSlot: target},
+			},
+			expected: entities.AccountInfo{Type: entities.AccountTypeProxy, CodeSize: 5, ProxyStandard: entities.ProxyEIP1822, Implementation: target.String()},
+		},
+		"zeppelinos proxy": {
+			backend: &fakeAccount{
+				code:    map[common.Address][]byte{account: contractCode},
+				storage: map[common.Hash]common.Address{zeppelinOSImplementationSlot: target},
+			},
+			expected: entities.AccountInfo{Type: entities.AccountTypeProxy, CodeSize: 5, ProxyStandard: entities.ProxyZeppelinOS, Implementation: target.String()},
+		},
+		"safe": {
+			backend: &fakeAccount{
+				code: map[common.Address][]byte{account: contractCode},
+				outputs: outputs(
+					[]interface{}{"VERSION", "1.3.0"},
+					[]interface{}{"getThreshold", big.NewInt(2)},
+					[]interface{}{"getOwners", []common.Address{account, target}},
+					[]interface{}{"masterCopy", target},
+				),
+			},
+			expected: entities.AccountInfo{
+				Type: entities.AccountTypeSmartAccount, CodeSize: 5, SmartAccount: entities.SmartAccountSafe, Version: "1.3.0",
+				Threshold: 2, Owners: []string{account.String(), target.String()}, Implementation: target.String(),
+			},
+		},
+		"contract": {
+			backend:  &fakeAccount{code: map[common.Address][]byte{account: contractCode}},
+			expected: entities.AccountInfo{Type: entities.AccountTypeContract, CodeSize: 5},
+		},
+		"contract with fallback": {
+			backend: &fakeAccount{
+				code:    map[common.Address][]byte{account: contractCode},
+				outputs: map[string][]byte{common.Bytes2Hex(safeABI.Methods["VERSION"].ID): {}},
+			},
+			expected: entities.AccountInfo{Type: entities.AccountTypeContract, CodeSize: 5},
+		},
+		"safe singleton without master copy": {
+			backend: &fakeAccount{
+				code: map[common.Address][]byte{account: contractCode},
+				outputs: outputs(
+					[]interface{}{"VERSION", "1.3.0"},
+					[]interface{}{"getThreshold", big.NewInt(1)},
+					[]interface{}{"getOwners", []common.Address{target}},
+				),
+			},
+			expected: entities.AccountInfo{
+				Type: entities.AccountTypeSmartAccount, CodeSize: 5, SmartAccount: entities.SmartAccountSafe, Version: "1.3.0",
+				Threshold: 1, Owners: []string{target.String()},
+			},
+		},
+	}
+	for name, tc := range table {
+		t.Run(name, func(t *testing.T) {
+			// when
+			info, err := srv.getAccountInfo(context.Background(), tc.backend, account)
+
+			// then
+			require.NoError(t, err)
+			tc.expected.Address = account.String()
+			tc.expected.Nonce = 3
+			require.Equal(t, tc.expected, *info)
+		})
+	}
+}
+
+func TestService_GetAccountInfoFailure(t *testing.T) {
+	// given node which fails calls of contract
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+	account := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	backend := &fakeAccount{
+		code:    map[common.Address][]byte{account: common.FromHex("0x6080604052")},
+		callErr: rpcError{code: -32000, message: "header not found"},
+	}
+
+	// when
+	_, err = srv.getAccountInfo(context.Background(), backend, account)
+
+	// then failed read is not reported as plain contract
+	require.ErrorContains(t, err, "header not found")
+}
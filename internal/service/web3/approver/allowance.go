@@ -0,0 +1,303 @@
+package approver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAllowanceUnderflow    = errors.New("decrease is above current allowance")
+	ErrAllowanceOverflow     = errors.New("increase is above max allowance")
+	ErrInvalidAllowanceDelta = errors.New("allowance change must be positive")
+	ErrTransactionFailed     = errors.New("transaction failed")
+)
+
+// ApprovalPair is token and spender of allowance
+type ApprovalPair struct {
+	Token   common.Address
+	Spender common.Address
+}
+
+// RevokeResult is outcome of revocation of pair, TxHash is empty when allowance is already zero or revocation failed
+type RevokeResult struct {
+	ApprovalPair
+	TxHash string
+	Err    error
+}
+
+// allowanceBackend is part of rpc client used to change allowance, transactions of holder are signed and sent by
+// SendTokenTx
+type allowanceBackend interface {
+	bind.ContractCaller
+	receiptReader
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SendTokenTx(ctx context.Context, tokenAddress, from common.Address, data []byte, nonce *big.Int) (string, error)
+}
+
+// signingClient is rpc client which sends token transactions signed by private key of holder
+type signingClient struct {
+	*ethclient.Client
+	service    *Service
+	privateKey *ecdsa.PrivateKey
+}
+
+func (s *Service) signingClient(web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey) *signingClient {
+	return &signingClient{Client: web3Client, service: s, privateKey: privateKey}
+}
+
+func (c *signingClient) SendTokenTx(ctx context.Context, tokenAddress, from common.Address, data []byte, nonce *big.Int) (string, error) {
+	tx, err := c.service.sendTokenTx(ctx, c.Client, c.privateKey, tokenAddress, from, data, nonce)
+	if err != nil {
+		return "", err
+	}
+	return tx.Hash().String(), nil
+}
+
+// ApproveContractUsage approves spender to spend exactly amount of token. Empty tx hash is returned when allowance
+// already equals amount.
+func (s *Service) ApproveContractUsage(ctx context.Context, web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey, tokenAddress, holder, spender common.Address, amount *big.Int) (string, error) {
+	if amount == nil || amount.Sign() < 0 || amount.Cmp(s.maxAllowed) > 0 {
+		return "", fmt.Errorf("invalid allowance %v", amount)
+	}
+	return s.setAllowance(ctx, s.signingClient(web3Client, privateKey), tokenAddress, holder, spender, amount, nil)
+}
+
+// IncreaseAllowance raises allowance of spender by positive delta
+func (s *Service) IncreaseAllowance(ctx context.Context, web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey, tokenAddress, holder, spender common.Address, delta *big.Int) (string, error) {
+	return s.changeAllowance(ctx, s.signingClient(web3Client, privateKey), tokenAddress, holder, spender, delta, true)
+}
+
+// DecreaseAllowance lowers allowance of spender by positive delta, ErrAllowanceUnderflow is returned when delta is
+// above current allowance
+func (s *Service) DecreaseAllowance(ctx context.Context, web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey, tokenAddress, holder, spender common.Address, delta *big.Int) (string, error) {
+	return s.changeAllowance(ctx, s.signingClient(web3Client, privateKey), tokenAddress, holder, spender, delta, false)
+}
+
+// RevokeApproval sets allowance of spender to zero. Empty tx hash is returned when there is no allowance.
+func (s *Service) RevokeApproval(ctx context.Context, web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey, tokenAddress, holder, spender common.Address) (string, error) {
+	return s.setAllowance(ctx, s.signingClient(web3Client, privateKey), tokenAddress, holder, spender, new(big.Int), nil)
+}
+
+// RevokeApprovals revokes allowances of pairs one by one with consecutive nonces. Failed pair does not stop the batch,
+// its error is in result.
+func (s *Service) RevokeApprovals(ctx context.Context, web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey, holder common.Address, pairs []ApprovalPair) ([]RevokeResult, error) {
+	return s.revokeApprovals(ctx, s.signingClient(web3Client, privateKey), holder, pairs)
+}
+
+func (s *Service) revokeApprovals(ctx context.Context, backend allowanceBackend, holder common.Address, pairs []ApprovalPair) ([]RevokeResult, error) {
+	nonce, err := backend.PendingNonceAt(ctx, holder)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get nonce: %w", err)
+	}
+	result := make([]RevokeResult, 0, len(pairs))
+	for _, pair := range pairs {
+		txHash, errRevoke := s.setAllowance(ctx, backend, pair.Token, holder, pair.Spender, new(big.Int), new(big.Int).SetUint64(nonce))
+		if errRevoke != nil {
+			s.log.Error("unable to revoke approval", errRevoke, zap.String("tokenAddress", pair.Token.String()), zap.String("spender", pair.Spender.String()))
+		}
+		if txHash != "" {
+			nonce++
+		}
+		result = append(result, RevokeResult{ApprovalPair: pair, TxHash: txHash, Err: errRevoke})
+	}
+	return result, nil
+}
+
+// changeAllowance changes allowance by delta with increaseAllowance or decreaseAllowance of token. They apply delta
+// to allowance at execution, so spender front-running the transaction is not able to spend both old and new allowance.
+// Tokens without these methods get approve of new allowance, reset to zero first only if they reject direct change.
+func (s *Service) changeAllowance(ctx context.Context, backend allowanceBackend, tokenAddress, holder, spender common.Address, delta *big.Int, increase bool) (string, error) {
+	if delta == nil || delta.Sign() <= 0 {
+		return "", fmt.Errorf("%w: %v", ErrInvalidAllowanceDelta, delta)
+	}
+	current, err := s.getAllowance(ctx, backend, tokenAddress, holder, spender)
+	if err != nil {
+		return "", err
+	}
+	method, change := "increaseAllowance", delta
+	if !increase {
+		method, change = "decreaseAllowance", new(big.Int).Neg(delta)
+	}
+	target, err := s.allowanceTarget(current, change)
+	if err != nil {
+		return "", err
+	}
+	log := s.log.With(zap.String("tokenAddress", tokenAddress.String())).With(zap.String("holder", holder.String())).With(zap.String("spender", spender.String()))
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	data, err := parsed.Pack(method, spender, delta)
+	if err != nil {
+		return "", fmt.Errorf("unable to pack %s: %w", method, err)
+	}
+	supported, err := s.supportsAllowanceChange(ctx, backend, tokenAddress, holder, data)
+	if err != nil {
+		return "", err
+	}
+	if supported {
+		log.Info("changing allowance", zap.String("method", method), zap.String("delta", delta.String()))
+		txHash, errSend := backend.SendTokenTx(ctx, tokenAddress, holder, data, nil)
+		if errSend != nil {
+			return "", fmt.Errorf("unable to %s: %w", method, errSend)
+		}
+		return txHash, nil
+	}
+	log.Info("token has no "+method+", approving new allowance", zap.String("allowance", target.String()))
+	return s.replaceAllowance(ctx, backend, tokenAddress, holder, spender, current, target, nil)
+}
+
+// supportsAllowanceChange reports whether token accepts call of increaseAllowance or decreaseAllowance. Contracts with
+// fallback accept any call, so only explicit true is taken as support.
+func (s *Service) supportsAllowanceChange(ctx context.Context, caller bind.ContractCaller, tokenAddress, holder common.Address, data []byte) (bool, error) {
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{From: holder, To: &tokenAddress, Data: data}, nil)
+	if err != nil {
+		if isRevert(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("unable to call token: %w", err)
+	}
+	return len(output) >= common.HashLength && checkTokenReturn(output) == nil, nil
+}
+
+// allowanceTarget returns current allowance changed by signed delta
+func (s *Service) allowanceTarget(current, delta *big.Int) (*big.Int, error) {
+	target := new(big.Int).Add(current, delta)
+	if target.Sign() < 0 {
+		return nil, fmt.Errorf("%w: allowance %s, decrease %s", ErrAllowanceUnderflow, current.String(), new(big.Int).Neg(delta).String())
+	}
+	if target.Cmp(s.maxAllowed) > 0 {
+		return nil, fmt.Errorf("%w: allowance %s, increase %s", ErrAllowanceOverflow, current.String(), delta.String())
+	}
+	return target, nil
+}
+
+// setAllowance sends approve of target allowance, nil nonce means pending nonce of holder. Transaction is skipped and
+// empty hash is returned when on-chain allowance already equals target. Tokens which reject change of non-zero
+// allowance, like USDT on ethereum, are reset to zero first and hash of the final approve is returned.
+func (s *Service) setAllowance(
+	ctx context.Context,
+	backend allowanceBackend,
+	tokenAddress, holder, spender common.Address,
+	target *big.Int,
+	nonce *big.Int,
+) (string, error) {
+	log := s.log.With(zap.String("tokenAddress", tokenAddress.String())).With(zap.String("holder", holder.String())).With(zap.String("spender", spender.String()))
+	total, err := s.getAllowance(ctx, backend, tokenAddress, holder, spender)
+	if err != nil {
+		return "", err
+	}
+	log.Info("allowance", zap.String("allowance", total.String()))
+	if total.Cmp(target) == 0 {
+		log.Info("already approved", zap.String("allowance", total.String()))
+		return "", nil
+	}
+
+	return s.replaceAllowance(ctx, backend, tokenAddress, holder, spender, total, target, nonce)
+}
+
+// replaceAllowance approves target in place of current allowance. Allowance is reset to zero first only when token
+// rejects approve of non-zero allowance over non-zero one.
+func (s *Service) replaceAllowance(
+	ctx context.Context,
+	backend allowanceBackend,
+	tokenAddress, holder, spender common.Address,
+	current, target *big.Int,
+	nonce *big.Int,
+) (string, error) {
+	log := s.log.With(zap.String("tokenAddress", tokenAddress.String())).With(zap.String("holder", holder.String())).With(zap.String("spender", spender.String()))
+	if current.Sign() != 0 && target.Sign() != 0 {
+		reset, err := s.needsZeroReset(ctx, backend, tokenAddress, holder, spender, target)
+		if err != nil {
+			return "", err
+		}
+		if reset {
+			log.Info("token rejects change of non-zero allowance, reset it first")
+			return s.resetAndApprove(ctx, backend, tokenAddress, holder, spender, target, nonce)
+		}
+	}
+	log.Info("approving", zap.String("allowance", target.String()))
+	return s.approve(ctx, backend, tokenAddress, holder, spender, target, nonce)
+}
+
+// resetAndApprove sets allowance to zero and approves target after reset is mined, hash of the final approve is returned.
+// Error of the final approve has hash of the reset, as allowance is left at zero then.
+func (s *Service) resetAndApprove(
+	ctx context.Context,
+	backend allowanceBackend,
+	tokenAddress, holder, spender common.Address,
+	target *big.Int,
+	nonce *big.Int,
+) (string, error) {
+	resetHash, err := s.approve(ctx, backend, tokenAddress, holder, spender, new(big.Int), nonce)
+	if err != nil {
+		return "", fmt.Errorf("unable to reset allowance: %w", err)
+	}
+	receipt, err := s.waitTransaction(ctx, backend, resetHash)
+	if err != nil {
+		return "", fmt.Errorf("unable to wait allowance reset %s: %w", resetHash, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("unable to reset allowance: %w: %s", ErrTransactionFailed, resetHash)
+	}
+	txHash, err := s.approve(ctx, backend, tokenAddress, holder, spender, target, nil)
+	if err != nil {
+		return "", fmt.Errorf("allowance is reset to zero by %s: %w", resetHash, err)
+	}
+	return txHash, nil
+}
+
+// needsZeroReset reports whether approve of target is rejected while approve of zero is accepted
+func (s *Service) needsZeroReset(ctx context.Context, caller bind.ContractCaller, tokenAddress, holder, spender common.Address, target *big.Int) (bool, error) {
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return false, err
+	}
+	data, err := parsed.Pack("approve", spender, target)
+	if err != nil {
+		return false, fmt.Errorf("unable to pack approve: %w", err)
+	}
+	err = s.simulateTokenCall(ctx, caller, tokenAddress, holder, data)
+	if err == nil || (!errors.Is(err, ErrTokenCallReverted) && !errors.Is(err, ErrTokenReturnedFalse)) {
+		return false, err
+	}
+	if data, err = parsed.Pack("approve", spender, new(big.Int)); err != nil {
+		return false, fmt.Errorf("unable to pack approve: %w", err)
+	}
+	if errZero := s.simulateTokenCall(ctx, caller, tokenAddress, holder, data); errZero != nil {
+		return false, fmt.Errorf("unable to approve: %w", errZero)
+	}
+	return true, nil
+}
+
+func (s *Service) approve(
+	ctx context.Context,
+	backend allowanceBackend,
+	tokenAddress, holder, spender common.Address,
+	amount *big.Int,
+	nonce *big.Int,
+) (string, error) {
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	data, err := parsed.Pack("approve", spender, amount)
+	if err != nil {
+		return "", fmt.Errorf("unable to pack approve: %w", err)
+	}
+	txHash, err := backend.SendTokenTx(ctx, tokenAddress, holder, data, nonce)
+	if err != nil {
+		return "", fmt.Errorf("unable to approve: %w", err)
+	}
+	return txHash, nil
+}
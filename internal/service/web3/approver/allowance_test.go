@@ -0,0 +1,377 @@
+package approver
+
+import (
+	"altt/internal/logger"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// sentTx is token transaction sent by fakeAllowanceBackend
+type sentTx struct {
+	token  common.Address
+	method string
+	value  string
+	nonce  *big.Int
+}
+
+// fakeAllowanceBackend keeps allowances of one holder and spender by token and applies sent transactions at once
+type fakeAllowanceBackend struct {
+	allowances map[common.Address]*big.Int
+	// zeroFirst tokens reject change of non-zero allowance like USDT on ethereum
+	zeroFirst map[common.Address]bool
+	// changeable tokens implement increaseAllowance and decreaseAllowance
+	changeable map[common.Address]bool
+	// rejected tokens return false from approve
+	rejected map[common.Address]bool
+	// failingTx is number of sent transaction which fails, from 1
+	failingTx int
+	// reverted transactions are mined with failed status
+	reverted bool
+	nonce    uint64
+	sent     []sentTx
+}
+
+func (f *fakeAllowanceBackend) allowance(token common.Address) *big.Int {
+	if value, ok := f.allowances[token]; ok {
+		return value
+	}
+	return new(big.Int)
+}
+
+func (f *fakeAllowanceBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (f *fakeAllowanceBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	method, err := parsed.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	token := *call.To
+	switch method.Name {
+	case "allowance":
+		return method.Outputs.Pack(f.allowance(token))
+	case "approve":
+		if f.rejected[token] {
+			return method.Outputs.Pack(false)
+		}
+		if f.zeroFirst[token] && args[1].(*big.Int).Sign() != 0 && f.allowance(token).Sign() != 0 {
+			return nil, revertError{}
+		}
+	case "increaseAllowance", "decreaseAllowance":
+		if !f.changeable[token] {
+			return nil, revertError{}
+		}
+	}
+	return method.Outputs.Pack(true)
+}
+
+func (f *fakeAllowanceBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return f.nonce, nil
+}
+
+func (f *fakeAllowanceBackend) TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error) {
+	if f.reverted {
+		return &types.Receipt{Status: types.ReceiptStatusFailed}, nil
+	}
+	return &types.Receipt{Status: types.ReceiptStatusSuccessful}, nil
+}
+
+func (f *fakeAllowanceBackend) SendTokenTx(ctx context.Context, tokenAddress, from common.Address, data []byte, nonce *big.Int) (string, error) {
+	if _, err := f.CallContract(ctx, ethereum.CallMsg{From: from, To: &tokenAddress, Data: data}, nil); err != nil {
+		return "", err
+	}
+	if f.rejected[tokenAddress] {
+		return "", ErrTokenReturnedFalse
+	}
+	if f.failingTx == len(f.sent)+1 {
+		return "", errors.New("nonce too low")
+	}
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	method, err := parsed.MethodById(data[:4])
+	if err != nil {
+		return "", err
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return "", err
+	}
+	value := args[1].(*big.Int)
+	switch method.Name {
+	case "approve":
+		f.allowances[tokenAddress] = value
+	case "increaseAllowance":
+		f.allowances[tokenAddress] = new(big.Int).Add(f.allowance(tokenAddress), value)
+	case "decreaseAllowance":
+		f.allowances[tokenAddress] = new(big.Int).Sub(f.allowance(tokenAddress), value)
+	}
+	f.sent = append(f.sent, sentTx{token: tokenAddress, method: method.Name, value: value.String(), nonce: nonce})
+	return common.BigToHash(big.NewInt(int64(len(f.sent)))).String(), nil
+}
+
+func newFakeAllowanceBackend() *fakeAllowanceBackend {
+	return &fakeAllowanceBackend{
+		allowances: make(map[common.Address]*big.Int),
+		zeroFirst:  make(map[common.Address]bool),
+		changeable: make(map[common.Address]bool),
+		rejected:   make(map[common.Address]bool),
+	}
+}
+
+var (
+	testHolder  = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	testSpender = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	testTokenA  = common.HexToAddress("0x000000000000000000000000000000000000000a")
+	testTokenB  = common.HexToAddress("0x000000000000000000000000000000000000000b")
+	testTokenC  = common.HexToAddress("0x000000000000000000000000000000000000000c")
+)
+
+func TestService_AllowanceTarget(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+
+	t.Run("increase and decrease", func(t *testing.T) {
+		target, err := srv.allowanceTarget(big.NewInt(100), big.NewInt(50))
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(150), target)
+
+		target, err = srv.allowanceTarget(big.NewInt(100), big.NewInt(-100))
+		require.NoError(t, err)
+		require.Zero(t, target.Sign())
+	})
+
+	t.Run("decrease below zero", func(t *testing.T) {
+		_, err := srv.allowanceTarget(big.NewInt(100), big.NewInt(-101))
+		require.ErrorIs(t, err, ErrAllowanceUnderflow)
+	})
+
+	t.Run("increase above max", func(t *testing.T) {
+		_, err := srv.allowanceTarget(srv.maxAllowed, big.NewInt(1))
+		require.ErrorIs(t, err, ErrAllowanceOverflow)
+	})
+}
+
+func TestService_SetAllowance(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+	ctx := context.Background()
+
+	t.Run("equal allowance is skipped", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+
+		// when
+		txHash, err := srv.setAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(10), nil)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, txHash)
+		require.Empty(t, backend.sent)
+	})
+
+	t.Run("allowance is approved", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+
+		// when
+		txHash, err := srv.setAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(20), big.NewInt(3))
+
+		// then
+		require.NoError(t, err)
+		require.NotEmpty(t, txHash)
+		require.Equal(t, []sentTx{{token: testTokenA, method: "approve", value: "20", nonce: big.NewInt(3)}}, backend.sent)
+	})
+
+	t.Run("non-zero allowance is reset first", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+		backend.zeroFirst[testTokenA] = true
+
+		// when
+		_, err := srv.setAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(20), big.NewInt(3))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []sentTx{
+			{token: testTokenA, method: "approve", value: "0", nonce: big.NewInt(3)},
+			{token: testTokenA, method: "approve", value: "20"},
+		}, backend.sent)
+	})
+
+	t.Run("failed reset is not followed by approve", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+		backend.zeroFirst[testTokenA] = true
+		backend.reverted = true
+
+		// when
+		_, err := srv.setAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(20), nil)
+
+		// then
+		require.ErrorIs(t, err, ErrTransactionFailed)
+		require.Len(t, backend.sent, 1)
+	})
+
+	t.Run("failed approve after reset has reset hash", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+		backend.zeroFirst[testTokenA] = true
+		backend.failingTx = 2
+
+		// when
+		_, err := srv.setAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(20), nil)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), common.BigToHash(big.NewInt(1)).String())
+		require.Zero(t, backend.allowance(testTokenA).Sign())
+	})
+}
+
+func TestService_RevokeApprovals(t *testing.T) {
+	// given
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+	backend := newFakeAllowanceBackend()
+	backend.nonce = 7
+	backend.allowances[testTokenA] = big.NewInt(10)
+	backend.allowances[testTokenC] = big.NewInt(30)
+	rejected := common.HexToAddress("0x000000000000000000000000000000000000000d")
+	backend.allowances[rejected] = big.NewInt(40)
+	backend.rejected[rejected] = true
+	pairs := []ApprovalPair{
+		{Token: testTokenA, Spender: testSpender},
+		{Token: testTokenB, Spender: testSpender},
+		{Token: rejected, Spender: testSpender},
+		{Token: testTokenC, Spender: testSpender},
+	}
+
+	// when
+	result, err := srv.revokeApprovals(context.Background(), backend, testHolder, pairs)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, result, 4)
+	require.NotEmpty(t, result[0].TxHash)
+	require.Empty(t, result[1].TxHash)
+	require.NoError(t, result[1].Err)
+	require.Empty(t, result[2].TxHash)
+	require.ErrorIs(t, result[2].Err, ErrTokenReturnedFalse)
+	require.NotEmpty(t, result[3].TxHash)
+	require.Equal(t, []sentTx{
+		{token: testTokenA, method: "approve", value: "0", nonce: big.NewInt(7)},
+		{token: testTokenC, method: "approve", value: "0", nonce: big.NewInt(8)},
+	}, backend.sent)
+}
+
+func TestService_ChangeAllowance(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+	ctx := context.Background()
+
+	t.Run("invalid delta", func(t *testing.T) {
+		backend := newFakeAllowanceBackend()
+		for _, delta := range []*big.Int{nil, new(big.Int), big.NewInt(-1)} {
+			_, err := srv.changeAllowance(ctx, backend, testTokenA, testHolder, testSpender, delta, true)
+			require.ErrorIs(t, err, ErrInvalidAllowanceDelta)
+		}
+		require.Empty(t, backend.sent)
+	})
+
+	t.Run("token methods are used when implemented", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+		backend.changeable[testTokenA] = true
+
+		// when
+		_, errIncrease := srv.changeAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(5), true)
+		_, errDecrease := srv.changeAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(15), false)
+
+		// then
+		require.NoError(t, errIncrease)
+		require.NoError(t, errDecrease)
+		require.Equal(t, []sentTx{
+			{token: testTokenA, method: "increaseAllowance", value: "5"},
+			{token: testTokenA, method: "decreaseAllowance", value: "15"},
+		}, backend.sent)
+		require.Zero(t, backend.allowance(testTokenA).Sign())
+	})
+
+	t.Run("allowance is approved directly without allowance change methods", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+
+		// when
+		_, err := srv.changeAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(5), true)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []sentTx{{token: testTokenA, method: "approve", value: "15"}}, backend.sent)
+	})
+
+	t.Run("allowance is reset before approve when token rejects direct change", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+		backend.zeroFirst[testTokenA] = true
+
+		// when
+		_, err := srv.changeAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(5), true)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []sentTx{
+			{token: testTokenA, method: "approve", value: "0"},
+			{token: testTokenA, method: "approve", value: "15"},
+		}, backend.sent)
+	})
+
+	t.Run("zero allowance is approved directly", func(t *testing.T) {
+		// given
+		backend := newFakeAllowanceBackend()
+
+		// when
+		_, err := srv.changeAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(5), true)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []sentTx{{token: testTokenA, method: "approve", value: "5"}}, backend.sent)
+	})
+
+	t.Run("decrease above allowance", func(t *testing.T) {
+		backend := newFakeAllowanceBackend()
+		backend.allowances[testTokenA] = big.NewInt(10)
+		_, err := srv.changeAllowance(ctx, backend, testTokenA, testHolder, testSpender, big.NewInt(11), false)
+		require.True(t, errors.Is(err, ErrAllowanceUnderflow))
+		require.Empty(t, backend.sent)
+	})
+}
@@ -0,0 +1,212 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package approver
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// BeaconMetaData contains all meta data concerning the Beacon contract.
+var BeaconMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"name\":\"implementation\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// BeaconABI is the input ABI used to generate the binding from.
+// Deprecated: Use BeaconMetaData.ABI instead.
+var BeaconABI = BeaconMetaData.ABI
+
+// Beacon is an auto generated Go binding around an Ethereum contract.
+type Beacon struct {
+	BeaconCaller     // Read-only binding to the contract
+	BeaconTransactor // Write-only binding to the contract
+	BeaconFilterer   // Log filterer for contract events
+}
+
+// BeaconCaller is an auto generated read-only Go binding around an Ethereum contract.
+type BeaconCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BeaconTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type BeaconTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BeaconFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type BeaconFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// BeaconSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type BeaconSession struct {
+	Contract     *Beacon           // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BeaconCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type BeaconCallerSession struct {
+	Contract *BeaconCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// BeaconTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type BeaconTransactorSession struct {
+	Contract     *BeaconTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// BeaconRaw is an auto generated low-level Go binding around an Ethereum contract.
+type BeaconRaw struct {
+	Contract *Beacon // Generic contract binding to access the raw methods on
+}
+
+// BeaconCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type BeaconCallerRaw struct {
+	Contract *BeaconCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// BeaconTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type BeaconTransactorRaw struct {
+	Contract *BeaconTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewBeacon creates a new instance of Beacon, bound to a specific deployed contract.
+func NewBeacon(address common.Address, backend bind.ContractBackend) (*Beacon, error) {
+	contract, err := bindBeacon(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Beacon{BeaconCaller: BeaconCaller{contract: contract}, BeaconTransactor: BeaconTransactor{contract: contract}, BeaconFilterer: BeaconFilterer{contract: contract}}, nil
+}
+
+// NewBeaconCaller creates a new read-only instance of Beacon, bound to a specific deployed contract.
+func NewBeaconCaller(address common.Address, caller bind.ContractCaller) (*BeaconCaller, error) {
+	contract, err := bindBeacon(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BeaconCaller{contract: contract}, nil
+}
+
+// NewBeaconTransactor creates a new write-only instance of Beacon, bound to a specific deployed contract.
+func NewBeaconTransactor(address common.Address, transactor bind.ContractTransactor) (*BeaconTransactor, error) {
+	contract, err := bindBeacon(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BeaconTransactor{contract: contract}, nil
+}
+
+// NewBeaconFilterer creates a new log filterer instance of Beacon, bound to a specific deployed contract.
+func NewBeaconFilterer(address common.Address, filterer bind.ContractFilterer) (*BeaconFilterer, error) {
+	contract, err := bindBeacon(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &BeaconFilterer{contract: contract}, nil
+}
+
+// bindBeacon binds a generic wrapper to an already deployed contract.
+func bindBeacon(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := BeaconMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Beacon *BeaconRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Beacon.Contract.BeaconCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Beacon *BeaconRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Beacon.Contract.BeaconTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Beacon *BeaconRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Beacon.Contract.BeaconTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Beacon *BeaconCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Beacon.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Beacon *BeaconTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Beacon.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Beacon *BeaconTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Beacon.Contract.contract.Transact(opts, method, params...)
+}
+
+// Implementation is a free data retrieval call binding the contract method 0x5c60da1b.
+//
+// Solidity: function implementation() view returns(address)
+func (_Beacon *BeaconCaller) Implementation(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Beacon.contract.Call(opts, &out, "implementation")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Implementation is a free data retrieval call binding the contract method 0x5c60da1b.
+//
+// Solidity: function implementation() view returns(address)
+func (_Beacon *BeaconSession) Implementation() (common.Address, error) {
+	return _Beacon.Contract.Implementation(&_Beacon.CallOpts)
+}
+
+// Implementation is a free data retrieval call binding the contract method 0x5c60da1b.
+//
+// Solidity: function implementation() view returns(address)
+func (_Beacon *BeaconCallerSession) Implementation() (common.Address, error) {
+	return _Beacon.Contract.Implementation(&_Beacon.CallOpts)
+}
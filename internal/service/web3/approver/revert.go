@@ -0,0 +1,40 @@
+package approver
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// revertCode is json-rpc error code of eth_call and eth_estimateGas reverted by contract
+const revertCode = 3
+
+// revertMessages are parts of node errors for calls which were executed and failed in contract
+var revertMessages = []string{
+	"execution reverted",
+	"vm execution error",
+	"invalid opcode",
+}
+
+// isRevert reports whether contract rejected call, as opposed to node which failed to execute it
+func isRevert(err error) bool {
+	var rpcErr gethrpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == revertCode {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, part := range revertMessages {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNotImplemented reports whether contract does not implement called method: call reverted, or contract answered with
+// data which does not decode as method output (fallback function)
+func isNotImplemented(err error) bool {
+	return isRevert(err) || errors.Is(err, bind.ErrNoCode) || strings.HasPrefix(err.Error(), "abi:")
+}
@@ -0,0 +1,35 @@
+package approver
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcError is json-rpc error returned by node
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e rpcError) Error() string  { return e.message }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestIsRevert(t *testing.T) {
+	require.True(t, isRevert(rpcError{code: 3, message: "execution reverted: ERC20: insufficient allowance"}))
+	require.True(t, isRevert(fmt.Errorf("wrapped: %w", rpcError{code: -32000, message: "execution reverted"})))
+	require.True(t, isRevert(errors.New("VM execution error.")))
+	require.False(t, isRevert(rpcError{code: -32000, message: "header not found"}))
+	require.False(t, isRevert(rpcError{code: 429, message: "too many requests"}))
+	require.False(t, isRevert(errors.New("context deadline exceeded")))
+}
+
+func TestIsNotImplemented(t *testing.T) {
+	require.True(t, isNotImplemented(rpcError{code: 3, message: "execution reverted"}))
+	require.True(t, isNotImplemented(bind.ErrNoCode))
+	require.True(t, isNotImplemented(errors.New("abi: attempting to unmarshall an empty string while arguments are expected")))
+	require.False(t, isNotImplemented(errors.New("connection refused")))
+}
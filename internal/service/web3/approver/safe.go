@@ -0,0 +1,305 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package approver
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// SafeMetaData contains all meta data concerning the Safe contract.
+var SafeMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"name\":\"VERSION\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getOwners\",\"outputs\":[{\"internalType\":\"address[]\",\"name\":\"\",\"type\":\"address[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getThreshold\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"masterCopy\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// SafeABI is the input ABI used to generate the binding from.
+// Deprecated: Use SafeMetaData.ABI instead.
+var SafeABI = SafeMetaData.ABI
+
+// Safe is an auto generated Go binding around an Ethereum contract.
+type Safe struct {
+	SafeCaller     // Read-only binding to the contract
+	SafeTransactor // Write-only binding to the contract
+	SafeFilterer   // Log filterer for contract events
+}
+
+// SafeCaller is an auto generated read-only Go binding around an Ethereum contract.
+type SafeCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SafeTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type SafeTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SafeFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type SafeFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// SafeSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type SafeSession struct {
+	Contract     *Safe             // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SafeCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type SafeCallerSession struct {
+	Contract *SafeCaller   // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// SafeTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type SafeTransactorSession struct {
+	Contract     *SafeTransactor   // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// SafeRaw is an auto generated low-level Go binding around an Ethereum contract.
+type SafeRaw struct {
+	Contract *Safe // Generic contract binding to access the raw methods on
+}
+
+// SafeCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type SafeCallerRaw struct {
+	Contract *SafeCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// SafeTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type SafeTransactorRaw struct {
+	Contract *SafeTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewSafe creates a new instance of Safe, bound to a specific deployed contract.
+func NewSafe(address common.Address, backend bind.ContractBackend) (*Safe, error) {
+	contract, err := bindSafe(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Safe{SafeCaller: SafeCaller{contract: contract}, SafeTransactor: SafeTransactor{contract: contract}, SafeFilterer: SafeFilterer{contract: contract}}, nil
+}
+
+// NewSafeCaller creates a new read-only instance of Safe, bound to a specific deployed contract.
+func NewSafeCaller(address common.Address, caller bind.ContractCaller) (*SafeCaller, error) {
+	contract, err := bindSafe(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SafeCaller{contract: contract}, nil
+}
+
+// NewSafeTransactor creates a new write-only instance of Safe, bound to a specific deployed contract.
+func NewSafeTransactor(address common.Address, transactor bind.ContractTransactor) (*SafeTransactor, error) {
+	contract, err := bindSafe(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SafeTransactor{contract: contract}, nil
+}
+
+// NewSafeFilterer creates a new log filterer instance of Safe, bound to a specific deployed contract.
+func NewSafeFilterer(address common.Address, filterer bind.ContractFilterer) (*SafeFilterer, error) {
+	contract, err := bindSafe(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &SafeFilterer{contract: contract}, nil
+}
+
+// bindSafe binds a generic wrapper to an already deployed contract.
+func bindSafe(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := SafeMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Safe *SafeRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Safe.Contract.SafeCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Safe *SafeRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Safe.Contract.SafeTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Safe *SafeRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Safe.Contract.SafeTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Safe *SafeCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Safe.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Safe *SafeTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Safe.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Safe *SafeTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Safe.Contract.contract.Transact(opts, method, params...)
+}
+
+// VERSION is a free data retrieval call binding the contract method 0xffa1ad74.
+//
+// Solidity: function VERSION() view returns(string)
+func (_Safe *SafeCaller) VERSION(opts *bind.CallOpts) (string, error) {
+	var out []interface{}
+	err := _Safe.contract.Call(opts, &out, "VERSION")
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// VERSION is a free data retrieval call binding the contract method 0xffa1ad74.
+//
+// Solidity: function VERSION() view returns(string)
+func (_Safe *SafeSession) VERSION() (string, error) {
+	return _Safe.Contract.VERSION(&_Safe.CallOpts)
+}
+
+// VERSION is a free data retrieval call binding the contract method 0xffa1ad74.
+//
+// Solidity: function VERSION() view returns(string)
+func (_Safe *SafeCallerSession) VERSION() (string, error) {
+	return _Safe.Contract.VERSION(&_Safe.CallOpts)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() view returns(address[])
+func (_Safe *SafeCaller) GetOwners(opts *bind.CallOpts) ([]common.Address, error) {
+	var out []interface{}
+	err := _Safe.contract.Call(opts, &out, "getOwners")
+
+	if err != nil {
+		return *new([]common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	return out0, err
+
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() view returns(address[])
+func (_Safe *SafeSession) GetOwners() ([]common.Address, error) {
+	return _Safe.Contract.GetOwners(&_Safe.CallOpts)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() view returns(address[])
+func (_Safe *SafeCallerSession) GetOwners() ([]common.Address, error) {
+	return _Safe.Contract.GetOwners(&_Safe.CallOpts)
+}
+
+// GetThreshold is a free data retrieval call binding the contract method 0xe75235b8.
+//
+// Solidity: function getThreshold() view returns(uint256)
+func (_Safe *SafeCaller) GetThreshold(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Safe.contract.Call(opts, &out, "getThreshold")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetThreshold is a free data retrieval call binding the contract method 0xe75235b8.
+//
+// Solidity: function getThreshold() view returns(uint256)
+func (_Safe *SafeSession) GetThreshold() (*big.Int, error) {
+	return _Safe.Contract.GetThreshold(&_Safe.CallOpts)
+}
+
+// GetThreshold is a free data retrieval call binding the contract method 0xe75235b8.
+//
+// Solidity: function getThreshold() view returns(uint256)
+func (_Safe *SafeCallerSession) GetThreshold() (*big.Int, error) {
+	return _Safe.Contract.GetThreshold(&_Safe.CallOpts)
+}
+
+// MasterCopy is a free data retrieval call binding the contract method 0xa619486e.
+//
+// Solidity: function masterCopy() view returns(address)
+func (_Safe *SafeCaller) MasterCopy(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Safe.contract.Call(opts, &out, "masterCopy")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// MasterCopy is a free data retrieval call binding the contract method 0xa619486e.
+//
+// Solidity: function masterCopy() view returns(address)
+func (_Safe *SafeSession) MasterCopy() (common.Address, error) {
+	return _Safe.Contract.MasterCopy(&_Safe.CallOpts)
+}
+
+// MasterCopy is a free data retrieval call binding the contract method 0xa619486e.
+//
+// Solidity: function masterCopy() view returns(address)
+func (_Safe *SafeCallerSession) MasterCopy() (common.Address, error) {
+	return _Safe.Contract.MasterCopy(&_Safe.CallOpts)
+}
@@ -1,12 +1,15 @@
 package approver
 
 import (
+	"altt/internal/entities"
 	"altt/internal/logger"
+	"altt/internal/utils"
 	"context"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,14 +23,27 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// maxURIRequests is limit of concurrent uri calls of one GetERC1155URIs
+const maxURIRequests = 8
+
+var ErrInsufficientFunds = errors.New("native balance is not enough to pay transaction fee")
+
+// FeeEstimator estimates total fee of transaction, on rollups L1 data fee is included, and suggests fee per gas
+// of transaction to send
+type FeeEstimator interface {
+	EstimateFeeWithClient(ctx context.Context, client *ethclient.Client, chain entities.Chain, msg ethereum.CallMsg) (*entities.FeeEstimate, error)
+	SuggestTxFee(ctx context.Context, client *ethclient.Client, chain entities.Chain) (*entities.TxFee, error)
+}
+
 // Service is a service that approves contracts to spend tokens
 type Service struct {
 	maxAllowed *big.Int
 	log        logger.AppLogger
+	fees       FeeEstimator
 }
 
-// InitService initializes the service
-func InitService(appLog logger.AppLogger) *Service {
+// InitService initializes the service, fees are used before sending transactions only
+func InitService(appLog logger.AppLogger, fees FeeEstimator) *Service {
 	two := big.NewInt(2)
 	exponent := big.NewInt(256)
 	power := new(big.Int).Exp(two, exponent, nil)
@@ -35,91 +51,140 @@ func InitService(appLog logger.AppLogger) *Service {
 	return &Service{
 		maxAllowed: result,
 		log:        appLog,
+		fees:       fees,
 	}
 }
 
 // ApproveContractUsageALL approves the contract to spend all the tokens
 func (s *Service) ApproveContractUsageALL(web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey, tokenAddress, holder, spender common.Address) (string, error) {
-	log := s.log.With(zap.String("tokenAddress", tokenAddress.String())).With(zap.String("holder", holder.String())).With(zap.String("spender", spender.String()))
-	contract, err := NewErc20(tokenAddress, web3Client)
+	return s.setAllowance(context.Background(), s.signingClient(web3Client, privateKey), tokenAddress, holder, spender, s.maxAllowed, nil)
+}
+
+// checkFee estimates gas of transaction and checks that sender is able to pay it at chosen fee, L1 data fee on rollups
+// and value of transaction included
+func (s *Service) checkFee(ctx context.Context, web3Client *ethclient.Client, chain entities.Chain, msg ethereum.CallMsg, fee *entities.TxFee) error {
+	estimate, err := s.fees.EstimateFeeWithClient(ctx, web3Client, chain, msg)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("unable to estimate fee: %w", err)
 	}
-	total, err := contract.Allowance(nil, holder, spender)
+	l1Fee, _ := new(big.Int).SetString(estimate.L1DataFee.Wei, 10)
+	total := maxTxCost(estimate.GasLimit, fee, l1Fee, msg.Value)
+	s.log.Info("transaction fee",
+		zap.String("chain", chain.String()),
+		zap.Uint64("gas_limit", estimate.GasLimit),
+		zap.String("l1_data_fee", estimate.L1DataFee.Amount),
+		zap.String("max_total_cost", utils.ETHFromWei(total)),
+	)
+	balance, err := s.GetNativeTokenBalance(ctx, web3Client, msg.From, entities.BlockTagLatest)
 	if err != nil {
-		return "", fmt.Errorf("unable to get allowance: %w", err)
+		return err
 	}
-	log.Info("allowance", zap.String("allowance", total.String()))
-	if total.Cmp(s.maxAllowed) == 0 { // they are equal
-		log.Info("already approved", zap.String("allowance", total.String()))
-		return "", nil
+	if balance.Cmp(total) < 0 {
+		return fmt.Errorf("%w: balance %s, fee %s", ErrInsufficientFunds, balance.String(), total.String())
 	}
+	return nil
+}
 
-	chainID, err := web3Client.ChainID(context.Background())
-	if err != nil {
-		return "", fmt.Errorf("unable to get chain id: %w", err)
+// maxTxCost is the most sender pays for transaction: gas limit times max fee per gas (gas price of legacy
+// transaction), L1 data fee and value
+func maxTxCost(gasLimit uint64, fee *entities.TxFee, l1Fee, value *big.Int) *big.Int {
+	price := fee.GasFeeCap
+	if fee.GasPrice != nil {
+		price = fee.GasPrice
 	}
-	log.Info("approving", zap.String("allowance", s.maxAllowed.String()), zap.Uint64("chainID", chainID.Uint64()))
-
-	tx, err := contract.Approve(&bind.TransactOpts{
-		From: holder,
-		Signer: func(address common.Address, transaction *types.Transaction) (*types.Transaction, error) {
-			return types.SignTx(transaction, types.LatestSignerForChainID(chainID), privateKey)
-		},
-	}, spender, s.maxAllowed)
-	if err != nil {
-		return "", fmt.Errorf("unable to approve: %w", err)
+	total := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), price)
+	for _, part := range []*big.Int{l1Fee, value} {
+		if part != nil {
+			total.Add(total, part)
+		}
 	}
-
-	log.Info("tx sent", zap.String("txHash", tx.Hash().String()))
-	return tx.Hash().String(), nil
+	return total
 }
 
-func (s *Service) GetNativeTokenBalance(ctx context.Context, web3Client *ethclient.Client, address common.Address) (*big.Int, error) {
-	val, err := web3Client.BalanceAt(ctx, address, nil)
+func (s *Service) GetNativeTokenBalance(ctx context.Context, web3Client *ethclient.Client, address common.Address, tag entities.BlockTag) (*big.Int, error) {
+	var (
+		val *big.Int
+		err error
+	)
+	if tag == entities.BlockTagPending {
+		val, err = web3Client.PendingBalanceAt(ctx, address)
+	} else {
+		val, err = web3Client.BalanceAt(ctx, address, tag.BlockNumber())
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to get balance: %w", err)
 	}
 	return val, nil
 }
 
+// receiptReader is part of rpc client used to wait for transaction
+type receiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
 func (s *Service) WaitTransaction(ctx context.Context, web3Client *ethclient.Client, txHash string) error {
+	_, err := s.waitTransaction(ctx, web3Client, txHash)
+	return err
+}
+
+// waitTransaction polls receipt of transaction until it is mined, nil receipt is returned for empty hash
+func (s *Service) waitTransaction(ctx context.Context, reader receiptReader, txHash string) (*types.Receipt, error) {
 	if txHash == "" {
-		return nil
+		return nil, nil
 	}
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
-			_, err := web3Client.TransactionReceipt(ctx, common.HexToHash(txHash))
+			receipt, err := reader.TransactionReceipt(ctx, common.HexToHash(txHash))
 			if err == nil {
-				return nil
+				return receipt, nil
 			}
 			if !errors.Is(err, ethereum.NotFound) {
-				return fmt.Errorf("unable to get transaction receipt: %w", err)
+				return nil, fmt.Errorf("unable to get transaction receipt: %w", err)
 			}
 			time.Sleep(5 * time.Second)
 		}
 	}
 }
 
-func (s *Service) GetERC20TokenBalance(ctx context.Context, web3Client *ethclient.Client, tokenAddress, address common.Address) (*big.Int, error) {
+func (s *Service) GetERC20TokenBalance(ctx context.Context, web3Client *ethclient.Client, tokenAddress, address common.Address, tag entities.BlockTag) (*big.Int, error) {
 	contract, err := NewErc20(tokenAddress, web3Client)
 	if err != nil {
 		return nil, err
 	}
-	val, err := contract.BalanceOf(&bind.CallOpts{
-		Context: ctx,
-	}, address)
+	val, err := contract.BalanceOf(callOpts(ctx, tag), address)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get balance: %w", err)
 	}
 	return val, nil
 }
 
+// GetAllowance returns amount of token which spender is allowed to spend on behalf of owner
+func (s *Service) GetAllowance(ctx context.Context, web3Client *ethclient.Client, tokenAddress, owner, spender common.Address) (*big.Int, error) {
+	return s.getAllowance(ctx, web3Client, tokenAddress, owner, spender)
+}
+
+func (s *Service) getAllowance(ctx context.Context, caller bind.ContractCaller, tokenAddress, owner, spender common.Address) (*big.Int, error) {
+	contract, err := NewErc20Caller(tokenAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	val, err := contract.Allowance(&bind.CallOpts{Context: ctx}, owner, spender)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get allowance: %w", err)
+	}
+	return val, nil
+}
+
+// IsMaxAllowance reports whether allowance is the one set by ApproveContractUsageALL
+func (s *Service) IsMaxAllowance(allowance *big.Int) bool {
+	return allowance.Cmp(s.maxAllowed) == 0
+}
+
 func (s *Service) GetContractData(ctx context.Context, web3Client *ethclient.Client, tokenAddress common.Address) (ticker string, decimal uint8, err error) {
 	contract, err := NewErc20(tokenAddress, web3Client)
 	if err != nil {
@@ -133,9 +198,7 @@ func (s *Service) GetContractData(ctx context.Context, web3Client *ethclient.Cli
 	)
 	go func() {
 		defer wg.Done()
-		ticker, errTicker = contract.Symbol(&bind.CallOpts{
-			Context: ctx,
-		})
+		ticker, errTicker = s.readTokenString(ctx, web3Client, tokenAddress, "symbol")
 	}()
 	go func() {
 		defer wg.Done()
@@ -152,3 +215,241 @@ func (s *Service) GetContractData(ctx context.Context, web3Client *ethclient.Cli
 	}
 	return ticker, decimal, nil
 }
+
+// GetERC1155Balances returns balances of holder for every id using a single balanceOfBatch call
+func (s *Service) GetERC1155Balances(ctx context.Context, web3Client *ethclient.Client, contractAddress, holder common.Address, ids []*big.Int, tag entities.BlockTag) ([]*big.Int, error) {
+	contract, err := NewErc1155(contractAddress, web3Client)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]common.Address, len(ids))
+	for i := range ids {
+		accounts[i] = holder
+	}
+	val, err := contract.BalanceOfBatch(callOpts(ctx, tag), accounts, ids)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get batch balance: %w", err)
+	}
+	if len(val) != len(ids) {
+		return nil, fmt.Errorf("unexpected batch balance length: got %d, want %d", len(val), len(ids))
+	}
+	return val, nil
+}
+
+// GetERC1155URIs returns metadata uri for every id with `{id}` placeholder already substituted.
+// Metadata extension is optional for ERC-1155, so ids for which `uri` reverts or is not implemented get empty string,
+// other errors fail the whole read. At most maxURIRequests calls are in flight.
+func (s *Service) GetERC1155URIs(ctx context.Context, web3Client *ethclient.Client, contractAddress common.Address, ids []*big.Int) ([]string, error) {
+	return s.getERC1155URIs(ctx, web3Client, contractAddress, ids)
+}
+
+func (s *Service) getERC1155URIs(ctx context.Context, caller bind.ContractCaller, contractAddress common.Address, ids []*big.Int) ([]string, error) {
+	contract, err := NewErc1155Caller(contractAddress, caller)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	result := make([]string, len(ids))
+	var (
+		mu sync.Mutex
+		// failure is first error, calls cancelled after it fail with context error
+		failure error
+	)
+	sem := make(chan struct{}, maxURIRequests)
+	var wg sync.WaitGroup
+	wg.Add(len(ids))
+	for i := range ids {
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			uri, errURI := contract.Uri(&bind.CallOpts{
+				Context: ctx,
+			}, ids[i])
+			if errURI != nil {
+				if !isNotImplemented(errURI) {
+					mu.Lock()
+					if failure == nil {
+						failure = fmt.Errorf("unable to get uri of %s: %w", ids[i].String(), errURI)
+					}
+					mu.Unlock()
+					cancel()
+				}
+				return
+			}
+			result[i] = utils.ResolveTokenURI(uri, ids[i])
+		}(i)
+	}
+	wg.Wait()
+	if failure != nil {
+		return nil, failure
+	}
+	return result, nil
+}
+
+// callOpts builds call options which read state at given block tag
+func callOpts(ctx context.Context, tag entities.BlockTag) *bind.CallOpts {
+	return &bind.CallOpts{
+		Context:     ctx,
+		Pending:     tag == entities.BlockTagPending,
+		BlockNumber: tag.BlockNumber(),
+	}
+}
+
+// GetTransferParticipants returns which of addresses sent or received token in blocks range [fromBlock, toBlock]
+func (s *Service) GetTransferParticipants(ctx context.Context, web3Client *ethclient.Client, tokenAddress common.Address, fromBlock, toBlock uint64, addresses []common.Address) (map[common.Address]struct{}, error) {
+	transfers, err := s.GetTransfers(ctx, web3Client, tokenAddress, fromBlock, toBlock, addresses)
+	if err != nil {
+		return nil, err
+	}
+	watched := make(map[common.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		watched[address] = struct{}{}
+	}
+	result := make(map[common.Address]struct{})
+	for _, transfer := range transfers {
+		for _, participant := range []common.Address{transfer.From, transfer.To} {
+			if _, ok := watched[participant]; ok {
+				result[participant] = struct{}{}
+			}
+		}
+	}
+	return result, nil
+}
+
+// TransferFilter selects transfers by sender and recipient, empty list matches any address
+type TransferFilter struct {
+	From []common.Address
+	To   []common.Address
+}
+
+// GetTransfers returns token transfers sent or received by addresses in blocks range [fromBlock, toBlock] in chain order.
+// Transfer between two of addresses is returned once.
+func (s *Service) GetTransfers(ctx context.Context, web3Client *ethclient.Client, tokenAddress common.Address, fromBlock, toBlock uint64, addresses []common.Address) ([]*Erc20Transfer, error) {
+	// topics are matched with AND between positions, so outgoing and incoming transfers are separate queries
+	return s.FilterTransfers(ctx, web3Client, tokenAddress, fromBlock, toBlock, TransferFilter{From: addresses}, TransferFilter{To: addresses})
+}
+
+// FilterTransfers returns token transfers in blocks range [fromBlock, toBlock] matching any of filters in chain order.
+// Transfer matched by several filters is returned once.
+func (s *Service) FilterTransfers(ctx context.Context, web3Client *ethclient.Client, tokenAddress common.Address, fromBlock, toBlock uint64, filters ...TransferFilter) ([]*Erc20Transfer, error) {
+	filterer, err := NewErc20Filterer(tokenAddress, web3Client)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.FilterOpts{
+		Start:   fromBlock,
+		End:     &toBlock,
+		Context: ctx,
+	}
+	type logID struct {
+		tx    common.Hash
+		index uint
+	}
+	seen := make(map[logID]struct{})
+	result := make([]*Erc20Transfer, 0)
+	for _, filter := range filters {
+		iter, errFilter := filterer.FilterTransfer(opts, filter.From, filter.To)
+		if errFilter != nil {
+			return nil, fmt.Errorf("unable to filter transfers: %w", errFilter)
+		}
+		for iter.Next() {
+			id := logID{tx: iter.Event.Raw.TxHash, index: iter.Event.Raw.Index}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			result = append(result, iter.Event)
+		}
+		if err = iter.Error(); err != nil {
+			return nil, fmt.Errorf("unable to iterate transfers: %w", err)
+		}
+		if err = iter.Close(); err != nil {
+			return nil, fmt.Errorf("unable to close transfers iterator: %w", err)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Raw.BlockNumber != result[j].Raw.BlockNumber {
+			return result[i].Raw.BlockNumber < result[j].Raw.BlockNumber
+		}
+		return result[i].Raw.Index < result[j].Raw.Index
+	})
+	return result, nil
+}
+
+// FilterApprovals returns Approval logs of any erc20 contract given by owner in blocks range [fromBlock, toBlock] in chain order.
+// Generated Erc20Filterer is bound to single contract, so logs are filtered by topics only and decoded with it.
+// Approval of erc721 has the same signature with indexed token id and is skipped.
+func (s *Service) FilterApprovals(ctx context.Context, web3Client *ethclient.Client, owner common.Address, fromBlock, toBlock uint64) ([]*Erc20Approval, error) {
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	filterer, err := NewErc20Filterer(common.Address{}, web3Client)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := web3Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Topics:    [][]common.Hash{{parsed.Events["Approval"].ID}, {common.BytesToHash(owner.Bytes())}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to filter approvals: %w", err)
+	}
+	result := make([]*Erc20Approval, 0, len(logs))
+	for i := range logs {
+		if len(logs[i].Topics) != 3 || logs[i].Removed {
+			continue
+		}
+		approval, errParse := filterer.ParseApproval(logs[i])
+		if errParse != nil {
+			continue
+		}
+		result = append(result, approval)
+	}
+	return result, nil
+}
+
+// GetERC20TokenBalanceAt returns token balance of address at block number
+func (s *Service) GetERC20TokenBalanceAt(ctx context.Context, web3Client *ethclient.Client, tokenAddress, address common.Address, blockNumber uint64) (*big.Int, error) {
+	contract, err := NewErc20Caller(tokenAddress, web3Client)
+	if err != nil {
+		return nil, err
+	}
+	val, err := contract.BalanceOf(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)}, address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get balance: %w", err)
+	}
+	return val, nil
+}
+
+// GetBalance returns balance of holder in native coin of the chain for empty token, otherwise in known erc20 token
+func (s *Service) GetBalance(ctx context.Context, web3Client *ethclient.Client, chain entities.Chain, token entities.Token, holder common.Address, tag entities.BlockTag) (*entities.Balance, error) {
+	var (
+		wei *big.Int
+		err error
+	)
+	if token == "" || token == entities.MapChainToFuel(chain) {
+		token = entities.MapChainToFuel(chain)
+		wei, err = s.GetNativeTokenBalance(ctx, web3Client, holder, tag)
+	} else {
+		var tokenAddress common.Address
+		if tokenAddress, err = entities.GetTokenAddress(chain, token); err != nil {
+			return nil, err
+		}
+		wei, err = s.GetERC20TokenBalance(ctx, web3Client, tokenAddress, holder, tag)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entities.Balance{
+		Chain:           chain,
+		ChainName:       chain.String(),
+		Token:           token,
+		TokenBalance:    entities.CoinFromWEI(token, wei),
+		TokenBalanceWei: wei.String(),
+		BlockTag:        tag,
+	}, nil
+}
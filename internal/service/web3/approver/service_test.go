@@ -1,14 +1,17 @@
 package approver_test
 
 import (
+	"altt/internal/config"
 	"altt/internal/entities"
 	"altt/internal/logger"
 	"altt/internal/service/rpc"
 	"altt/internal/service/web3"
 	"altt/internal/service/web3/approver"
+	"altt/internal/service/web3/gas"
 	"altt/internal/utils"
 	"context"
 	"crypto/ecdsa"
+	"math/big"
 	"os"
 	"testing"
 	"time"
@@ -36,7 +39,9 @@ func TestService_ApproveContractUsage(t *testing.T) {
 	t.Skip("skip test")
 	appLog, err := logger.NewAppLogger("")
 	require.NoError(t, err)
-	service := approver.InitService(appLog)
+	serviceGas, err := gas.NewService(appLog, config.GasConfig{})
+	require.NoError(t, err)
+	service := approver.InitService(appLog, serviceGas)
 	ethClient, privateKey, accAddress := initTest(t)
 
 	tokenAddress, err := entities.GetTokenAddress(targetChain, entities.USDC)
@@ -53,15 +58,45 @@ func TestService_ApproveContractUsage(t *testing.T) {
 	t.Log("approveTxHash:", approveTxHash)
 }
 
+func TestService_ApproveExactAndRevoke(t *testing.T) {
+	t.Skip("skip test")
+	appLog, err := logger.NewAppLogger("")
+	require.NoError(t, err)
+	serviceGas, err := gas.NewService(appLog, config.GasConfig{})
+	require.NoError(t, err)
+	service := approver.InitService(appLog, serviceGas)
+	ethClient, privateKey, accAddress := initTest(t)
+
+	tokenAddress, err := entities.GetTokenAddress(targetChain, entities.USDC)
+	require.NoError(t, err)
+	spender := common.HexToAddress(sampleContract)
+	ctx := context.Background()
+
+	approveTxHash, err := service.ApproveContractUsage(ctx, ethClient, privateKey, tokenAddress, accAddress, spender, big.NewInt(1_000_000))
+	require.NoError(t, err)
+	require.NoError(t, service.WaitTransaction(ctx, ethClient, approveTxHash))
+
+	// the same amount again is skipped
+	approveTxHash, err = service.ApproveContractUsage(ctx, ethClient, privateKey, tokenAddress, accAddress, spender, big.NewInt(1_000_000))
+	require.NoError(t, err)
+	require.Empty(t, approveTxHash)
+
+	results, err := service.RevokeApprovals(ctx, ethClient, privateKey, accAddress, []approver.ApprovalPair{{Token: tokenAddress, Spender: spender}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	t.Log("revokeTxHash:", results[0].TxHash)
+}
+
 func TestService_GetNativeTokenBalance(t *testing.T) {
 	appLog, err := logger.NewAppLogger("")
 	require.NoError(t, err)
-	service := approver.InitService(appLog)
+	service := approver.InitService(appLog, nil)
 	ethClient, _, accAddress := initTest(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	val, err := service.GetNativeTokenBalance(ctx, ethClient, accAddress)
+	val, err := service.GetNativeTokenBalance(ctx, ethClient, accAddress, entities.BlockTagLatest)
 	require.NoError(t, err)
 	t.Log("val:", utils.ETHFromWei(val))
 }
@@ -69,7 +104,7 @@ func TestService_GetNativeTokenBalance(t *testing.T) {
 func TestService_GetERC20TokenBalance(t *testing.T) {
 	appLog, err := logger.NewAppLogger("")
 	require.NoError(t, err)
-	service := approver.InitService(appLog)
+	service := approver.InitService(appLog, nil)
 	ethClient, _, accAddress := initTest(t)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -77,7 +112,7 @@ func TestService_GetERC20TokenBalance(t *testing.T) {
 
 	tokenAddress, err := entities.GetTokenAddress(targetChain, entities.USDC)
 	require.NoError(t, err)
-	val, err := service.GetERC20TokenBalance(ctx, ethClient, tokenAddress, accAddress)
+	val, err := service.GetERC20TokenBalance(ctx, ethClient, tokenAddress, accAddress, entities.BlockTagLatest)
 	require.NoError(t, err)
 	t.Log("val:", entities.CoinFromWEI(entities.USDC, val))
 }
@@ -85,7 +120,7 @@ func TestService_GetERC20TokenBalance(t *testing.T) {
 func TestService_GetContractData(t *testing.T) {
 	appLog, err := logger.NewAppLogger("")
 	require.NoError(t, err)
-	service := approver.InitService(appLog)
+	service := approver.InitService(appLog, nil)
 	ethClient, _, _ := initTest(t)
 
 	require.NoError(t, err)
@@ -0,0 +1,145 @@
+package approver
+
+import (
+	"altt/internal/entities"
+	"altt/internal/utils"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTokenCallReverted  = errors.New("token call reverted")
+	ErrTokenReturnedFalse = errors.New("token call returned false")
+	ErrInvalidTokenReturn = errors.New("token call returned invalid value")
+)
+
+// TransferToken sends amount of token from holder to recipient. Tokens which return nothing from transfer, like USDT,
+// are supported.
+func (s *Service) TransferToken(ctx context.Context, web3Client *ethclient.Client, privateKey *ecdsa.PrivateKey, tokenAddress, holder, to common.Address, amount *big.Int) (string, error) {
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	data, err := parsed.Pack("transfer", to, amount)
+	if err != nil {
+		return "", fmt.Errorf("unable to pack transfer: %w", err)
+	}
+	tx, err := s.sendTokenTx(ctx, web3Client, privateKey, tokenAddress, holder, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to transfer: %w", err)
+	}
+	s.log.Info("transfer sent",
+		zap.String("tokenAddress", tokenAddress.String()),
+		zap.String("to", to.String()),
+		zap.String("amount", amount.String()),
+		zap.String("txHash", tx.Hash().String()),
+	)
+	return tx.Hash().String(), nil
+}
+
+// sendTokenTx checks token call with eth_call and balance of sender at chosen fee and sends it, nil nonce means pending
+// nonce of sender.
+// Transaction is sent with raw data, so return value of token is checked by checkTokenReturn only.
+func (s *Service) sendTokenTx(
+	ctx context.Context,
+	web3Client *ethclient.Client,
+	privateKey *ecdsa.PrivateKey,
+	tokenAddress, from common.Address,
+	data []byte,
+	nonce *big.Int,
+) (*types.Transaction, error) {
+	if err := s.simulateTokenCall(ctx, web3Client, tokenAddress, from, data); err != nil {
+		return nil, err
+	}
+	chainID, err := web3Client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get chain id: %w", err)
+	}
+	chain := entities.Chain(chainID.Uint64())
+	fee, err := s.fees.SuggestTxFee(ctx, web3Client, chain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get transaction fee: %w", err)
+	}
+	if err = s.checkFee(ctx, web3Client, chain, ethereum.CallMsg{From: from, To: &tokenAddress, Data: data}, fee); err != nil {
+		return nil, err
+	}
+	contract := bind.NewBoundContract(tokenAddress, abi.ABI{}, web3Client, web3Client, web3Client)
+	tx, err := contract.RawTransact(&bind.TransactOpts{
+		From:      from,
+		Nonce:     nonce,
+		Context:   ctx,
+		GasPrice:  fee.GasPrice,
+		GasFeeCap: fee.GasFeeCap,
+		GasTipCap: fee.GasTipCap,
+		Signer: func(address common.Address, transaction *types.Transaction) (*types.Transaction, error) {
+			return types.SignTx(transaction, types.LatestSignerForChainID(chainID), privateKey)
+		},
+	}, data)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("tx sent", append([]zap.Field{zap.String("tokenAddress", tokenAddress.String()), zap.String("txHash", tx.Hash().String())}, feeFields(fee)...)...)
+	return tx, nil
+}
+
+// feeFields are log fields of chosen fee in gwei
+func feeFields(fee *entities.TxFee) []zap.Field {
+	if fee.GasPrice != nil {
+		return []zap.Field{zap.String("gas_price", utils.ETHFromGWei(fee.GasPrice))}
+	}
+	return []zap.Field{
+		zap.String("max_fee_per_gas", utils.ETHFromGWei(fee.GasFeeCap)),
+		zap.String("max_priority_fee_per_gas", utils.ETHFromGWei(fee.GasTipCap)),
+	}
+}
+
+// simulateTokenCall executes token call with eth_call from sender. Generated bindings fail to unpack empty output,
+// so call is low-level and output is checked by checkTokenReturn.
+func (s *Service) simulateTokenCall(ctx context.Context, caller bind.ContractCaller, tokenAddress, from common.Address, data []byte) error {
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{From: from, To: &tokenAddress, Data: data}, nil)
+	if err != nil {
+		if isRevert(err) {
+			return fmt.Errorf("%w: %s", ErrTokenCallReverted, err)
+		}
+		return fmt.Errorf("unable to call token: %w", err)
+	}
+	if len(output) == 0 {
+		// call of address without code succeeds with empty output as well
+		code, errCode := caller.CodeAt(ctx, tokenAddress, nil)
+		if errCode != nil {
+			return fmt.Errorf("unable to get code: %w", errCode)
+		}
+		if len(code) == 0 {
+			return bind.ErrNoCode
+		}
+	}
+	return checkTokenReturn(output)
+}
+
+// checkTokenReturn accepts empty output of tokens without return value and true, like SafeERC20 does
+func checkTokenReturn(output []byte) error {
+	if len(output) == 0 {
+		return nil
+	}
+	if len(output) < common.HashLength {
+		return fmt.Errorf("%w: %x", ErrInvalidTokenReturn, output)
+	}
+	switch value := new(big.Int).SetBytes(output[:common.HashLength]); {
+	case value.Cmp(common.Big1) == 0:
+		return nil
+	case value.Sign() == 0:
+		return ErrTokenReturnedFalse
+	}
+	return fmt.Errorf("%w: %x", ErrInvalidTokenReturn, output[:common.HashLength])
+}
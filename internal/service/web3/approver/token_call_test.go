@@ -0,0 +1,98 @@
+package approver
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// revertError is json-rpc error of reverted eth_call
+type revertError struct{}
+
+func (revertError) Error() string  { return "execution reverted" }
+func (revertError) ErrorCode() int { return 3 }
+
+// fakeToken approves like USDT on ethereum: no return value and revert on change of non-zero allowance
+type fakeToken struct {
+	allowance *big.Int
+	noCode    bool
+	callErr   error
+}
+
+func (f *fakeToken) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	if f.noCode {
+		return nil, nil
+	}
+	return []byte{1}, nil
+}
+
+func (f *fakeToken) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if f.noCode || f.callErr != nil {
+		return nil, f.callErr
+	}
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	args, err := parsed.Methods["approve"].Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	if value := args[1].(*big.Int); value.Sign() != 0 && f.allowance.Sign() != 0 {
+		return nil, revertError{}
+	}
+	return nil, nil
+}
+
+func TestCheckTokenReturn(t *testing.T) {
+	require.NoError(t, checkTokenReturn(nil))
+	require.NoError(t, checkTokenReturn(common.BigToHash(big.NewInt(1)).Bytes()))
+	require.ErrorIs(t, checkTokenReturn(make([]byte, 32)), ErrTokenReturnedFalse)
+	require.ErrorIs(t, checkTokenReturn(common.BigToHash(big.NewInt(2)).Bytes()), ErrInvalidTokenReturn)
+	require.ErrorIs(t, checkTokenReturn([]byte{1}), ErrInvalidTokenReturn)
+}
+
+func TestService_NeedsZeroReset(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+	token := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	holder := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	spender := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	t.Run("non-zero allowance is reset", func(t *testing.T) {
+		reset, err := srv.needsZeroReset(context.Background(), &fakeToken{allowance: big.NewInt(5)}, token, holder, spender, big.NewInt(10))
+		require.NoError(t, err)
+		require.True(t, reset)
+	})
+
+	t.Run("zero allowance is approved directly", func(t *testing.T) {
+		reset, err := srv.needsZeroReset(context.Background(), &fakeToken{allowance: new(big.Int)}, token, holder, spender, big.NewInt(10))
+		require.NoError(t, err)
+		require.False(t, reset)
+	})
+
+	t.Run("rpc failure is not revert", func(t *testing.T) {
+		_, err := srv.needsZeroReset(context.Background(), &fakeToken{allowance: big.NewInt(5), callErr: rpcError{code: -32005, message: "limit exceeded"}}, token, holder, spender, big.NewInt(10))
+		require.Error(t, err)
+		require.False(t, errors.Is(err, ErrTokenCallReverted))
+	})
+
+	t.Run("address without code", func(t *testing.T) {
+		_, err := srv.needsZeroReset(context.Background(), &fakeToken{noCode: true}, token, holder, spender, big.NewInt(10))
+		require.True(t, errors.Is(err, bind.ErrNoCode))
+	})
+}
+
+func TestMaxTxCost(t *testing.T) {
+	require.Equal(t, big.NewInt(21_000*30+500), maxTxCost(21_000, &entities.TxFee{GasFeeCap: big.NewInt(30), GasTipCap: big.NewInt(2)}, big.NewInt(500), nil))
+	require.Equal(t, big.NewInt(21_000*40+7), maxTxCost(21_000, &entities.TxFee{GasPrice: big.NewInt(40)}, new(big.Int), big.NewInt(7)))
+}
@@ -0,0 +1,111 @@
+package approver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var errInvalidTokenString = errors.New("token string is neither string nor bytes32")
+
+// ContractInfo is erc20 metadata read from contract
+type ContractInfo struct {
+	Name        string
+	Symbol      string
+	Decimals    uint8
+	TotalSupply *big.Int
+}
+
+// GetContractInfo returns name, symbol, decimals and total supply of erc20 contract. name is optional in erc20,
+// contracts without it have empty Name.
+func (s *Service) GetContractInfo(ctx context.Context, web3Client *ethclient.Client, tokenAddress common.Address) (*ContractInfo, error) {
+	contract, err := NewErc20Caller(tokenAddress, web3Client)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		info                                 ContractInfo
+		errName, errSymbol, errDec, errTotal error
+		wg                                   sync.WaitGroup
+	)
+	wg.Add(4)
+	go func() {
+		defer wg.Done()
+		info.Name, errName = s.readTokenString(ctx, web3Client, tokenAddress, "name")
+	}()
+	go func() {
+		defer wg.Done()
+		info.Symbol, errSymbol = s.readTokenString(ctx, web3Client, tokenAddress, "symbol")
+	}()
+	go func() {
+		defer wg.Done()
+		info.Decimals, errDec = contract.Decimals(&bind.CallOpts{Context: ctx})
+	}()
+	go func() {
+		defer wg.Done()
+		info.TotalSupply, errTotal = contract.TotalSupply(&bind.CallOpts{Context: ctx})
+	}()
+	wg.Wait()
+	if errDec != nil {
+		return nil, fmt.Errorf("unable to get decimal: %w", errDec)
+	}
+	if errTotal != nil {
+		return nil, fmt.Errorf("unable to get total supply: %w", errTotal)
+	}
+	if errSymbol != nil {
+		return nil, fmt.Errorf("unable to get ticker: %w", errSymbol)
+	}
+	if errName != nil {
+		info.Name = ""
+	}
+	return &info, nil
+}
+
+// readTokenString calls name or symbol of erc20 contract. Old tokens like MKR return bytes32 instead of string,
+// generated binding fails on them, so result is decoded here.
+func (s *Service) readTokenString(ctx context.Context, web3Client *ethclient.Client, tokenAddress common.Address, method string) (string, error) {
+	parsed, err := Erc20MetaData.GetAbi()
+	if err != nil {
+		return "", err
+	}
+	input, err := parsed.Pack(method)
+	if err != nil {
+		return "", err
+	}
+	output, err := web3Client.CallContract(ctx, ethereum.CallMsg{To: &tokenAddress, Data: input}, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(output) == 0 {
+		code, errCode := web3Client.CodeAt(ctx, tokenAddress, nil)
+		if errCode == nil && len(code) == 0 {
+			return "", bind.ErrNoCode
+		}
+	}
+	unpacked, err := parsed.Unpack(method, output)
+	if err == nil {
+		return unpacked[0].(string), nil
+	}
+	return decodeBytes32String(output)
+}
+
+// decodeBytes32String decodes bytes32 right padded with zeros
+func decodeBytes32String(output []byte) (string, error) {
+	if len(output) != 32 {
+		return "", errInvalidTokenString
+	}
+	value := bytes.TrimRight(output, "\x00")
+	if !utf8.Valid(value) {
+		return "", errInvalidTokenString
+	}
+	return string(value), nil
+}
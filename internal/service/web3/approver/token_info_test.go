@@ -0,0 +1,25 @@
+package approver
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBytes32String(t *testing.T) {
+	// given symbol of MKR token
+	output := common.RightPadBytes([]byte("MKR"), 32)
+
+	// when
+	symbol, err := decodeBytes32String(output)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "MKR", symbol)
+
+	_, err = decodeBytes32String(output[:31])
+	require.ErrorIs(t, err, errInvalidTokenString)
+	_, err = decodeBytes32String(common.RightPadBytes([]byte{0xff, 0xfe}, 32))
+	require.ErrorIs(t, err, errInvalidTokenString)
+}
@@ -0,0 +1,91 @@
+package approver
+
+import (
+	"altt/internal/logger"
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMultiToken answers uri of ids, ids listed in failures fail with the error
+type fakeMultiToken struct {
+	failures map[int64]error
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (f *fakeMultiToken) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (f *fakeMultiToken) CallContract(ctx context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	f.inFlight++
+	if f.inFlight > f.peak {
+		f.peak = f.inFlight
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+
+	parsed, err := Erc1155MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	args, err := parsed.Methods["uri"].Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	if errCall, ok := f.failures[args[0].(*big.Int).Int64()]; ok {
+		return nil, errCall
+	}
+	return parsed.Methods["uri"].Outputs.Pack("ipfs://meta/{id}.json")
+}
+
+func TestService_GetERC1155URIs(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := InitService(appLog, nil)
+	contract := common.HexToAddress("0x76BE3b62873462d2142405439777e971754E8E77")
+	ids := make([]*big.Int, 50)
+	for i := range ids {
+		ids[i] = big.NewInt(int64(i))
+	}
+
+	t.Run("reverted uri is empty", func(t *testing.T) {
+		// given
+		token := &fakeMultiToken{failures: map[int64]error{1: rpcError{code: 3, message: "execution reverted"}}}
+
+		// when
+		uris, err := srv.getERC1155URIs(context.Background(), token, contract, ids)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "ipfs://meta/"+common.BigToHash(big.NewInt(0)).Hex()[2:]+".json", uris[0])
+		require.Empty(t, uris[1])
+		require.LessOrEqual(t, token.peak, maxURIRequests)
+	})
+
+	t.Run("rpc failure is returned", func(t *testing.T) {
+		// given
+		token := &fakeMultiToken{failures: map[int64]error{7: rpcError{code: 429, message: "too many requests"}}}
+
+		// when
+		_, err := srv.getERC1155URIs(context.Background(), token, contract, ids)
+
+		// then
+		require.ErrorContains(t, err, "too many requests")
+	})
+}
@@ -0,0 +1,34 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetAccountInfo classifies address as EOA, contract, proxy or known smart account by eth_getCode, nonce and proxy
+// storage slots. Address without code is EOA, also when it is counterfactual smart account which is not deployed yet.
+func (s *Service) GetAccountInfo(ctx context.Context, chain entities.Chain, address common.Address) (*entities.AccountInfo, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, fmt.Errorf("chain %s is not available", chain.String())
+	}
+	resp, err := s.group.Do(ctx, "account-"+chain.String()+"-"+address.String(), func(ctx context.Context) (interface{}, error) {
+		client, err := web3.GetWeb3Client(chain)
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+		return s.erc20.GetAccountInfo(ctx, client, address)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get account info: %w", err)
+	}
+	info := *resp.(*entities.AccountInfo) // use unsafe cast here as we know that it's result of group
+	info.Chain = chain
+	info.ChainName = chain.String()
+	return &info, nil
+}
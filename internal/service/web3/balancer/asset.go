@@ -0,0 +1,102 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+// GetAssetBalance returns balance of canonical asset summed across configured chains and bridged variants.
+// Chains which failed to respond are listed in FailedChains and are not part of total.
+func (s *Service) GetAssetBalance(ctx context.Context, asset entities.Token, holder common.Address, tag entities.BlockTag) *entities.AssetBalance {
+	members := make([]entities.AssetMember, 0)
+	for _, member := range entities.GetAssetMembers(asset) {
+		if rpc.ChainAvailable(member.Chain) {
+			members = append(members, member)
+		}
+	}
+	balances := make([]*entities.Balance, len(members))
+	var wg sync.WaitGroup
+	for i := range members {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			member := members[i]
+			var err error
+			if member.Native {
+				balances[i], err = s.GetNativeBalance(ctx, member.Chain, holder, tag)
+			} else {
+				balances[i], err = s.GetKnownTokenBalance(ctx, member.Token, member.Chain, holder, tag)
+			}
+			if err != nil {
+				balances[i] = nil
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return sumAssetBalances(asset, holder, tag, members, balances)
+}
+
+// sumAssetBalances sums balances of asset members, nil balance is failed read. Chain is listed in FailedChains once
+// even if several members on it failed. BlockTag is the least final tag used by chains, as fallback may differ per chain.
+func sumAssetBalances(
+	asset entities.Token,
+	holder common.Address,
+	tag entities.BlockTag,
+	members []entities.AssetMember,
+	balances []*entities.Balance,
+) *entities.AssetBalance {
+	result := &entities.AssetBalance{
+		Asset:    asset,
+		Holder:   holder,
+		BlockTag: tag,
+		Chains:   make([]*entities.Balance, 0, len(members)),
+	}
+	failed := make(map[entities.Chain]struct{})
+	total, totalUSD := decimal.Zero, decimal.Zero
+	valued, usedTag := true, entities.BlockTag("")
+	for i, balance := range balances {
+		var amount decimal.Decimal
+		if balance != nil {
+			var err error
+			if amount, err = decimal.NewFromString(balance.TokenBalance); err != nil {
+				balance = nil
+			}
+		}
+		if balance == nil {
+			if _, ok := failed[members[i].Chain]; !ok {
+				failed[members[i].Chain] = struct{}{}
+				result.FailedChains = append(result.FailedChains, members[i].Chain.String())
+			}
+			continue
+		}
+		result.Chains = append(result.Chains, balance)
+		if usedTag == "" {
+			usedTag = balance.BlockTag
+		}
+		usedTag = entities.LeastFinal(usedTag, balance.BlockTag)
+		total = total.Add(amount)
+		if amount.IsZero() {
+			continue
+		}
+		usdValue, err := decimal.NewFromString(balance.USDValue)
+		if err != nil {
+			valued = false
+			continue
+		}
+		totalUSD = totalUSD.Add(usdValue)
+	}
+	if usedTag != "" {
+		result.BlockTag = usedTag
+	}
+	result.TotalBalance = total.String()
+	if valued {
+		result.USDValue = totalUSD.StringFixed(2)
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumAssetBalances(t *testing.T) {
+	// given
+	holder := common.HexToAddress("0xDf8ac28156209F5cbc89cDec419dd3dB3D3E326a")
+	members := []entities.AssetMember{
+		{Chain: entities.ChainEthereum, Token: entities.AgEUR},
+		{Chain: entities.ChainPolygon, Token: entities.AgEUR},
+		{Chain: entities.ChainPolygon, Token: entities.LZAgEUR, Bridged: true},
+		{Chain: entities.ChainArbitrum, Token: entities.AgEUR},
+		{Chain: entities.ChainOptimism, Token: entities.AgEUR},
+	}
+	balances := []*entities.Balance{
+		{Chain: entities.ChainEthereum, TokenBalance: "1.5", USDValue: "1.60", BlockTag: entities.BlockTagFinalized},
+		nil,
+		nil,
+		{Chain: entities.ChainArbitrum, TokenBalance: "2", USDValue: "2.15", BlockTag: entities.BlockTagLatest},
+		{Chain: entities.ChainOptimism, TokenBalance: "not a number", BlockTag: entities.BlockTagFinalized},
+	}
+
+	// when
+	result := sumAssetBalances(entities.AgEUR, holder, entities.BlockTagFinalized, members, balances)
+
+	// then
+	require.Equal(t, "3.5", result.TotalBalance)
+	require.Equal(t, "3.75", result.USDValue)
+	require.Len(t, result.Chains, 2)
+	require.Equal(t, []string{entities.ChainPolygon.String(), entities.ChainOptimism.String()}, result.FailedChains)
+	require.Equal(t, entities.BlockTagLatest, result.BlockTag)
+}
+
+func TestSumAssetBalances_NoChains(t *testing.T) {
+	result := sumAssetBalances(entities.AgEUR, common.Address{}, entities.BlockTagSafe, nil, nil)
+	require.Equal(t, entities.BlockTagSafe, result.BlockTag)
+	require.Equal(t, "0", result.TotalBalance)
+}
@@ -0,0 +1,83 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// invalidParamsCode is json-rpc code of request with unsupported params
+const invalidParamsCode = -32602
+
+type taggedBalance struct {
+	value *big.Int
+	tag   entities.BlockTag
+	// block is set when balance is served by indexer
+	block uint64
+}
+
+// withTagFallback runs read at requested tag. If endpoint rejects the tag (chain has no finality
+// or node does not expose it) read is repeated with next closest tag. Returns tag which was actually used.
+func (s *Service) withTagFallback(tag entities.BlockTag, read func(tag entities.BlockTag) error) (entities.BlockTag, error) {
+	for {
+		err := read(tag)
+		if err == nil {
+			return tag, nil
+		}
+		next, ok := tag.Fallback()
+		if !ok || !isTagRejection(err) {
+			return tag, err
+		}
+		s.log.Info("block tag rejected by node, fallback",
+			zap.String("tag", string(tag)),
+			zap.String("fallback", string(next)),
+			zap.String("reason", err.Error()),
+		)
+		tag = next
+	}
+}
+
+// balanceWithFallback is withTagFallback for single balance reads.
+func (s *Service) balanceWithFallback(tag entities.BlockTag, read func(tag entities.BlockTag) (*big.Int, error)) (*taggedBalance, error) {
+	var val *big.Int
+	usedTag, err := s.withTagFallback(tag, func(tag entities.BlockTag) (err error) {
+		val, err = read(tag)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &taggedBalance{value: val, tag: usedTag}, nil
+}
+
+// tagRejectionMessages are messages of nodes which do not support requested block tag
+var tagRejectionMessages = []string{
+	"unknown block",
+	"invalid block tag",
+	"invalid block number",
+	"finalized block not found",
+	"safe block not found",
+}
+
+// isTagRejection reports whether node rejected block tag itself. Other json-rpc errors (rate limit,
+// header not found, reverted call) are not a reason to read at less safe tag.
+func isTagRejection(err error) bool {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	if rpcErr.ErrorCode() == invalidParamsCode {
+		return true
+	}
+	message := strings.ToLower(rpcErr.Error())
+	for _, rejection := range tagRejectionMessages {
+		if strings.Contains(message, rejection) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rpcError is json-rpc error as returned by node
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e rpcError) Error() string  { return e.message }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestIsTagRejection(t *testing.T) {
+	table := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"invalid params":      {err: rpcError{code: -32602, message: "invalid argument 1: hex string without 0x prefix"}, expected: true},
+		"unknown block":       {err: rpcError{code: -32000, message: "Unknown block"}, expected: true},
+		"finalized not found": {err: fmt.Errorf("wrapped: %w", rpcError{code: -32000, message: "finalized block not found"}), expected: true},
+		"rate limit":          {err: rpcError{code: -32005, message: "too many requests"}, expected: false},
+		"header not found":    {err: rpcError{code: -32000, message: "header not found"}, expected: false},
+		"execution reverted":  {err: rpcError{code: 3, message: "execution reverted"}, expected: false},
+		"transport failure":   {err: errors.New("connection refused"), expected: false},
+	}
+	for name, tc := range table {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, isTagRejection(tc.err))
+		})
+	}
+}
+
+func TestService_WithTagFallback(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := &Service{log: appLog}
+
+	t.Run("rejected tag falls back", func(t *testing.T) {
+		used, err := srv.withTagFallback(entities.BlockTagFinalized, func(tag entities.BlockTag) error {
+			if tag == entities.BlockTagFinalized {
+				return rpcError{code: -32000, message: "finalized block not found"}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, entities.BlockTagSafe, used)
+	})
+
+	t.Run("transient error is returned", func(t *testing.T) {
+		used, err := srv.withTagFallback(entities.BlockTagFinalized, func(entities.BlockTag) error {
+			return rpcError{code: -32005, message: "rate limit exceeded"}
+		})
+		require.Error(t, err)
+		require.Equal(t, entities.BlockTagFinalized, used)
+	})
+}
@@ -0,0 +1,85 @@
+package balancer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultCallTimeout = 15 * time.Second
+
+// flightGroup coalesces concurrent calls with same key into one upstream call.
+// Unlike singleflight upstream call is not bound to context of the caller who started it:
+// it runs on detached context with own deadline, while every waiter stops waiting on own context.
+// Successful result is reused for resultTTL after completion.
+type flightGroup struct {
+	callTimeout time.Duration
+	resultTTL   time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	val  interface{}
+	err  error
+}
+
+func newFlightGroup(callTimeout, resultTTL time.Duration) *flightGroup {
+	if callTimeout <= 0 {
+		callTimeout = defaultCallTimeout
+	}
+	return &flightGroup{
+		callTimeout: callTimeout,
+		resultTTL:   resultTTL,
+		calls:       make(map[string]*flightCall),
+	}
+}
+
+// Do returns result of fn for key, joining in-flight or recently completed call if there is one.
+func (g *flightGroup) Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.run(key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *flightGroup) run(key string, call *flightCall, fn func(ctx context.Context) (interface{}, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.callTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			call.val, call.err = nil, fmt.Errorf("flight call panic: %v", r)
+		}
+		if call.err != nil || g.resultTTL <= 0 {
+			g.forget(key, call)
+		} else {
+			time.AfterFunc(g.resultTTL, func() {
+				g.forget(key, call)
+			})
+		}
+		close(call.done)
+	}()
+	call.val, call.err = fn(ctx)
+}
+
+func (g *flightGroup) forget(key string, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
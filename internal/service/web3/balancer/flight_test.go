@@ -0,0 +1,84 @@
+package balancer
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightGroup_CallerCancellation(t *testing.T) {
+	// given
+	group := newFlightGroup(time.Second, 0)
+	release := make(chan struct{})
+	var calls int32
+	fn := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-release:
+			return "ok", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// when
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := group.Do(firstCtx, "key", fn)
+		firstErr <- err
+	}()
+	secondRes := make(chan interface{}, 1)
+	go func() {
+		res, _ := group.Do(context.Background(), "key", fn)
+		secondRes <- res
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	// then
+	require.ErrorIs(t, <-firstErr, context.Canceled)
+	close(release)
+	require.Equal(t, "ok", <-secondRes)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestFlightGroup_CallTimeout(t *testing.T) {
+	// given
+	group := newFlightGroup(50*time.Millisecond, 0)
+
+	// when
+	_, err := group.Do(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	// then
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFlightGroup_ResultTTL(t *testing.T) {
+	// given
+	group := newFlightGroup(time.Second, 100*time.Millisecond)
+	var calls int32
+	fn := func(ctx context.Context) (interface{}, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}
+
+	// when
+	first, err := group.Do(context.Background(), "key", fn)
+	require.NoError(t, err)
+	second, err := group.Do(context.Background(), "key", fn)
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+	third, err := group.Do(context.Background(), "key", fn)
+	require.NoError(t, err)
+
+	// then
+	require.Equal(t, int32(1), first)
+	require.Equal(t, int32(1), second)
+	require.Equal(t, int32(2), third)
+}
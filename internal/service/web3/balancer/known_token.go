@@ -13,25 +13,35 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func (s *Service) GetKnownTokenBalance(ctx context.Context, token entities.Token, chain entities.Chain, holder common.Address) (*entities.Balance, error) {
+func (s *Service) GetKnownTokenBalance(ctx context.Context, token entities.Token, chain entities.Chain, holder common.Address, tag entities.BlockTag) (*entities.Balance, error) {
 	s.metrics.NewTokenBalanceRequest(holder, token)
 	if !rpc.ChainAvailable(chain) {
 		return nil, fmt.Errorf("chain %s is not available", chain.String())
 	}
-	resp, err := s.group.Do(getKnownKey(token, chain, holder), func() (interface{}, error) {
-		connector, err := web3.GetConnector(chain)
-		if err != nil {
-			return nil, err
+	if tag == entities.BlockTagLatest {
+		if value, block, ok := s.indexer.GetBalance(chain, token, holder); ok {
+			return s.knownTokenResult(ctx, token, chain, &taggedBalance{value: value, tag: tag, block: block}), nil
 		}
-		client, err := connector.GetWeb3()
-		if err != nil {
-			return nil, fmt.Errorf("unable to get web3 client: %w", err)
-		}
-		tokenAddress, err := entities.GetTokenAddress(connector.GetChainID(), token)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get token address: %w", err)
-		}
-		return s.erc20.GetERC20TokenBalance(ctx, client, tokenAddress, holder)
+	}
+	key := getKnownKey(token, chain, holder, tag)
+	resp, err := s.group.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return s.withSnapshot(key, func() (*taggedBalance, error) {
+			connector, err := web3.GetConnector(chain)
+			if err != nil {
+				return nil, err
+			}
+			client, err := connector.GetWeb3()
+			if err != nil {
+				return nil, fmt.Errorf("unable to get web3 client: %w", err)
+			}
+			tokenAddress, err := entities.GetTokenAddress(connector.GetChainID(), token)
+			if err != nil {
+				return nil, fmt.Errorf("unable to get token address: %w", err)
+			}
+			return s.balanceWithFallback(tag, func(tag entities.BlockTag) (*big.Int, error) {
+				return s.erc20.GetERC20TokenBalance(ctx, client, tokenAddress, holder, tag)
+			})
+		})
 	})
 	if err != nil {
 		s.log.Error("failed to get native balance",
@@ -42,15 +52,27 @@ func (s *Service) GetKnownTokenBalance(ctx context.Context, token entities.Token
 		)
 		return nil, fmt.Errorf("failed to get native balance")
 	}
-	return &entities.Balance{
+	balance := resp.(*taggedBalance) // use unsafe cast here as we know that it's result of group
+	if balance.tag == entities.BlockTagLatest {
+		s.indexer.Track(chain, token, holder)
+	}
+	return s.knownTokenResult(ctx, token, chain, balance), nil
+}
+
+func (s *Service) knownTokenResult(ctx context.Context, token entities.Token, chain entities.Chain, balance *taggedBalance) *entities.Balance {
+	result := &entities.Balance{
 		Chain:           chain,
 		ChainName:       chain.String(),
 		Token:           token,
-		TokenBalance:    entities.CoinFromWEI(token, resp.(*big.Int)),
-		TokenBalanceWei: resp.(*big.Int).String(), // use unsafe cast here as we know that it's result of group
-	}, nil
+		TokenBalance:    entities.CoinFromWEI(token, balance.value),
+		TokenBalanceWei: balance.value.String(),
+		BlockTag:        balance.tag,
+		BlockNumber:     balance.block,
+	}
+	s.pricing.Valuate(ctx, result)
+	return result
 }
 
-func getKnownKey(token entities.Token, chain entities.Chain, address common.Address) string {
-	return fmt.Sprintf("%s-%s-%s", string(token), chain.String(), address.String())
+func getKnownKey(token entities.Token, chain entities.Chain, address common.Address, tag entities.BlockTag) string {
+	return fmt.Sprintf("%s-%s-%s-%s", string(token), chain.String(), address.String(), tag)
 }
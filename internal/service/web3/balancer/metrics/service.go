@@ -18,6 +18,7 @@ type Service struct {
 	uniqueTokens        *prometheus.GaugeVec
 	totalNativeRequests prometheus.Counter
 	totalTokenRequests  prometheus.Counter
+	totalMultiRequests  prometheus.Counter
 
 	reg prometheus.Registerer
 }
@@ -30,6 +31,7 @@ func IniMetrics(disableMetrics bool) *Service {
 	if !disableMetrics {
 		srv.totalNativeRequests = srv.registerCounter("total_native_requests", "Total number of requests for native token")
 		srv.totalTokenRequests = srv.registerCounter("total_token_requests", "Total number of requests for custom token")
+		srv.totalMultiRequests = srv.registerCounter("total_multi_token_requests", "Total number of requests for erc1155 tokens")
 		srv.uniqueAddresses = srv.registerGauge("unique_addresses", "Total number of unique addresses", []string{"address"})
 		srv.uniqueTokens = srv.registerGauge("unique_tokens", "Total number of unique tokens", []string{"token"})
 	}
@@ -68,3 +70,11 @@ func (s *Service) NewTokenBalanceRequest(address common.Address, token entities.
 	s.uniqueAddresses.WithLabelValues(address.String()).Inc()
 	s.uniqueTokens.WithLabelValues(string(token)).Inc()
 }
+
+func (s *Service) NewMultiTokenBalanceRequest(address common.Address) {
+	if s.disableMetrics {
+		return
+	}
+	s.totalMultiRequests.Inc()
+	s.uniqueAddresses.WithLabelValues(address.String()).Inc()
+}
@@ -0,0 +1,85 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+type multiTokenResult struct {
+	balances []*big.Int
+	uris     []string
+	tag      entities.BlockTag
+}
+
+// GetMultiTokenBalances returns ERC-1155 balances of holder for given ids with resolved metadata uri.
+func (s *Service) GetMultiTokenBalances(ctx context.Context, chain entities.Chain, contract, holder common.Address, ids []*big.Int, tag entities.BlockTag) (*entities.MultiTokenBalance, error) {
+	s.metrics.NewMultiTokenBalanceRequest(holder)
+	if !rpc.ChainAvailable(chain) {
+		return nil, fmt.Errorf("chain %s is not available", chain.String())
+	}
+	resp, err := s.group.Do(ctx, getMultiTokenKey(chain, contract, holder, ids, tag), func(ctx context.Context) (interface{}, error) {
+		connector, err := web3.GetConnector(chain)
+		if err != nil {
+			return nil, err
+		}
+		client, err := connector.GetWeb3()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get web3 client: %w", err)
+		}
+		var balances []*big.Int
+		usedTag, err := s.withTagFallback(tag, func(tag entities.BlockTag) (err error) {
+			balances, err = s.erc20.GetERC1155Balances(ctx, client, contract, holder, ids, tag)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		uris, err := s.erc20.GetERC1155URIs(ctx, client, contract, ids)
+		if err != nil {
+			return nil, err
+		}
+		return &multiTokenResult{balances: balances, uris: uris, tag: usedTag}, nil
+	})
+	if err != nil {
+		s.log.Error("failed to get multi token balance",
+			err,
+			zap.String("contract", contract.String()),
+			zap.String("chain", chain.String()),
+			zap.String("address", holder.String()),
+		)
+		return nil, fmt.Errorf("failed to get multi token balance")
+	}
+	res := resp.(*multiTokenResult) // use unsafe cast here as we know that it's result of group
+	tokens := make([]entities.MultiTokenBalanceItem, 0, len(ids))
+	for i := range ids {
+		tokens = append(tokens, entities.MultiTokenBalanceItem{
+			ID:          ids[i].String(),
+			Balance:     res.balances[i].String(),
+			MetadataURI: res.uris[i],
+		})
+	}
+	return &entities.MultiTokenBalance{
+		Chain:     chain,
+		ChainName: chain.String(),
+		Contract:  contract.String(),
+		Holder:    holder.String(),
+		BlockTag:  res.tag,
+		Tokens:    tokens,
+	}, nil
+}
+
+func getMultiTokenKey(chain entities.Chain, contract, address common.Address, ids []*big.Int, tag entities.BlockTag) string {
+	idList := make([]string, 0, len(ids))
+	for _, id := range ids {
+		idList = append(idList, id.String())
+	}
+	return fmt.Sprintf("%s-%s-%s-%s-%s", chain.String(), contract.String(), address.String(), strings.Join(idList, ","), tag)
+}
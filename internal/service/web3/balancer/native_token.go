@@ -14,21 +14,26 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func (s *Service) GetNativeBalance(ctx context.Context, chain entities.Chain, holder common.Address) (*entities.Balance, error) {
+func (s *Service) GetNativeBalance(ctx context.Context, chain entities.Chain, holder common.Address, tag entities.BlockTag) (*entities.Balance, error) {
 	s.metrics.NewNativeBalanceRequest(holder)
 	if !rpc.ChainAvailable(chain) {
 		return nil, fmt.Errorf("chain %s is not available", chain.String())
 	}
-	resp, err := s.group.Do(getNativeKey(chain, holder), func() (interface{}, error) {
-		connector, err := web3.GetConnector(chain)
-		if err != nil {
-			return nil, err
-		}
-		client, err := connector.GetWeb3()
-		if err != nil {
-			return nil, err
-		}
-		return client.BalanceAt(ctx, holder, nil)
+	key := getNativeKey(chain, holder, tag)
+	resp, err := s.group.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return s.withSnapshot(key, func() (*taggedBalance, error) {
+			connector, err := web3.GetConnector(chain)
+			if err != nil {
+				return nil, err
+			}
+			client, err := connector.GetWeb3()
+			if err != nil {
+				return nil, err
+			}
+			return s.balanceWithFallback(tag, func(tag entities.BlockTag) (*big.Int, error) {
+				return s.erc20.GetNativeTokenBalance(ctx, client, holder, tag)
+			})
+		})
 	})
 	if err != nil {
 		s.log.Error("failed to get native balance",
@@ -38,15 +43,19 @@ func (s *Service) GetNativeBalance(ctx context.Context, chain entities.Chain, ho
 		)
 		return nil, fmt.Errorf("failed to get native balance")
 	}
-	return &entities.Balance{
+	balance := resp.(*taggedBalance) // use unsafe cast here as we know that it's result of group
+	result := &entities.Balance{
 		Chain:           chain,
 		ChainName:       chain.String(),
 		Token:           entities.MapChainToFuel(chain),
-		TokenBalance:    utils.ETHFromWei(resp.(*big.Int)), // use unsafe cast here as we know that it's result of group
-		TokenBalanceWei: resp.(*big.Int).String(),
-	}, nil
+		TokenBalance:    utils.ETHFromWei(balance.value),
+		TokenBalanceWei: balance.value.String(),
+		BlockTag:        balance.tag,
+	}
+	s.pricing.Valuate(ctx, result)
+	return result, nil
 }
 
-func getNativeKey(chain entities.Chain, address common.Address) string {
-	return fmt.Sprintf("%s-%s", chain.String(), address.String())
+func getNativeKey(chain entities.Chain, address common.Address, tag entities.BlockTag) string {
+	return fmt.Sprintf("%s-%s-%s", chain.String(), address.String(), tag)
 }
@@ -1,25 +1,66 @@
 package balancer
 
 import (
+	"altt/internal/config"
+	"altt/internal/entities"
 	"altt/internal/logger"
 	"altt/internal/service/web3/approver"
 	"altt/internal/service/web3/balancer/metrics"
+	"altt/internal/service/web3/indexer"
+	"altt/internal/service/web3/pricing"
+	"altt/internal/service/web3/verifier"
+	"altt/internal/storage"
+	"time"
 
-	"github.com/golang/groupcache/singleflight"
 	"go.uber.org/zap"
 )
 
 type Service struct {
-	group   singleflight.Group
-	erc20   *approver.Service
-	metrics *metrics.Service
-	log     logger.AppLogger
+	group    *flightGroup
+	erc20    *approver.Service
+	pricing  *pricing.Service
+	verifier *verifier.Service
+	indexer  *indexer.Service
+	metrics  *metrics.Service
+	storage  *storage.Repository
+	log      logger.AppLogger
+
+	resultTTL     time.Duration
+	flushInterval time.Duration
+	snapshots     snapshotBuffer
+	stop          chan struct{}
+	done          chan struct{}
 }
 
-func NewService(log logger.AppLogger, erc20 *approver.Service, disableMetrics bool) *Service {
+func NewService(
+	log logger.AppLogger,
+	erc20 *approver.Service,
+	pricingService *pricing.Service,
+	verifierService *verifier.Service,
+	indexerService *indexer.Service,
+	repository *storage.Repository,
+	disableMetrics bool,
+	conf config.BalancerConfig,
+) *Service {
+	if conf.SnapshotFlushInterval <= 0 {
+		conf.SnapshotFlushInterval = defaultSnapshotFlushInterval
+	}
 	return &Service{
-		log:     log.With(zap.String("service", "balancer")),
-		erc20:   erc20,
-		metrics: metrics.IniMetrics(disableMetrics),
+		log:      log.With(zap.String("service", "balancer")),
+		erc20:    erc20,
+		pricing:  pricingService,
+		verifier: verifierService,
+		indexer:  indexerService,
+		metrics:  metrics.IniMetrics(disableMetrics),
+		storage:  repository,
+		group:    newFlightGroup(conf.CallTimeout, conf.ResultTTL),
+
+		resultTTL:     conf.ResultTTL,
+		flushInterval: conf.SnapshotFlushInterval,
 	}
 }
+
+// GetIndexerStatus returns indexed head and its age of chains followed by indexer
+func (s *Service) GetIndexerStatus() []*entities.IndexerStatus {
+	return s.indexer.GetStatus()
+}
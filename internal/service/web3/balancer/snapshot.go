@@ -0,0 +1,125 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSnapshotFlushInterval = 5 * time.Second
+	// maxPendingSnapshots is number of buffered snapshots which are flushed without waiting for flush interval
+	maxPendingSnapshots = 1_000
+)
+
+// snapshotBuffer collects fresh balance snapshots, so storage gets one transaction per flush instead of one per read
+type snapshotBuffer struct {
+	mu      sync.Mutex
+	pending map[string]*entities.BalanceSnapshot
+}
+
+// withSnapshot extends result reuse of flight group over restarts: balance read less than resultTTL ago is taken
+// from buffer or storage, fresh one is buffered and saved with next flush. Storage errors only disable reuse.
+func (s *Service) withSnapshot(key string, read func() (*taggedBalance, error)) (*taggedBalance, error) {
+	if s.resultTTL <= 0 {
+		return read()
+	}
+	snapshot := s.pendingSnapshot(key)
+	if snapshot == nil {
+		var err error
+		if snapshot, err = s.storage.GetBalanceSnapshot(key); err != nil {
+			s.log.Error("unable to get balance snapshot", err, zap.String("key", key))
+		}
+	}
+	if snapshot != nil && snapshot.Value != nil && time.Since(snapshot.UpdatedAt) < s.resultTTL {
+		return &taggedBalance{value: snapshot.Value, tag: snapshot.BlockTag}, nil
+	}
+	balance, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if s.queueSnapshot(key, &entities.BalanceSnapshot{Value: balance.value, BlockTag: balance.tag, UpdatedAt: time.Now()}) {
+		s.flushSnapshots()
+	}
+	return balance, nil
+}
+
+func (s *Service) pendingSnapshot(key string) *entities.BalanceSnapshot {
+	s.snapshots.mu.Lock()
+	defer s.snapshots.mu.Unlock()
+	return s.snapshots.pending[key]
+}
+
+// queueSnapshot buffers snapshot and reports whether buffer is full
+func (s *Service) queueSnapshot(key string, snapshot *entities.BalanceSnapshot) bool {
+	s.snapshots.mu.Lock()
+	defer s.snapshots.mu.Unlock()
+	if s.snapshots.pending == nil {
+		s.snapshots.pending = make(map[string]*entities.BalanceSnapshot)
+	}
+	s.snapshots.pending[key] = snapshot
+	return len(s.snapshots.pending) >= maxPendingSnapshots
+}
+
+// flushSnapshots saves buffered snapshots and prunes ones older than resultTTL from storage. Snapshots which were not
+// saved stay buffered until next flush.
+func (s *Service) flushSnapshots() {
+	s.snapshots.mu.Lock()
+	batch := s.snapshots.pending
+	s.snapshots.pending = nil
+	s.snapshots.mu.Unlock()
+
+	pruned, err := s.storage.SaveBalanceSnapshots(batch, time.Now().Add(-s.resultTTL))
+	if err == nil {
+		if pruned > 0 {
+			s.log.Info("expired balance snapshots pruned", zap.Int("count", pruned))
+		}
+		return
+	}
+	s.log.Error("unable to save balance snapshots", err, zap.Int("count", len(batch)))
+	s.snapshots.mu.Lock()
+	defer s.snapshots.mu.Unlock()
+	if s.snapshots.pending == nil {
+		s.snapshots.pending = make(map[string]*entities.BalanceSnapshot, len(batch))
+	}
+	for key, snapshot := range batch {
+		if _, newer := s.snapshots.pending[key]; !newer {
+			s.snapshots.pending[key] = snapshot
+		}
+	}
+}
+
+// Start flushes buffered balance snapshots every flush interval until Stop
+func (s *Service) Start() {
+	if s.resultTTL <= 0 {
+		return
+	}
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.flushSnapshots()
+			}
+		}
+	}()
+}
+
+// Stop saves buffered snapshots, storage must be closed after Stop
+func (s *Service) Stop() {
+	if s.stop != nil {
+		close(s.stop)
+		<-s.done
+		s.stop = nil
+	}
+	if s.resultTTL > 0 {
+		s.flushSnapshots()
+	}
+}
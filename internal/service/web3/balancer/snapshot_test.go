@@ -0,0 +1,84 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/storage"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_WithSnapshot(t *testing.T) {
+	// given service restarted on top of same storage
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	store, err := storage.Open("")
+	require.NoError(t, err)
+	repository := storage.NewRepository(store)
+	newService := func(resultTTL time.Duration) *Service {
+		return &Service{log: appLog, storage: repository, resultTTL: resultTTL}
+	}
+	var reads int
+	read := func() (*taggedBalance, error) {
+		reads++
+		return &taggedBalance{value: big.NewInt(int64(reads)), tag: entities.BlockTagLatest}, nil
+	}
+
+	// when
+	srv := newService(time.Minute)
+	first, err := srv.withSnapshot("key", read)
+	require.NoError(t, err)
+	buffered, err := srv.withSnapshot("key", read)
+	require.NoError(t, err)
+	stored, err := repository.GetBalanceSnapshot("key")
+	require.NoError(t, err)
+	require.Nil(t, stored, "snapshot is saved on flush only")
+	srv.Stop()
+	second, err := newService(time.Minute).withSnapshot("key", read)
+	require.NoError(t, err)
+	expired, err := newService(time.Nanosecond).withSnapshot("key", read)
+	require.NoError(t, err)
+
+	// then
+	require.Equal(t, big.NewInt(1), first.value)
+	require.Equal(t, big.NewInt(1), buffered.value)
+	require.Equal(t, big.NewInt(1), second.value)
+	require.Equal(t, big.NewInt(2), expired.value)
+	require.Equal(t, 2, reads)
+}
+
+func TestService_FlushSnapshots(t *testing.T) {
+	// given expired snapshot in storage and full buffer
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	store, err := storage.Open("")
+	require.NoError(t, err)
+	repository := storage.NewRepository(store)
+	_, err = repository.SaveBalanceSnapshots(map[string]*entities.BalanceSnapshot{
+		"old": {Value: big.NewInt(1), UpdatedAt: time.Now().Add(-time.Hour)},
+	}, time.Time{})
+	require.NoError(t, err)
+	srv := &Service{log: appLog, storage: repository, resultTTL: time.Minute}
+	read := func() (*taggedBalance, error) {
+		return &taggedBalance{value: big.NewInt(1), tag: entities.BlockTagLatest}, nil
+	}
+
+	// when
+	for i := 0; i < maxPendingSnapshots; i++ {
+		_, err = srv.withSnapshot(fmt.Sprintf("key-%d", i), read)
+		require.NoError(t, err)
+	}
+
+	// then buffer is flushed without waiting for interval and expired snapshot is pruned
+	require.Empty(t, srv.snapshots.pending)
+	stored, err := repository.GetBalanceSnapshot("key-0")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	old, err := repository.GetBalanceSnapshot("old")
+	require.NoError(t, err)
+	require.Nil(t, old)
+}
@@ -0,0 +1,119 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/approver"
+	"altt/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+var ErrNotContract = errors.New("address is not a contract")
+
+// GetTokenMetadata returns symbol and decimals of erc20 contract. Metadata does not change, so it is read from contract
+// once and then served from storage.
+func (s *Service) GetTokenMetadata(ctx context.Context, chain entities.Chain, token common.Address) (*entities.TokenMetadata, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, fmt.Errorf("chain %s is not available", chain.String())
+	}
+	metadata, err := s.storage.GetTokenMetadata(chain, token)
+	if err != nil {
+		s.log.Error("unable to get stored token metadata", err, zap.String("token", token.String()))
+	}
+	if metadata != nil {
+		return metadata, nil
+	}
+	resp, err := s.group.Do(ctx, "metadata-"+chain.String()+"-"+token.String(), func(ctx context.Context) (interface{}, error) {
+		client, err := web3.GetWeb3Client(chain)
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+		symbol, decimals, err := s.erc20.GetContractData(ctx, client, token)
+		if err != nil {
+			return nil, err
+		}
+		return &entities.TokenMetadata{
+			Chain:     chain,
+			Address:   token.String(),
+			Symbol:    symbol,
+			Decimals:  decimals,
+			UpdatedAt: time.Now(),
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get token metadata: %w", err)
+	}
+	metadata = resp.(*entities.TokenMetadata) // use unsafe cast here as we know that it's result of group
+	if err = s.storage.SaveTokenMetadata(metadata); err != nil {
+		s.log.Error("unable to save token metadata", err, zap.String("token", token.String()))
+	}
+	return metadata, nil
+}
+
+// GetTokenInfo returns metadata and total supply of erc20 contract read from chain. For known tokens on-chain symbol
+// and decimals are compared with token registry.
+func (s *Service) GetTokenInfo(ctx context.Context, chain entities.Chain, contract common.Address) (*entities.TokenInfo, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, fmt.Errorf("chain %s is not available", chain.String())
+	}
+	resp, err := s.group.Do(ctx, "info-"+chain.String()+"-"+contract.String(), func(ctx context.Context) (interface{}, error) {
+		client, err := web3.GetWeb3Client(chain)
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+		return s.erc20.GetContractInfo(ctx, client, contract)
+	})
+	if err != nil {
+		if errors.Is(err, bind.ErrNoCode) {
+			return nil, ErrNotContract
+		}
+		return nil, fmt.Errorf("unable to get token info: %w", err)
+	}
+	info := resp.(*approver.ContractInfo) // use unsafe cast here as we know that it's result of group
+	result := &entities.TokenInfo{
+		Chain:          chain,
+		ChainName:      chain.String(),
+		Contract:       contract.String(),
+		Name:           info.Name,
+		Symbol:         info.Symbol,
+		Decimals:       info.Decimals,
+		TotalSupply:    utils.CustomFromWei(info.TotalSupply, int(info.Decimals)),
+		TotalSupplyWei: info.TotalSupply.String(),
+	}
+	if token, ok := entities.GetTokenByAddress(chain, contract); ok {
+		result.Registry = checkRegistry(token, info)
+	}
+	return result, nil
+}
+
+func checkRegistry(token entities.Token, info *approver.ContractInfo) *entities.TokenRegistryCheck {
+	decimals := entities.GetTokenDecimals(token)
+	return &entities.TokenRegistryCheck{
+		Token:         token,
+		Decimals:      decimals,
+		SymbolMatch:   normalizeSymbol(string(token)) == normalizeSymbol(info.Symbol),
+		DecimalsMatch: decimals == int(info.Decimals),
+	}
+}
+
+// normalizeSymbol drops case and separators, so BTC_b of registry matches BTC.b of contract
+func normalizeSymbol(symbol string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, symbol)
+}
@@ -0,0 +1,26 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/web3/approver"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRegistry(t *testing.T) {
+	t.Run("matches", func(t *testing.T) {
+		check := checkRegistry(entities.BTC_b, &approver.ContractInfo{Symbol: "BTC.b", Decimals: 8, TotalSupply: big.NewInt(1)})
+		require.True(t, check.SymbolMatch)
+		require.True(t, check.DecimalsMatch)
+	})
+
+	t.Run("bridged variant", func(t *testing.T) {
+		// given bridged usdc with 18 decimals
+		check := checkRegistry(entities.USDC, &approver.ContractInfo{Symbol: "USDC.e", Decimals: 18, TotalSupply: big.NewInt(1)})
+		require.False(t, check.SymbolMatch)
+		require.False(t, check.DecimalsMatch)
+		require.Equal(t, 6, check.Decimals)
+	})
+}
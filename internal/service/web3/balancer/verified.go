@@ -0,0 +1,86 @@
+package balancer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3/verifier"
+	"altt/internal/utils"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// GetVerifiedNativeBalance returns native balance proven by account proof. Unlike GetNativeBalance there is no
+// fallback to other tag, as unverified answer is worse than error here.
+func (s *Service) GetVerifiedNativeBalance(ctx context.Context, chain entities.Chain, holder common.Address, tag entities.BlockTag) (*entities.Balance, error) {
+	s.metrics.NewNativeBalanceRequest(holder)
+	if !rpc.ChainAvailable(chain) {
+		return nil, fmt.Errorf("chain %s is not available", chain.String())
+	}
+	resp, err := s.group.Do(ctx, "verified-"+getNativeKey(chain, holder, tag), func(ctx context.Context) (interface{}, error) {
+		return s.verifier.VerifyNativeBalance(ctx, chain, holder, tag)
+	})
+	if err != nil {
+		s.log.Error("failed to verify native balance",
+			err,
+			zap.String("chain", chain.String()),
+			zap.String("address", holder.String()),
+		)
+		return nil, fmt.Errorf("failed to verify native balance: %w", err)
+	}
+	balance := resp.(*verifier.VerifiedBalance) // use unsafe cast here as we know that it's result of group
+	result := &entities.Balance{
+		Chain:           chain,
+		ChainName:       chain.String(),
+		Token:           entities.MapChainToFuel(chain),
+		TokenBalance:    utils.ETHFromWei(balance.Value),
+		TokenBalanceWei: balance.Value.String(),
+		BlockTag:        tag,
+		Verified:        true,
+		BlockNumber:     balance.BlockNumber,
+	}
+	s.pricing.Valuate(ctx, result)
+	return result, nil
+}
+
+// GetVerifiedKnownTokenBalance returns token balance proven by storage proof of token balances mapping.
+func (s *Service) GetVerifiedKnownTokenBalance(ctx context.Context, token entities.Token, chain entities.Chain, holder common.Address, tag entities.BlockTag) (*entities.Balance, error) {
+	if entities.IsFuel(chain, token) {
+		return s.GetVerifiedNativeBalance(ctx, chain, holder, tag)
+	}
+	s.metrics.NewTokenBalanceRequest(holder, token)
+	if !rpc.ChainAvailable(chain) {
+		return nil, fmt.Errorf("chain %s is not available", chain.String())
+	}
+	tokenAddress, err := entities.GetTokenAddress(chain, token)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get token address: %w", err)
+	}
+	resp, err := s.group.Do(ctx, "verified-"+getKnownKey(token, chain, holder, tag), func(ctx context.Context) (interface{}, error) {
+		return s.verifier.VerifyERC20Balance(ctx, chain, tokenAddress, holder, tag)
+	})
+	if err != nil {
+		s.log.Error("failed to verify token balance",
+			err,
+			zap.String("token", string(token)),
+			zap.String("chain", chain.String()),
+			zap.String("address", holder.String()),
+		)
+		return nil, fmt.Errorf("failed to verify token balance: %w", err)
+	}
+	balance := resp.(*verifier.VerifiedBalance) // use unsafe cast here as we know that it's result of group
+	result := &entities.Balance{
+		Chain:           chain,
+		ChainName:       chain.String(),
+		Token:           token,
+		TokenBalance:    entities.CoinFromWEI(token, balance.Value),
+		TokenBalanceWei: balance.Value.String(),
+		BlockTag:        tag,
+		Verified:        true,
+		BlockNumber:     balance.BlockNumber,
+	}
+	s.pricing.Valuate(ctx, result)
+	return result, nil
+}
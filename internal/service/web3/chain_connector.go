@@ -6,6 +6,7 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -75,6 +76,19 @@ func GetConnector(chain entities.Chain) (*ChainConnector, error) {
 	return &conn, nil
 }
 
+// GetWeb3Client returns web3 client of the chain connected to next rpc node from rotation
+func GetWeb3Client(chain entities.Chain) (*ethclient.Client, error) {
+	connector, err := GetConnector(chain)
+	if err != nil {
+		return nil, err
+	}
+	client, err := connector.GetWeb3()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get web3 client: %w", err)
+	}
+	return client, nil
+}
+
 func (c *ChainConnector) GetWeb3() (*ethclient.Client, error) {
 	rpcURL, err := rpc.GetRPC(c.chainID)
 	if err != nil {
@@ -92,7 +106,7 @@ func (c *ChainConnector) GetChainIDBI() *big.Int {
 }
 
 func (c *ChainConnector) TxHash(hash string) string {
-	return c.explorer + "/tx/" + hash
+	return strings.TrimSuffix(c.explorer, "/") + "/tx/" + hash
 }
 
 func (c *ChainConnector) KeyToAddress(key *ecdsa.PrivateKey) (common.Address, error) {
@@ -0,0 +1,76 @@
+package ens
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// cacheEntry is result of lookup. Misses are cached too: zero address or empty name for names and addresses without
+// record, err for failed lookups.
+type cacheEntry struct {
+	address common.Address
+	name    string
+	err     error
+	expires time.Time
+}
+
+// entryCache keeps lookup results until they expire, least recently used entries are dropped over size limit
+type entryCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru orders keys from most to least recently used
+	lru *list.List
+}
+
+type cacheItem struct {
+	key   string
+	entry cacheEntry
+}
+
+func newEntryCache(size int) *entryCache {
+	return &entryCache{size: size, entries: make(map[string]*list.Element), lru: list.New()}
+}
+
+// get returns entry of key which has not expired yet
+func (c *entryCache) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	item := element.Value.(*cacheItem)
+	if !time.Now().Before(item.entry.expires) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		return cacheEntry{}, false
+	}
+	c.lru.MoveToFront(element)
+	return item.entry, true
+}
+
+func (c *entryCache) set(key string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cacheItem).entry = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheItem{key: key, entry: entry})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Remove(c.lru.Back()).(*cacheItem)
+		delete(c.entries, oldest.key)
+	}
+}
+
+func (c *entryCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
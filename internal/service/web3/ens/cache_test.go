@@ -0,0 +1,53 @@
+package ens
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntryCache(t *testing.T) {
+	t.Run("least recently used entry is dropped over size", func(t *testing.T) {
+		// given
+		cache := newEntryCache(2)
+		expires := time.Now().Add(time.Minute)
+		cache.set("a", cacheEntry{name: "a.eth", expires: expires})
+		cache.set("b", cacheEntry{name: "b.eth", expires: expires})
+		_, ok := cache.get("a")
+		require.True(t, ok)
+
+		// when
+		cache.set("c", cacheEntry{name: "c.eth", expires: expires})
+
+		// then
+		require.Equal(t, 2, cache.len())
+		_, ok = cache.get("b")
+		require.False(t, ok)
+		entry, ok := cache.get("a")
+		require.True(t, ok)
+		require.Equal(t, "a.eth", entry.name)
+	})
+
+	t.Run("expired entry is dropped", func(t *testing.T) {
+		cache := newEntryCache(2)
+		cache.set("a", cacheEntry{name: "a.eth", expires: time.Now().Add(-time.Second)})
+		_, ok := cache.get("a")
+		require.False(t, ok)
+		require.Zero(t, cache.len())
+	})
+
+	t.Run("misses and failures are cached", func(t *testing.T) {
+		cache := newEntryCache(2)
+		failure := errors.New("timeout")
+		cache.set("missing", cacheEntry{expires: time.Now().Add(time.Minute)})
+		cache.set("failed", cacheEntry{err: failure, expires: time.Now().Add(time.Minute)})
+		entry, ok := cache.get("missing")
+		require.True(t, ok)
+		require.Empty(t, entry.name)
+		entry, ok = cache.get("failed")
+		require.True(t, ok)
+		require.ErrorIs(t, entry.err, failure)
+	})
+}
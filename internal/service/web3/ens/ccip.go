@@ -0,0 +1,168 @@
+package ens
+
+import (
+	"altt/internal/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxOffchainLookups limits chained OffchainLookup reverts of one call
+	maxOffchainLookups = 4
+	maxGatewayResponse = 1 << 20
+)
+
+var ErrOffchainLookup = errors.New("offchain lookup failed")
+
+// offchainLookup is OffchainLookup revert of EIP-3668
+type offchainLookup struct {
+	Sender           common.Address
+	Urls             []string
+	CallData         []byte
+	CallbackFunction [4]byte
+	ExtraData        []byte
+}
+
+// callbackArgs are arguments of callback function: gateway response and extra data
+var callbackArgs = func() abi.Arguments {
+	bytesType, _ := abi.NewType("bytes", "", nil)
+	return abi.Arguments{{Type: bytesType}, {Type: bytesType}}
+}()
+
+// call executes eth_call and follows CCIP-read: OffchainLookup revert is answered by gateway and passed to callback
+// of the same contract.
+func (s *Service) call(ctx context.Context, caller bind.ContractCaller, to common.Address, data []byte) ([]byte, error) {
+	for i := 0; i <= maxOffchainLookups; i++ {
+		result, err := caller.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+		if err == nil {
+			return result, nil
+		}
+		lookup, ok := s.parseOffchainLookup(err)
+		if !ok {
+			return nil, err
+		}
+		if lookup.Sender != to {
+			return nil, fmt.Errorf("%w: sender %s is not called contract", ErrOffchainLookup, lookup.Sender.String())
+		}
+		response, err := s.queryGateways(ctx, lookup)
+		if err != nil {
+			return nil, err
+		}
+		args, err := callbackArgs.Pack(response, lookup.ExtraData)
+		if err != nil {
+			return nil, fmt.Errorf("unable to pack callback: %w", err)
+		}
+		data = append(lookup.CallbackFunction[:], args...)
+	}
+	return nil, fmt.Errorf("%w: more than %d lookups", ErrOffchainLookup, maxOffchainLookups)
+}
+
+// parseOffchainLookup decodes OffchainLookup from revert data of rpc error
+func (s *Service) parseOffchainLookup(err error) (*offchainLookup, bool) {
+	var dataErr gethrpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	raw, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, errDecode := hexutil.Decode(raw)
+	if errDecode != nil || len(data) < 4 {
+		return nil, false
+	}
+	abiError := s.resolverABI.Errors["OffchainLookup"]
+	if !bytes.Equal(data[:4], abiError.ID[:4]) {
+		return nil, false
+	}
+	values, errUnpack := abiError.Inputs.Unpack(data[4:])
+	if errUnpack != nil {
+		return nil, false
+	}
+	var lookup offchainLookup
+	if errCopy := abiError.Inputs.Copy(&lookup, values); errCopy != nil {
+		return nil, false
+	}
+	return &lookup, true
+}
+
+// queryGateways asks gateway urls in order. Server errors move to next url, client errors stop the lookup.
+func (s *Service) queryGateways(ctx context.Context, lookup *offchainLookup) ([]byte, error) {
+	var lastErr error = fmt.Errorf("%w: no gateway urls", ErrOffchainLookup)
+	for _, gatewayURL := range lookup.Urls {
+		response, retry, err := s.queryGateway(ctx, gatewayURL, lookup)
+		if err == nil {
+			return response, nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func (s *Service) queryGateway(ctx context.Context, gatewayURL string, lookup *offchainLookup) (response []byte, retry bool, err error) {
+	// gateway urls come from resolver contract, so only https urls of public hosts are queried
+	if _, err = utils.ValidatePublicURL(gatewayURL); err != nil {
+		return nil, true, fmt.Errorf("%w: invalid gateway url %s: %s", ErrOffchainLookup, gatewayURL, err)
+	}
+	sender := strings.ToLower(lookup.Sender.Hex())
+	callData := hexutil.Encode(lookup.CallData)
+
+	ctx, cancel := context.WithTimeout(ctx, s.conf.GatewayTimeout)
+	defer cancel()
+	var req *http.Request
+	if strings.Contains(gatewayURL, "{data}") {
+		target := strings.NewReplacer("{sender}", sender, "{data}", callData).Replace(gatewayURL)
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, target, http.NoBody)
+	} else {
+		body, errMarshal := json.Marshal(map[string]string{"data": callData, "sender": sender})
+		if errMarshal != nil {
+			return nil, false, errMarshal
+		}
+		target := strings.ReplaceAll(gatewayURL, "{sender}", sender)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %s", ErrOffchainLookup, err)
+	}
+	resp, err := s.gateway.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: %s", ErrOffchainLookup, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, true, fmt.Errorf("%w: gateway responded %d", ErrOffchainLookup, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("%w: gateway responded %d", ErrOffchainLookup, resp.StatusCode)
+	}
+	var payload struct {
+		Data string `json:"data"`
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxGatewayResponse)).Decode(&payload); err != nil {
+		return nil, true, fmt.Errorf("%w: invalid gateway response: %s", ErrOffchainLookup, err)
+	}
+	response, err = hexutil.Decode(payload.Data)
+	if err != nil {
+		return nil, true, fmt.Errorf("%w: invalid gateway data: %s", ErrOffchainLookup, err)
+	}
+	return response, false, nil
+}
@@ -0,0 +1,160 @@
+package ens
+
+import (
+	"altt/internal/config"
+	"altt/internal/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+// revertError is rpc error of reverted eth_call with revert data
+type revertError struct {
+	data []byte
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// gatewayTransport sends requests to public gateway urls to local test server
+type gatewayTransport struct {
+	host string
+}
+
+func (t gatewayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = "http", t.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// fakeCaller answers eth_call by handler of called contract
+type fakeCaller struct {
+	contracts map[common.Address]func(data []byte) ([]byte, error)
+}
+
+func (f *fakeCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if _, ok := f.contracts[contract]; ok {
+		return []byte{0x1}, nil
+	}
+	return nil, nil
+}
+
+func (f *fakeCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	handler, ok := f.contracts[*call.To]
+	if !ok {
+		return nil, nil
+	}
+	return handler(call.Data)
+}
+
+func TestService_Resolve(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv, err := NewService(appLog, config.ENSConfig{})
+	require.NoError(t, err)
+	registryABI, err := EnsRegistryMetaData.GetAbi()
+	require.NoError(t, err)
+
+	resolver := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	owner := common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	callback := [4]byte{0x11, 0x22, 0x33, 0x44}
+	extraData := []byte("extra")
+
+	// given gateway which fails once and then answers with address
+	var gatewayCalls int
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gatewayCalls++
+		if strings.HasPrefix(r.URL.Path, "/broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		require.Equal(t, http.MethodPost, r.Method)
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, strings.ToLower(resolver.Hex()), req["sender"])
+		require.Equal(t, "0xabcd", req["data"])
+		response, _ := srv.resolverABI.Methods["addr"].Outputs.Pack(owner)
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]string{"data": hexutil.Encode(response)}))
+	}))
+	defer gateway.Close()
+	srv.gateway = &http.Client{Transport: gatewayTransport{host: strings.TrimPrefix(gateway.URL, "http://")}}
+	gatewayURL := "https://gateway.example.com"
+
+	// given wildcard offchain resolver set on parent name
+	caller := &fakeCaller{contracts: map[common.Address]func(data []byte) ([]byte, error){
+		registryAddress: func(data []byte) ([]byte, error) {
+			args, errUnpack := registryABI.Methods["resolver"].Inputs.Unpack(data[4:])
+			require.NoError(t, errUnpack)
+			if common.Hash(args[0].([32]byte)) == NameHash("example.eth") {
+				return registryABI.Methods["resolver"].Outputs.Pack(resolver)
+			}
+			return registryABI.Methods["resolver"].Outputs.Pack(common.Address{})
+		},
+		resolver: func(data []byte) ([]byte, error) {
+			switch {
+			case bytes.Equal(data[:4], srv.resolverABI.Methods["supportsInterface"].ID):
+				return srv.resolverABI.Methods["supportsInterface"].Outputs.Pack(true)
+			case bytes.Equal(data[:4], srv.resolverABI.Methods["resolve"].ID):
+				lookup, errPack := srv.resolverABI.Errors["OffchainLookup"].Inputs.Pack(
+					resolver, []string{gatewayURL + "/broken/{sender}", gatewayURL + "/{sender}"}, []byte{0xab, 0xcd}, callback, extraData,
+				)
+				require.NoError(t, errPack)
+				return nil, &revertError{data: append(srv.resolverABI.Errors["OffchainLookup"].ID.Bytes()[:4], lookup...)}
+			case bytes.Equal(data[:4], callback[:]):
+				bytesType, _ := abi.NewType("bytes", "", nil)
+				args, errUnpack := abi.Arguments{{Type: bytesType}, {Type: bytesType}}.Unpack(data[4:])
+				require.NoError(t, errUnpack)
+				require.Equal(t, extraData, args[1])
+				return srv.resolverABI.Methods["resolve"].Outputs.Pack(args[0])
+			}
+			return nil, errors.New("unexpected call")
+		},
+	}}
+
+	t.Run("wildcard offchain resolver", func(t *testing.T) {
+		// when
+		address, errResolve := srv.resolve(context.Background(), caller, "sub.example.eth")
+
+		// then
+		require.NoError(t, errResolve)
+		require.Equal(t, owner, address)
+		require.Equal(t, 2, gatewayCalls)
+	})
+
+	t.Run("gateway of local host is not queried", func(t *testing.T) {
+		// given
+		calls := gatewayCalls
+		lookup := &offchainLookup{Sender: resolver, Urls: []string{gateway.URL + "/{sender}", "https://169.254.169.254/{sender}"}}
+
+		// when
+		_, errQuery := srv.queryGateways(context.Background(), lookup)
+
+		// then
+		require.ErrorIs(t, errQuery, ErrOffchainLookup)
+		require.Equal(t, calls, gatewayCalls)
+	})
+
+	t.Run("name without resolver", func(t *testing.T) {
+		_, errResolve := srv.resolve(context.Background(), caller, "unknown.eth")
+		require.ErrorIs(t, errResolve, ErrNameNotFound)
+	})
+
+	t.Run("lookup from other contract is rejected", func(t *testing.T) {
+		other := common.HexToAddress("0x00000000000000000000000000000000000000e2")
+		caller.contracts[other] = caller.contracts[resolver]
+		_, errCall := srv.call(context.Background(), caller, other, srv.resolverABI.Methods["resolve"].ID)
+		require.ErrorIs(t, errCall, ErrOffchainLookup)
+	})
+}
@@ -0,0 +1,305 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package ens
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// EnsResolverMetaData contains all meta data concerning the EnsResolver contract.
+var EnsResolverMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"sender\",\"type\":\"address\"},{\"internalType\":\"string[]\",\"name\":\"urls\",\"type\":\"string[]\"},{\"internalType\":\"bytes\",\"name\":\"callData\",\"type\":\"bytes\"},{\"internalType\":\"bytes4\",\"name\":\"callbackFunction\",\"type\":\"bytes4\"},{\"internalType\":\"bytes\",\"name\":\"extraData\",\"type\":\"bytes\"}],\"name\":\"OffchainLookup\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"node\",\"type\":\"bytes32\"}],\"name\":\"addr\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"node\",\"type\":\"bytes32\"}],\"name\":\"name\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"name\",\"type\":\"bytes\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"resolve\",\"outputs\":[{\"internalType\":\"bytes\",\"name\":\"\",\"type\":\"bytes\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes4\",\"name\":\"interfaceID\",\"type\":\"bytes4\"}],\"name\":\"supportsInterface\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+}
+
+// EnsResolverABI is the input ABI used to generate the binding from.
+// Deprecated: Use EnsResolverMetaData.ABI instead.
+var EnsResolverABI = EnsResolverMetaData.ABI
+
+// EnsResolver is an auto generated Go binding around an Ethereum contract.
+type EnsResolver struct {
+	EnsResolverCaller     // Read-only binding to the contract
+	EnsResolverTransactor // Write-only binding to the contract
+	EnsResolverFilterer   // Log filterer for contract events
+}
+
+// EnsResolverCaller is an auto generated read-only Go binding around an Ethereum contract.
+type EnsResolverCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// EnsResolverTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type EnsResolverTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// EnsResolverFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type EnsResolverFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// EnsResolverSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type EnsResolverSession struct {
+	Contract     *EnsResolver      // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// EnsResolverCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type EnsResolverCallerSession struct {
+	Contract *EnsResolverCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts      // Call options to use throughout this session
+}
+
+// EnsResolverTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type EnsResolverTransactorSession struct {
+	Contract     *EnsResolverTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts      // Transaction auth options to use throughout this session
+}
+
+// EnsResolverRaw is an auto generated low-level Go binding around an Ethereum contract.
+type EnsResolverRaw struct {
+	Contract *EnsResolver // Generic contract binding to access the raw methods on
+}
+
+// EnsResolverCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type EnsResolverCallerRaw struct {
+	Contract *EnsResolverCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// EnsResolverTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type EnsResolverTransactorRaw struct {
+	Contract *EnsResolverTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewEnsResolver creates a new instance of EnsResolver, bound to a specific deployed contract.
+func NewEnsResolver(address common.Address, backend bind.ContractBackend) (*EnsResolver, error) {
+	contract, err := bindEnsResolver(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &EnsResolver{EnsResolverCaller: EnsResolverCaller{contract: contract}, EnsResolverTransactor: EnsResolverTransactor{contract: contract}, EnsResolverFilterer: EnsResolverFilterer{contract: contract}}, nil
+}
+
+// NewEnsResolverCaller creates a new read-only instance of EnsResolver, bound to a specific deployed contract.
+func NewEnsResolverCaller(address common.Address, caller bind.ContractCaller) (*EnsResolverCaller, error) {
+	contract, err := bindEnsResolver(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &EnsResolverCaller{contract: contract}, nil
+}
+
+// NewEnsResolverTransactor creates a new write-only instance of EnsResolver, bound to a specific deployed contract.
+func NewEnsResolverTransactor(address common.Address, transactor bind.ContractTransactor) (*EnsResolverTransactor, error) {
+	contract, err := bindEnsResolver(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &EnsResolverTransactor{contract: contract}, nil
+}
+
+// NewEnsResolverFilterer creates a new log filterer instance of EnsResolver, bound to a specific deployed contract.
+func NewEnsResolverFilterer(address common.Address, filterer bind.ContractFilterer) (*EnsResolverFilterer, error) {
+	contract, err := bindEnsResolver(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &EnsResolverFilterer{contract: contract}, nil
+}
+
+// bindEnsResolver binds a generic wrapper to an already deployed contract.
+func bindEnsResolver(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := EnsResolverMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_EnsResolver *EnsResolverRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _EnsResolver.Contract.EnsResolverCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_EnsResolver *EnsResolverRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _EnsResolver.Contract.EnsResolverTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_EnsResolver *EnsResolverRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _EnsResolver.Contract.EnsResolverTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_EnsResolver *EnsResolverCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _EnsResolver.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_EnsResolver *EnsResolverTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _EnsResolver.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_EnsResolver *EnsResolverTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _EnsResolver.Contract.contract.Transact(opts, method, params...)
+}
+
+// Addr is a free data retrieval call binding the contract method 0x3b3b57de.
+//
+// Solidity: function addr(bytes32 node) view returns(address)
+func (_EnsResolver *EnsResolverCaller) Addr(opts *bind.CallOpts, node [32]byte) (common.Address, error) {
+	var out []interface{}
+	err := _EnsResolver.contract.Call(opts, &out, "addr", node)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Addr is a free data retrieval call binding the contract method 0x3b3b57de.
+//
+// Solidity: function addr(bytes32 node) view returns(address)
+func (_EnsResolver *EnsResolverSession) Addr(node [32]byte) (common.Address, error) {
+	return _EnsResolver.Contract.Addr(&_EnsResolver.CallOpts, node)
+}
+
+// Addr is a free data retrieval call binding the contract method 0x3b3b57de.
+//
+// Solidity: function addr(bytes32 node) view returns(address)
+func (_EnsResolver *EnsResolverCallerSession) Addr(node [32]byte) (common.Address, error) {
+	return _EnsResolver.Contract.Addr(&_EnsResolver.CallOpts, node)
+}
+
+// Name is a free data retrieval call binding the contract method 0x691f3431.
+//
+// Solidity: function name(bytes32 node) view returns(string)
+func (_EnsResolver *EnsResolverCaller) Name(opts *bind.CallOpts, node [32]byte) (string, error) {
+	var out []interface{}
+	err := _EnsResolver.contract.Call(opts, &out, "name", node)
+
+	if err != nil {
+		return *new(string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(string)).(*string)
+
+	return out0, err
+
+}
+
+// Name is a free data retrieval call binding the contract method 0x691f3431.
+//
+// Solidity: function name(bytes32 node) view returns(string)
+func (_EnsResolver *EnsResolverSession) Name(node [32]byte) (string, error) {
+	return _EnsResolver.Contract.Name(&_EnsResolver.CallOpts, node)
+}
+
+// Name is a free data retrieval call binding the contract method 0x691f3431.
+//
+// Solidity: function name(bytes32 node) view returns(string)
+func (_EnsResolver *EnsResolverCallerSession) Name(node [32]byte) (string, error) {
+	return _EnsResolver.Contract.Name(&_EnsResolver.CallOpts, node)
+}
+
+// Resolve is a free data retrieval call binding the contract method 0x9061b923.
+//
+// Solidity: function resolve(bytes name, bytes data) view returns(bytes)
+func (_EnsResolver *EnsResolverCaller) Resolve(opts *bind.CallOpts, name []byte, data []byte) ([]byte, error) {
+	var out []interface{}
+	err := _EnsResolver.contract.Call(opts, &out, "resolve", name, data)
+
+	if err != nil {
+		return *new([]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]byte)).(*[]byte)
+
+	return out0, err
+
+}
+
+// Resolve is a free data retrieval call binding the contract method 0x9061b923.
+//
+// Solidity: function resolve(bytes name, bytes data) view returns(bytes)
+func (_EnsResolver *EnsResolverSession) Resolve(name []byte, data []byte) ([]byte, error) {
+	return _EnsResolver.Contract.Resolve(&_EnsResolver.CallOpts, name, data)
+}
+
+// Resolve is a free data retrieval call binding the contract method 0x9061b923.
+//
+// Solidity: function resolve(bytes name, bytes data) view returns(bytes)
+func (_EnsResolver *EnsResolverCallerSession) Resolve(name []byte, data []byte) ([]byte, error) {
+	return _EnsResolver.Contract.Resolve(&_EnsResolver.CallOpts, name, data)
+}
+
+// SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
+//
+// Solidity: function supportsInterface(bytes4 interfaceID) view returns(bool)
+func (_EnsResolver *EnsResolverCaller) SupportsInterface(opts *bind.CallOpts, interfaceID [4]byte) (bool, error) {
+	var out []interface{}
+	err := _EnsResolver.contract.Call(opts, &out, "supportsInterface", interfaceID)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
+//
+// Solidity: function supportsInterface(bytes4 interfaceID) view returns(bool)
+func (_EnsResolver *EnsResolverSession) SupportsInterface(interfaceID [4]byte) (bool, error) {
+	return _EnsResolver.Contract.SupportsInterface(&_EnsResolver.CallOpts, interfaceID)
+}
+
+// SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
+//
+// Solidity: function supportsInterface(bytes4 interfaceID) view returns(bool)
+func (_EnsResolver *EnsResolverCallerSession) SupportsInterface(interfaceID [4]byte) (bool, error) {
+	return _EnsResolver.Contract.SupportsInterface(&_EnsResolver.CallOpts, interfaceID)
+}
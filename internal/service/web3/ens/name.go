@@ -0,0 +1,70 @@
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidName = errors.New("invalid ens name")
+
+// profile is UTS-46 lookup mapping of ENSIP-1 normalisation: case folding, compatibility mapping and rejection of disallowed code points
+var profile = idna.New(idna.MapForLookup(), idna.Transitional(false), idna.StrictDomainName(false))
+
+// IsName reports whether src looks like ENS name rather than hex address
+func IsName(src string) bool {
+	return strings.Contains(src, ".") && !strings.HasPrefix(strings.ToLower(src), "0x")
+}
+
+// Normalize returns normalised form of name, which is hashed and compared
+func Normalize(name string) (string, error) {
+	normalized, err := profile.ToUnicode(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidName, err)
+	}
+	for _, label := range strings.Split(normalized, ".") {
+		if label == "" {
+			return "", fmt.Errorf("%w: empty label", ErrInvalidName)
+		}
+		if strings.IndexFunc(label, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+			return "", fmt.Errorf("%w: whitespace in label", ErrInvalidName)
+		}
+	}
+	return normalized, nil
+}
+
+// NameHash returns node of normalised name as defined by EIP-137
+func NameHash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), crypto.Keccak256([]byte(labels[i])))
+	}
+	return node
+}
+
+// dnsEncode returns name in DNS wire format, used by ENSIP-10 resolve(bytes,bytes)
+func dnsEncode(name string) ([]byte, error) {
+	encoded := make([]byte, 0, len(name)+2)
+	for _, label := range strings.Split(name, ".") {
+		if len(label) > 255 {
+			return nil, fmt.Errorf("%w: label is longer than 255 bytes", ErrInvalidName)
+		}
+		encoded = append(encoded, byte(len(label)))
+		encoded = append(encoded, label...)
+	}
+	return append(encoded, 0), nil
+}
+
+// reverseName returns name of reverse record of address
+func reverseName(address common.Address) string {
+	return strings.ToLower(address.Hex()[2:]) + ".addr.reverse"
+}
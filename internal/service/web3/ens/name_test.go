@@ -0,0 +1,50 @@
+package ens
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameHash(t *testing.T) {
+	require.Equal(t, common.Hash{}, NameHash(""))
+	require.Equal(t, "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae", NameHash("eth").Hex())
+	require.Equal(t, "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f", NameHash("foo.eth").Hex())
+}
+
+func TestNormalize(t *testing.T) {
+	t.Run("case and compatibility mapping", func(t *testing.T) {
+		name, err := Normalize("Vitalik.ETH")
+		require.NoError(t, err)
+		require.Equal(t, "vitalik.eth", name)
+
+		name, err = Normalize("ｖｉｔａｌｉｋ.eth")
+		require.NoError(t, err)
+		require.Equal(t, "vitalik.eth", name)
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		for _, name := range []string{"vitalik..eth", ".eth", "vita lik.eth", "a‍b.eth"} {
+			_, err := Normalize(name)
+			require.ErrorIs(t, err, ErrInvalidName, name)
+		}
+	})
+}
+
+func TestIsName(t *testing.T) {
+	require.True(t, IsName("vitalik.eth"))
+	require.True(t, IsName("name.example.com"))
+	require.False(t, IsName("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"))
+	require.False(t, IsName("vitalik"))
+}
+
+func TestDNSEncode(t *testing.T) {
+	encoded, err := dnsEncode("foo.eth")
+	require.NoError(t, err)
+	require.Equal(t, []byte("\x03foo\x03eth\x00"), encoded)
+}
+
+func TestReverseName(t *testing.T) {
+	require.Equal(t, "d8da6bf26964af9d7eed9e03e53415d37aa96045.addr.reverse", reverseName(common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")))
+}
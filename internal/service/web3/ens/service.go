@@ -0,0 +1,272 @@
+package ens
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCacheTTL       = 10 * time.Minute
+	defaultCacheSize      = 10_000
+	defaultGatewayTimeout = 10 * time.Second
+	// failureTTL is how long failed lookup is returned from cache, so unavailable resolver is not asked on every request
+	failureTTL = 30 * time.Second
+)
+
+// registryAddress is ENS registry on ethereum mainnet
+var registryAddress = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// extendedResolverInterface is interface id of ENSIP-10 resolve(bytes,bytes)
+var extendedResolverInterface = [4]byte{0x90, 0x61, 0xb9, 0x23}
+
+var (
+	ErrNameNotFound = errors.New("ens name is not found")
+	ErrUnavailable  = errors.New("ens is not available, ethereum rpc is not configured")
+)
+
+// Service resolves ENS names to addresses and addresses to primary names on ethereum mainnet.
+// Wildcard resolvers (ENSIP-10) and offchain resolvers (CCIP-read, EIP-3668) are supported.
+type Service struct {
+	log         logger.AppLogger
+	conf        config.ENSConfig
+	gateway     *http.Client
+	resolverABI *abi.ABI
+	names       *entryCache
+	reverse     *entryCache
+}
+
+func NewService(log logger.AppLogger, conf config.ENSConfig) (*Service, error) {
+	if conf.CacheTTL == 0 {
+		conf.CacheTTL = defaultCacheTTL
+	}
+	if conf.CacheSize <= 0 {
+		conf.CacheSize = defaultCacheSize
+	}
+	if conf.GatewayTimeout == 0 {
+		conf.GatewayTimeout = defaultGatewayTimeout
+	}
+	resolverABI, err := EnsResolverMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse resolver abi: %w", err)
+	}
+	return &Service{
+		log:         log.With(zap.String("service", "ens")),
+		conf:        conf,
+		gateway:     utils.NewPublicHTTPClient(conf.GatewayTimeout),
+		resolverABI: resolverABI,
+		names:       newEntryCache(conf.CacheSize),
+		reverse:     newEntryCache(conf.CacheSize),
+	}, nil
+}
+
+// Resolve returns address of ENS name
+func (s *Service) Resolve(ctx context.Context, name string) (common.Address, error) {
+	normalized, err := Normalize(name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if entry, ok := s.names.get(normalized); ok {
+		if entry.err != nil {
+			return common.Address{}, entry.err
+		}
+		if entry.address == (common.Address{}) {
+			return common.Address{}, ErrNameNotFound
+		}
+		return entry.address, nil
+	}
+	if !rpc.ChainAvailable(entities.ChainEthereum) {
+		return common.Address{}, ErrUnavailable
+	}
+	client, err := web3.GetWeb3Client(entities.ChainEthereum)
+	if err != nil {
+		return common.Address{}, err
+	}
+	defer client.Close()
+	address, err := s.resolve(ctx, client, normalized)
+	if err != nil && !errors.Is(err, ErrNameNotFound) {
+		s.cacheFailure(s.names, normalized, err)
+		return common.Address{}, err
+	}
+	s.names.set(normalized, cacheEntry{address: address, expires: time.Now().Add(s.conf.CacheTTL)})
+	return address, err
+}
+
+// LookupAddress returns primary name of address. Name is returned only when it resolves back to the address,
+// empty string means that primary name is not set.
+func (s *Service) LookupAddress(ctx context.Context, address common.Address) (string, error) {
+	key := address.Hex()
+	if entry, ok := s.reverse.get(key); ok {
+		return entry.name, entry.err
+	}
+	if !rpc.ChainAvailable(entities.ChainEthereum) {
+		return "", ErrUnavailable
+	}
+	client, err := web3.GetWeb3Client(entities.ChainEthereum)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	name, err := s.lookupAddress(ctx, client, address)
+	if err != nil {
+		s.cacheFailure(s.reverse, key, err)
+		return "", err
+	}
+	s.reverse.set(key, cacheEntry{name: name, expires: time.Now().Add(s.conf.CacheTTL)})
+	return name, nil
+}
+
+// cacheFailure keeps failed lookup for failureTTL, lookup canceled by caller is not cached
+func (s *Service) cacheFailure(cache *entryCache, key string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	cache.set(key, cacheEntry{err: err, expires: time.Now().Add(failureTTL)})
+}
+
+// resolve finds resolver of normalised name and reads its addr record
+func (s *Service) resolve(ctx context.Context, backend bind.ContractCaller, name string) (common.Address, error) {
+	resolver, exact, err := s.findResolver(ctx, backend, name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	node := NameHash(name)
+	addrCall, err := s.resolverABI.Pack("addr", node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	extended, err := s.supportsExtended(ctx, backend, resolver)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var result []byte
+	switch {
+	case extended:
+		dnsName, errEncode := dnsEncode(name)
+		if errEncode != nil {
+			return common.Address{}, errEncode
+		}
+		resolveCall, errPack := s.resolverABI.Pack("resolve", dnsName, addrCall)
+		if errPack != nil {
+			return common.Address{}, errPack
+		}
+		response, errCall := s.call(ctx, backend, resolver, resolveCall)
+		if errCall != nil {
+			return common.Address{}, fmt.Errorf("unable to resolve %s: %w", name, errCall)
+		}
+		unpacked, errUnpack := s.resolverABI.Unpack("resolve", response)
+		if errUnpack != nil {
+			return common.Address{}, fmt.Errorf("unable to unpack resolve result: %w", errUnpack)
+		}
+		result = unpacked[0].([]byte)
+	case exact:
+		if result, err = s.call(ctx, backend, resolver, addrCall); err != nil {
+			return common.Address{}, fmt.Errorf("unable to resolve %s: %w", name, err)
+		}
+	default:
+		// resolver of parent name is used only when it supports wildcard resolution
+		return common.Address{}, ErrNameNotFound
+	}
+	unpacked, err := s.resolverABI.Unpack("addr", result)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("unable to unpack addr result: %w", err)
+	}
+	address := unpacked[0].(common.Address)
+	if address == (common.Address{}) {
+		return common.Address{}, ErrNameNotFound
+	}
+	return address, nil
+}
+
+// findResolver returns resolver of name or of its closest parent with resolver (ENSIP-10), exact is set when resolver
+// is set for name itself
+func (s *Service) findResolver(ctx context.Context, backend bind.ContractCaller, name string) (resolver common.Address, exact bool, err error) {
+	registry, err := NewEnsRegistryCaller(registryAddress, backend)
+	if err != nil {
+		return common.Address{}, false, err
+	}
+	labels := strings.Split(name, ".")
+	for i := range labels {
+		resolver, err = registry.Resolver(&bind.CallOpts{Context: ctx}, NameHash(strings.Join(labels[i:], ".")))
+		if err != nil {
+			return common.Address{}, false, fmt.Errorf("unable to get resolver: %w", err)
+		}
+		if resolver != (common.Address{}) {
+			return resolver, i == 0, nil
+		}
+	}
+	return common.Address{}, false, ErrNameNotFound
+}
+
+// supportsExtended reports whether resolver implements ENSIP-10, resolvers without ERC-165 do not
+func (s *Service) supportsExtended(ctx context.Context, backend bind.ContractCaller, resolver common.Address) (bool, error) {
+	caller, err := NewEnsResolverCaller(resolver, backend)
+	if err != nil {
+		return false, err
+	}
+	supported, err := caller.SupportsInterface(&bind.CallOpts{Context: ctx}, extendedResolverInterface)
+	if err != nil {
+		return false, nil // nolint:nilerr // call reverts on resolvers without ERC-165
+	}
+	return supported, nil
+}
+
+func (s *Service) lookupAddress(ctx context.Context, backend bind.ContractCaller, address common.Address) (string, error) {
+	node := NameHash(reverseName(address))
+	registry, err := NewEnsRegistryCaller(registryAddress, backend)
+	if err != nil {
+		return "", err
+	}
+	resolver, err := registry.Resolver(&bind.CallOpts{Context: ctx}, node)
+	if err != nil {
+		return "", fmt.Errorf("unable to get reverse resolver: %w", err)
+	}
+	if resolver == (common.Address{}) {
+		return "", nil
+	}
+	nameCall, err := s.resolverABI.Pack("name", node)
+	if err != nil {
+		return "", err
+	}
+	result, err := s.call(ctx, backend, resolver, nameCall)
+	if err != nil {
+		return "", fmt.Errorf("unable to get reverse name: %w", err)
+	}
+	unpacked, err := s.resolverABI.Unpack("name", result)
+	if err != nil {
+		return "", fmt.Errorf("unable to unpack name result: %w", err)
+	}
+	name := unpacked[0].(string)
+	if name == "" {
+		return "", nil
+	}
+	if normalized, errNormalize := Normalize(name); errNormalize != nil || normalized != name {
+		s.log.Info("reverse name is not normalised, ignore it", zap.String("address", address.String()), zap.String("name", name))
+		return "", nil
+	}
+	forward, err := s.resolve(ctx, backend, name)
+	if errors.Is(err, ErrNameNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if forward != address {
+		return "", nil
+	}
+	return name, nil
+}
@@ -0,0 +1,305 @@
+package follower
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/heads"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWindow = 128
+	fetchTimeout  = 30 * time.Second
+	// maxGap is number of blocks missed by follower (e.g. during node outage) which are fetched and delivered,
+	// wider gaps are reported by Resync event
+	maxGap = 512
+	// subscriberBuffer fits blocks delivered after widest gap together with removed blocks of reorg
+	subscriberBuffer = 2 * (maxGap + defaultWindow)
+)
+
+type EventType string
+
+const (
+	BlockAdded   EventType = "block_added"
+	BlockRemoved EventType = "block_removed"
+	// Resync means that new canonical chain does not connect to delivered blocks: gap after outage is wider than
+	// maxGap or reorg is deeper than window. Consumer should drop state derived from previous events, added blocks
+	// continue from Header.
+	Resync EventType = "resync"
+)
+
+// Event is change of canonical chain. Removed blocks are emitted from tip down before added blocks, added blocks go in ascending order.
+type Event struct {
+	Type   EventType
+	Chain  entities.Chain
+	Header *types.Header
+}
+
+var ErrUnknownParent = errors.New("parent header not found")
+
+// headerSource fetches headers which were missed between polled heads or replaced by reorg
+type headerSource interface {
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+}
+
+// Service follows canonical chain of subscribed chains over window of recent headers and detects reorgs by parent hash.
+type Service struct {
+	log    logger.AppLogger
+	heads  *heads.Service
+	window int
+
+	mu     sync.Mutex
+	nextID int
+	chains map[entities.Chain]*chainFollower
+}
+
+type chainFollower struct {
+	// canonical is window of recent canonical headers in ascending order
+	canonical   []*types.Header
+	subs        map[int]*subscriber
+	unsubscribe func()
+}
+
+type subscriber struct {
+	confirmations uint64
+	// delivered is number of last block delivered as added, zero until first delivery
+	delivered uint64
+	ch        chan Event
+}
+
+func NewService(log logger.AppLogger, headsService *heads.Service, window int) *Service {
+	if window <= 0 {
+		window = defaultWindow
+	}
+	return &Service{
+		log:    log.With(zap.String("service", "follower")),
+		heads:  headsService,
+		window: window,
+		chains: make(map[entities.Chain]*chainFollower),
+	}
+}
+
+// Subscribe returns ordered events of chain and function to cancel subscription. Block is delivered as added once it has
+// given number of confirmations, so with confirmations > 0 only reorgs deeper than that produce removed events.
+// Confirmations should be less than follower window. Subscriber which does not keep up is dropped and its channel is closed,
+// as skipped events would leave consumer state inconsistent.
+func (s *Service) Subscribe(chain entities.Chain, confirmations uint64) (<-chan Event, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	sub := &subscriber{confirmations: confirmations, ch: make(chan Event, subscriberBuffer)}
+	follower, ok := s.chains[chain]
+	if !ok {
+		follower = &chainFollower{subs: make(map[int]*subscriber)}
+		s.chains[chain] = follower
+		headsCh, unsubscribe := s.heads.Subscribe(chain)
+		follower.unsubscribe = unsubscribe
+		go s.follow(chain, follower, headsCh)
+	}
+	if len(follower.canonical) > 0 {
+		// new subscriber starts from current tip instead of replaying whole window
+		tip := follower.canonical[len(follower.canonical)-1].Number.Uint64()
+		if tip > confirmations {
+			sub.delivered = tip - confirmations
+		}
+	}
+	follower.subs[id] = sub
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.removeSubscriber(chain, follower, id)
+		})
+	}
+}
+
+// removeSubscriber closes channel of subscriber and stops following chain after the last one, s.mu is held by caller
+func (s *Service) removeSubscriber(chain entities.Chain, follower *chainFollower, id int) {
+	if sub, exists := follower.subs[id]; exists {
+		delete(follower.subs, id)
+		close(sub.ch)
+	}
+	if len(follower.subs) > 0 {
+		return
+	}
+	if s.chains[chain] == follower {
+		delete(s.chains, chain)
+		follower.unsubscribe()
+	}
+}
+
+// follow processes heads of chain with one client, which is dialled again only after failed head
+func (s *Service) follow(chain entities.Chain, follower *chainFollower, headsCh <-chan *types.Header) {
+	var client *ethclient.Client
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
+	for head := range headsCh {
+		if client == nil {
+			var err error
+			if client, err = web3.GetWeb3Client(chain); err != nil {
+				s.log.Error("unable to get web3 client", err, zap.String("chain", chain.String()))
+				client = nil
+				continue
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+		if err := s.processHead(ctx, chain, follower, client, head); err != nil {
+			s.log.Error("unable to process head", err, zap.String("chain", chain.String()), zap.Uint64("block", head.Number.Uint64()))
+			client.Close()
+			client = nil
+		}
+		cancel()
+	}
+}
+
+// processHead connects head to canonical window, fetching missed ancestors, and delivers changes to subscribers
+func (s *Service) processHead(ctx context.Context, chain entities.Chain, follower *chainFollower, source headerSource, head *types.Header) error {
+	s.mu.Lock()
+	if s.chains[chain] != follower {
+		// follower was stopped after its last subscriber left, heads channel is being closed
+		s.mu.Unlock()
+		return nil
+	}
+	canonical := append([]*types.Header(nil), follower.canonical...)
+	s.mu.Unlock()
+
+	segment, ancestor, err := s.connect(ctx, source, canonical, head)
+	if err != nil {
+		return err
+	}
+	if len(segment) == 0 {
+		return nil
+	}
+	// blocks fetched over gap are delivered, only window of them is kept
+	updated := append(canonical[:ancestor+1:ancestor+1], segment...)
+	kept := updated
+	if len(kept) > s.window {
+		kept = kept[len(kept)-s.window:]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	follower.canonical = kept
+	if ancestor < 0 && len(canonical) > 0 {
+		s.log.Info("head does not connect to window, resync",
+			zap.String("chain", chain.String()),
+			zap.Uint64("tip", canonical[len(canonical)-1].Number.Uint64()),
+			zap.Uint64("head", head.Number.Uint64()),
+		)
+	} else if ancestor < len(canonical)-1 {
+		s.log.Info("reorg detected",
+			zap.String("chain", chain.String()),
+			zap.Int("depth", len(canonical)-1-ancestor),
+			zap.Uint64("head", head.Number.Uint64()),
+		)
+	}
+	for id, sub := range follower.subs {
+		if !s.deliver(chain, sub, canonical, ancestor, updated) {
+			s.log.Info("drop slow subscriber", zap.String("chain", chain.String()))
+			s.removeSubscriber(chain, follower, id)
+		}
+	}
+	return nil
+}
+
+// connect returns new canonical headers after ancestor and index of ancestor in window. Headers missed since tip
+// of window are fetched up to maxGap blocks. Ancestor index -1 means head does not connect to window: gap is wider
+// or reorg is deeper than window, so segment is just head. Known head returns empty segment.
+func (s *Service) connect(ctx context.Context, source headerSource, canonical []*types.Header, head *types.Header) ([]*types.Header, int, error) {
+	index := make(map[common.Hash]int, len(canonical))
+	for i, header := range canonical {
+		index[header.Hash()] = i
+	}
+	if _, known := index[head.Hash()]; known {
+		return nil, 0, nil
+	}
+	limit := s.window
+	if len(canonical) > 0 {
+		tip := canonical[len(canonical)-1].Number.Uint64()
+		if number := head.Number.Uint64(); number > tip+maxGap {
+			return []*types.Header{head}, -1, nil
+		} else if number > tip {
+			limit += int(number - tip)
+		}
+	}
+	segment := []*types.Header{head}
+	for {
+		current := segment[0]
+		if ancestor, ok := index[current.ParentHash]; ok {
+			return segment, ancestor, nil
+		}
+		if len(canonical) == 0 || len(segment) >= limit || current.Number.Uint64() <= canonical[0].Number.Uint64() {
+			return []*types.Header{head}, -1, nil
+		}
+		parent, err := source.HeaderByHash(ctx, current.ParentHash)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %s", ErrUnknownParent, err)
+		}
+		segment = append([]*types.Header{parent}, segment...)
+	}
+}
+
+// deliver sends removed blocks which subscriber already got, or Resync if canonical chain does not connect to them,
+// then blocks which reached required confirmations. Returns false if subscriber channel is full.
+func (s *Service) deliver(chain entities.Chain, sub *subscriber, previous []*types.Header, ancestor int, canonical []*types.Header) bool {
+	send := func(eventType EventType, header *types.Header) bool {
+		select {
+		case sub.ch <- Event{Type: eventType, Chain: chain, Header: header}:
+			return true
+		default:
+			return false
+		}
+	}
+	if ancestor < 0 && sub.delivered > 0 {
+		// blocks between delivered and new chain are unknown, consumer starts again from first block of new chain
+		if !send(Resync, canonical[0]) {
+			return false
+		}
+		sub.delivered = 0
+		if first := canonical[0].Number.Uint64(); first > 0 {
+			sub.delivered = first - 1
+		}
+		return deliverAdded(sub, canonical, send)
+	}
+	for i := len(previous) - 1; i > ancestor; i-- {
+		if previous[i].Number.Uint64() > sub.delivered {
+			continue
+		}
+		if !send(BlockRemoved, previous[i]) {
+			return false
+		}
+		sub.delivered = previous[i].Number.Uint64() - 1
+	}
+	return deliverAdded(sub, canonical, send)
+}
+
+func deliverAdded(sub *subscriber, canonical []*types.Header, send func(EventType, *types.Header) bool) bool {
+	tip := canonical[len(canonical)-1].Number.Uint64()
+	for _, header := range canonical {
+		number := header.Number.Uint64()
+		if number <= sub.delivered || number+sub.confirmations > tip {
+			continue
+		}
+		if !send(BlockAdded, header) {
+			return false
+		}
+		sub.delivered = number
+	}
+	return true
+}
@@ -0,0 +1,215 @@
+package follower
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const chain = entities.ChainEthereum
+
+type fakeSource map[common.Hash]*types.Header
+
+func (f fakeSource) HeaderByHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	if header, ok := f[hash]; ok {
+		return header, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f fakeSource) block(parent *types.Header, fork byte) *types.Header {
+	header := &types.Header{Number: big.NewInt(1), Extra: []byte{fork}}
+	if parent != nil {
+		header.Number = new(big.Int).Add(parent.Number, big.NewInt(1))
+		header.ParentHash = parent.Hash()
+	}
+	f[header.Hash()] = header
+	return header
+}
+
+func TestService_ProcessHead(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	newFollower := func(confirmations uint64) (*Service, *subscriber) {
+		srv := NewService(appLog, nil, 16)
+		sub := &subscriber{confirmations: confirmations, ch: make(chan Event, subscriberBuffer)}
+		srv.chains[chain] = &chainFollower{subs: map[int]*subscriber{0: sub}}
+		return srv, sub
+	}
+
+	t.Run("missed blocks are fetched by parent hash", func(t *testing.T) {
+		// given
+		srv, sub := newFollower(0)
+		source := fakeSource{}
+		b1 := source.block(nil, 0)
+		b2 := source.block(b1, 0)
+		b3 := source.block(b2, 0)
+		b4 := source.block(b3, 0)
+
+		// when
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, b1))
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, b4))
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, b4))
+
+		// then
+		requireEvents(t, sub, []EventType{BlockAdded, BlockAdded, BlockAdded, BlockAdded}, []*types.Header{b1, b2, b3, b4})
+	})
+
+	t.Run("reorg removes replaced blocks before adding new ones", func(t *testing.T) {
+		// given
+		srv, sub := newFollower(0)
+		source := fakeSource{}
+		b1 := source.block(nil, 0)
+		b2 := source.block(b1, 0)
+		b3a := source.block(b2, 'a')
+		b3b := source.block(b2, 'b')
+		b4b := source.block(b3b, 'b')
+
+		// when
+		for _, head := range []*types.Header{b1, b2, b3a, b4b} {
+			require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, head))
+		}
+
+		// then
+		requireEvents(t, sub,
+			[]EventType{BlockAdded, BlockAdded, BlockAdded, BlockRemoved, BlockAdded, BlockAdded},
+			[]*types.Header{b1, b2, b3a, b3a, b3b, b4b},
+		)
+	})
+
+	t.Run("blocks are delivered after confirmations", func(t *testing.T) {
+		// given
+		srv, sub := newFollower(2)
+		source := fakeSource{}
+		b1 := source.block(nil, 0)
+		b2 := source.block(b1, 0)
+		b3 := source.block(b2, 0)
+		b4a := source.block(b3, 'a')
+		b4b := source.block(b3, 'b')
+		b5b := source.block(b4b, 'b')
+
+		// when
+		for _, head := range []*types.Header{b1, b2, b3, b4a, b5b} {
+			require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, head))
+		}
+
+		// then shallow reorg of unconfirmed block is not visible
+		requireEvents(t, sub, []EventType{BlockAdded, BlockAdded, BlockAdded}, []*types.Header{b1, b2, b3})
+	})
+
+	t.Run("blocks missed during outage are delivered beyond window", func(t *testing.T) {
+		// given
+		srv, sub := newFollower(0)
+		source := fakeSource{}
+		chainHeaders := []*types.Header{source.block(nil, 0)}
+		for len(chainHeaders) < 40 {
+			chainHeaders = append(chainHeaders, source.block(chainHeaders[len(chainHeaders)-1], 0))
+		}
+		expected := make([]EventType, len(chainHeaders))
+		for i := range expected {
+			expected[i] = BlockAdded
+		}
+
+		// when
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, chainHeaders[0]))
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, chainHeaders[len(chainHeaders)-1]))
+
+		// then
+		requireEvents(t, sub, expected, chainHeaders)
+		require.Len(t, srv.chains[chain].canonical, 16)
+	})
+
+	t.Run("gap wider than max gap is resync", func(t *testing.T) {
+		// given
+		srv, sub := newFollower(0)
+		source := fakeSource{}
+		b1 := source.block(nil, 0)
+		far := &types.Header{Number: big.NewInt(maxGap + 2), ParentHash: common.HexToHash("0x01")}
+
+		// when
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, b1))
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, far))
+
+		// then
+		requireEvents(t, sub, []EventType{BlockAdded, Resync, BlockAdded}, []*types.Header{b1, far, far})
+	})
+
+	t.Run("reorg deeper than window is resync", func(t *testing.T) {
+		// given
+		srv, sub := newFollower(0)
+		source := fakeSource{}
+		root := source.block(nil, 0)
+		a, b := root, root
+		for i := 0; i < 20; i++ {
+			a = source.block(a, 'a')
+			b = source.block(b, 'b')
+		}
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, root))
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, a))
+		for len(sub.ch) > 0 {
+			<-sub.ch
+		}
+
+		// when
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, b))
+
+		// then no removed events for window which does not cover fork point
+		requireEvents(t, sub, []EventType{Resync, BlockAdded}, []*types.Header{b, b})
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		// given
+		srv, _ := newFollower(0)
+		source := fakeSource{}
+		b1 := source.block(nil, 0)
+		b2 := source.block(b1, 0)
+		b3 := source.block(b2, 0)
+		delete(source, b2.Hash())
+
+		// when
+		require.NoError(t, srv.processHead(context.Background(), chain, srv.chains[chain], source, b1))
+		err := srv.processHead(context.Background(), chain, srv.chains[chain], source, b3)
+
+		// then
+		require.ErrorIs(t, err, ErrUnknownParent)
+	})
+
+	t.Run("dropping last slow subscriber stops following chain", func(t *testing.T) {
+		// given
+		srv := NewService(appLog, nil, 16)
+		sub := &subscriber{ch: make(chan Event)}
+		unsubscribed := false
+		follower := &chainFollower{subs: map[int]*subscriber{0: sub}, unsubscribe: func() { unsubscribed = true }}
+		srv.chains[chain] = follower
+		source := fakeSource{}
+		b1 := source.block(nil, 0)
+		b2 := source.block(b1, 0)
+
+		// when
+		require.NoError(t, srv.processHead(context.Background(), chain, follower, source, b1))
+		require.NoError(t, srv.processHead(context.Background(), chain, follower, source, b2))
+
+		// then
+		require.True(t, unsubscribed)
+		require.NotContains(t, srv.chains, chain)
+		_, open := <-sub.ch
+		require.False(t, open)
+		require.Len(t, follower.canonical, 1) // head after stop is ignored
+	})
+}
+
+func requireEvents(t *testing.T, sub *subscriber, expected []EventType, headers []*types.Header) {
+	require.Len(t, sub.ch, len(expected))
+	for i := range expected {
+		event := <-sub.ch
+		require.Equal(t, expected[i], event.Type, "event %d", i)
+		require.Equal(t, headers[i].Hash(), event.Header.Hash(), "event %d", i)
+	}
+}
@@ -0,0 +1,179 @@
+package gas
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	// gasPriceOracleAddress is GasPriceOracle predeploy of OP-stack chains
+	gasPriceOracleAddress = common.HexToAddress("0x420000000000000000000000000000000000000F")
+	// nodeInterfaceAddress is virtual NodeInterface contract of arbitrum nitro, it is served by eth_call only
+	nodeInterfaceAddress = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+)
+
+var rollups = map[entities.Chain]entities.Rollup{
+	entities.ChainOptimism: entities.RollupOPStack,
+	entities.ChainArbitrum: entities.RollupArbitrum,
+}
+
+// feeBackend is part of rpc client used by fee estimation
+type feeBackend interface {
+	bind.ContractCaller
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// EstimateFee returns total fee of transaction on chain, L1 data fee is included on rollups
+func (s *Service) EstimateFee(ctx context.Context, chain entities.Chain, msg ethereum.CallMsg) (*entities.FeeEstimate, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, ErrChainNotAvailable
+	}
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return s.EstimateFeeWithClient(ctx, client, chain, msg)
+}
+
+// EstimateFeeWithClient is EstimateFee which uses already opened client of chain
+func (s *Service) EstimateFeeWithClient(ctx context.Context, client *ethclient.Client, chain entities.Chain, msg ethereum.CallMsg) (*entities.FeeEstimate, error) {
+	return s.estimateFee(ctx, client, chain, msg)
+}
+
+func (s *Service) estimateFee(ctx context.Context, backend feeBackend, chain entities.Chain, msg ethereum.CallMsg) (*entities.FeeEstimate, error) {
+	rollup := rollups[chain]
+	if rollup == entities.RollupArbitrum {
+		gasLimit, baseFee, l1Fee, err := s.arbitrumFee(ctx, backend, msg)
+		if err != nil {
+			return nil, err
+		}
+		return newFeeEstimate(chain, rollup, gasLimit, baseFee, l1Fee), nil
+	}
+
+	gasLimit, err := backend.EstimateGas(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate gas: %w", err)
+	}
+	gasPrice, err := backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get gas price: %w", err)
+	}
+	l1Fee := new(big.Int)
+	if rollup == entities.RollupOPStack {
+		if l1Fee, err = s.opStackL1Fee(ctx, backend, chain, msg, gasLimit, gasPrice); err != nil {
+			return nil, err
+		}
+	}
+	return newFeeEstimate(chain, rollup, gasLimit, gasPrice, l1Fee), nil
+}
+
+// opStackL1Fee asks GasPriceOracle for L1 data fee of transaction. Oracle expects unsigned transaction,
+// size of signature is added by oracle itself.
+func (s *Service) opStackL1Fee(
+	ctx context.Context,
+	backend feeBackend,
+	chain entities.Chain,
+	msg ethereum.CallMsg,
+	gasLimit uint64,
+	gasPrice *big.Int,
+) (*big.Int, error) {
+	var nonce uint64
+	if msg.From != (common.Address{}) {
+		var err error
+		if nonce, err = backend.PendingNonceAt(ctx, msg.From); err != nil {
+			return nil, fmt.Errorf("unable to get nonce: %w", err)
+		}
+	}
+	value := msg.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	unsigned, err := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chain.ChainIDBI(),
+		Nonce:     nonce,
+		GasTipCap: gasPrice,
+		GasFeeCap: gasPrice,
+		Gas:       gasLimit,
+		To:        msg.To,
+		Value:     value,
+		Data:      msg.Data,
+	}).MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode transaction: %w", err)
+	}
+	oracle, err := NewGasPriceOracleCaller(gasPriceOracleAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := oracle.GetL1Fee(&bind.CallOpts{Context: ctx}, unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get l1 fee: %w", err)
+	}
+	return fee, nil
+}
+
+// arbitrumFee splits gas estimate of NodeInterface.gasEstimateComponents to L2 execution gas and gas which pays
+// L1 data fee, both are paid with L2 base fee
+func (s *Service) arbitrumFee(ctx context.Context, backend feeBackend, msg ethereum.CallMsg) (gasLimit uint64, baseFee, l1Fee *big.Int, err error) {
+	parsed, err := NodeInterfaceMetaData.GetAbi()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	var to common.Address
+	if msg.To != nil {
+		to = *msg.To
+	}
+	input, err := parsed.Pack("gasEstimateComponents", to, msg.To == nil, msg.Data)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	nodeInterface := nodeInterfaceAddress
+	output, err := backend.CallContract(ctx, ethereum.CallMsg{From: msg.From, To: &nodeInterface, Value: msg.Value, Data: input}, nil)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("unable to estimate gas components: %w", err)
+	}
+	values, err := parsed.Unpack("gasEstimateComponents", output)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("unable to unpack gas components: %w", err)
+	}
+	gasEstimate, gasForL1 := values[0].(uint64), values[1].(uint64)
+	baseFee = values[2].(*big.Int)
+	if gasForL1 > gasEstimate {
+		return 0, nil, nil, fmt.Errorf("invalid gas components: l1 gas %d is above total %d", gasForL1, gasEstimate)
+	}
+	l1Fee = new(big.Int).Mul(new(big.Int).SetUint64(gasForL1), baseFee)
+	return gasEstimate - gasForL1, baseFee, l1Fee, nil
+}
+
+func newFeeEstimate(chain entities.Chain, rollup entities.Rollup, gasLimit uint64, gasPrice, l1Fee *big.Int) *entities.FeeEstimate {
+	token := entities.MapChainToFuel(chain)
+	execution := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), gasPrice)
+	return &entities.FeeEstimate{
+		Chain:        chain,
+		ChainName:    chain.String(),
+		Token:        token,
+		Rollup:       rollup,
+		GasLimit:     gasLimit,
+		GasPrice:     NewGasFee(gasPrice),
+		ExecutionFee: newNativeFee(token, execution),
+		L1DataFee:    newNativeFee(token, l1Fee),
+		TotalFee:     newNativeFee(token, new(big.Int).Add(execution, l1Fee)),
+	}
+}
+
+func newNativeFee(token entities.Token, wei *big.Int) entities.NativeFee {
+	return entities.NativeFee{Wei: wei.String(), Amount: entities.CoinFromWEI(token, wei)}
+}
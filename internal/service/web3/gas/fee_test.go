@@ -0,0 +1,122 @@
+package gas
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackend answers rollup contracts with packed outputs and records their inputs
+type fakeBackend struct {
+	gasLimit uint64
+	gasPrice *big.Int
+	outputs  map[common.Address][]byte
+	calls    map[common.Address][]byte
+}
+
+func (f *fakeBackend) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (f *fakeBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.calls[*call.To] = call.Data
+	return f.outputs[*call.To], nil
+}
+
+func (f *fakeBackend) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return f.gasLimit, nil
+}
+
+func (f *fakeBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return f.gasPrice, nil
+}
+
+func (f *fakeBackend) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	return 7, nil
+}
+
+func packOutputs(t *testing.T, parsed *abi.ABI, method string, values ...interface{}) []byte {
+	output, err := parsed.Methods[method].Outputs.Pack(values...)
+	require.NoError(t, err)
+	return output
+}
+
+func TestService_EstimateFee(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv, err := NewService(appLog, config.GasConfig{})
+	require.NoError(t, err)
+	to := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	msg := ethereum.CallMsg{From: common.HexToAddress("0x1"), To: &to, Data: []byte{0x09, 0x5e, 0xa7, 0xb3}}
+
+	t.Run("op-stack adds l1 data fee", func(t *testing.T) {
+		// given
+		oracleABI, err := GasPriceOracleMetaData.GetAbi()
+		require.NoError(t, err)
+		backend := &fakeBackend{
+			gasLimit: 50_000,
+			gasPrice: big.NewInt(1_000_000),
+			outputs:  map[common.Address][]byte{gasPriceOracleAddress: packOutputs(t, oracleABI, "getL1Fee", big.NewInt(400_000_000_000))},
+			calls:    make(map[common.Address][]byte),
+		}
+
+		// when
+		fee, err := srv.estimateFee(context.Background(), backend, entities.ChainOptimism, msg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, entities.RollupOPStack, fee.Rollup)
+		require.Equal(t, "50000000000", fee.ExecutionFee.Wei)
+		require.Equal(t, "400000000000", fee.L1DataFee.Wei)
+		require.Equal(t, "450000000000", fee.TotalFee.Wei)
+		require.Equal(t, "0.00000045", fee.TotalFee.Amount)
+		require.Contains(t, backend.calls, gasPriceOracleAddress)
+	})
+
+	t.Run("arbitrum splits gas components", func(t *testing.T) {
+		// given
+		nodeABI, err := NodeInterfaceMetaData.GetAbi()
+		require.NoError(t, err)
+		baseFee := big.NewInt(10_000_000)
+		backend := &fakeBackend{
+			outputs: map[common.Address][]byte{
+				nodeInterfaceAddress: packOutputs(t, nodeABI, "gasEstimateComponents", uint64(300_000), uint64(250_000), baseFee, big.NewInt(20_000_000_000)),
+			},
+			calls: make(map[common.Address][]byte),
+		}
+
+		// when
+		fee, err := srv.estimateFee(context.Background(), backend, entities.ChainArbitrum, msg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, entities.RollupArbitrum, fee.Rollup)
+		require.Equal(t, uint64(50_000), fee.GasLimit)
+		require.Equal(t, "10000000", fee.GasPrice.Wei)
+		require.Equal(t, "500000000000", fee.ExecutionFee.Wei)
+		require.Equal(t, "2500000000000", fee.L1DataFee.Wei)
+		require.Equal(t, "3000000000000", fee.TotalFee.Wei)
+		args, err := nodeABI.Methods["gasEstimateComponents"].Inputs.Unpack(backend.calls[nodeInterfaceAddress][4:])
+		require.NoError(t, err)
+		require.Equal(t, to, args[0])
+		require.Equal(t, false, args[1])
+	})
+
+	t.Run("other chains have execution fee only", func(t *testing.T) {
+		backend := &fakeBackend{gasLimit: 21_000, gasPrice: big.NewInt(30_000_000_000), calls: make(map[common.Address][]byte)}
+		fee, err := srv.estimateFee(context.Background(), backend, entities.ChainEthereum, msg)
+		require.NoError(t, err)
+		require.Empty(t, fee.Rollup)
+		require.Equal(t, "0", fee.L1DataFee.Wei)
+		require.Equal(t, "630000000000000", fee.TotalFee.Wei)
+		require.Empty(t, backend.calls)
+	})
+}
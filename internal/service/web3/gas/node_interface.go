@@ -0,0 +1,202 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package gas
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// NodeInterfaceMetaData contains all meta data concerning the NodeInterface contract.
+var NodeInterfaceMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"contractCreation\",\"type\":\"bool\"},{\"internalType\":\"bytes\",\"name\":\"data\",\"type\":\"bytes\"}],\"name\":\"gasEstimateComponents\",\"outputs\":[{\"internalType\":\"uint64\",\"name\":\"gasEstimate\",\"type\":\"uint64\"},{\"internalType\":\"uint64\",\"name\":\"gasEstimateForL1\",\"type\":\"uint64\"},{\"internalType\":\"uint256\",\"name\":\"baseFee\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"l1BaseFeeEstimate\",\"type\":\"uint256\"}],\"stateMutability\":\"payable\",\"type\":\"function\"}]",
+}
+
+// NodeInterfaceABI is the input ABI used to generate the binding from.
+// Deprecated: Use NodeInterfaceMetaData.ABI instead.
+var NodeInterfaceABI = NodeInterfaceMetaData.ABI
+
+// NodeInterface is an auto generated Go binding around an Ethereum contract.
+type NodeInterface struct {
+	NodeInterfaceCaller     // Read-only binding to the contract
+	NodeInterfaceTransactor // Write-only binding to the contract
+	NodeInterfaceFilterer   // Log filterer for contract events
+}
+
+// NodeInterfaceCaller is an auto generated read-only Go binding around an Ethereum contract.
+type NodeInterfaceCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// NodeInterfaceTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type NodeInterfaceTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// NodeInterfaceFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type NodeInterfaceFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// NodeInterfaceSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type NodeInterfaceSession struct {
+	Contract     *NodeInterface    // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// NodeInterfaceCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type NodeInterfaceCallerSession struct {
+	Contract *NodeInterfaceCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts        // Call options to use throughout this session
+}
+
+// NodeInterfaceTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type NodeInterfaceTransactorSession struct {
+	Contract     *NodeInterfaceTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// NodeInterfaceRaw is an auto generated low-level Go binding around an Ethereum contract.
+type NodeInterfaceRaw struct {
+	Contract *NodeInterface // Generic contract binding to access the raw methods on
+}
+
+// NodeInterfaceCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type NodeInterfaceCallerRaw struct {
+	Contract *NodeInterfaceCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// NodeInterfaceTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type NodeInterfaceTransactorRaw struct {
+	Contract *NodeInterfaceTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewNodeInterface creates a new instance of NodeInterface, bound to a specific deployed contract.
+func NewNodeInterface(address common.Address, backend bind.ContractBackend) (*NodeInterface, error) {
+	contract, err := bindNodeInterface(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeInterface{NodeInterfaceCaller: NodeInterfaceCaller{contract: contract}, NodeInterfaceTransactor: NodeInterfaceTransactor{contract: contract}, NodeInterfaceFilterer: NodeInterfaceFilterer{contract: contract}}, nil
+}
+
+// NewNodeInterfaceCaller creates a new read-only instance of NodeInterface, bound to a specific deployed contract.
+func NewNodeInterfaceCaller(address common.Address, caller bind.ContractCaller) (*NodeInterfaceCaller, error) {
+	contract, err := bindNodeInterface(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeInterfaceCaller{contract: contract}, nil
+}
+
+// NewNodeInterfaceTransactor creates a new write-only instance of NodeInterface, bound to a specific deployed contract.
+func NewNodeInterfaceTransactor(address common.Address, transactor bind.ContractTransactor) (*NodeInterfaceTransactor, error) {
+	contract, err := bindNodeInterface(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeInterfaceTransactor{contract: contract}, nil
+}
+
+// NewNodeInterfaceFilterer creates a new log filterer instance of NodeInterface, bound to a specific deployed contract.
+func NewNodeInterfaceFilterer(address common.Address, filterer bind.ContractFilterer) (*NodeInterfaceFilterer, error) {
+	contract, err := bindNodeInterface(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeInterfaceFilterer{contract: contract}, nil
+}
+
+// bindNodeInterface binds a generic wrapper to an already deployed contract.
+func bindNodeInterface(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := NodeInterfaceMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_NodeInterface *NodeInterfaceRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _NodeInterface.Contract.NodeInterfaceCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_NodeInterface *NodeInterfaceRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NodeInterface.Contract.NodeInterfaceTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_NodeInterface *NodeInterfaceRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _NodeInterface.Contract.NodeInterfaceTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_NodeInterface *NodeInterfaceCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _NodeInterface.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_NodeInterface *NodeInterfaceTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _NodeInterface.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_NodeInterface *NodeInterfaceTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _NodeInterface.Contract.contract.Transact(opts, method, params...)
+}
+
+// GasEstimateComponents is a paid mutator transaction binding the contract method 0xc94e6eeb.
+//
+// Solidity: function gasEstimateComponents(address to, bool contractCreation, bytes data) payable returns(uint64 gasEstimate, uint64 gasEstimateForL1, uint256 baseFee, uint256 l1BaseFeeEstimate)
+func (_NodeInterface *NodeInterfaceTransactor) GasEstimateComponents(opts *bind.TransactOpts, to common.Address, contractCreation bool, data []byte) (*types.Transaction, error) {
+	return _NodeInterface.contract.Transact(opts, "gasEstimateComponents", to, contractCreation, data)
+}
+
+// GasEstimateComponents is a paid mutator transaction binding the contract method 0xc94e6eeb.
+//
+// Solidity: function gasEstimateComponents(address to, bool contractCreation, bytes data) payable returns(uint64 gasEstimate, uint64 gasEstimateForL1, uint256 baseFee, uint256 l1BaseFeeEstimate)
+func (_NodeInterface *NodeInterfaceSession) GasEstimateComponents(to common.Address, contractCreation bool, data []byte) (*types.Transaction, error) {
+	return _NodeInterface.Contract.GasEstimateComponents(&_NodeInterface.TransactOpts, to, contractCreation, data)
+}
+
+// GasEstimateComponents is a paid mutator transaction binding the contract method 0xc94e6eeb.
+//
+// Solidity: function gasEstimateComponents(address to, bool contractCreation, bytes data) payable returns(uint64 gasEstimate, uint64 gasEstimateForL1, uint256 baseFee, uint256 l1BaseFeeEstimate)
+func (_NodeInterface *NodeInterfaceTransactorSession) GasEstimateComponents(to common.Address, contractCreation bool, data []byte) (*types.Transaction, error) {
+	return _NodeInterface.Contract.GasEstimateComponents(&_NodeInterface.TransactOpts, to, contractCreation, data)
+}
@@ -0,0 +1,217 @@
+package gas
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBlockCount = 20
+	defaultCacheTTL   = 5 * time.Second
+	// defaultTxPercentile is priority fee percentile paid by sent transactions
+	defaultTxPercentile = 50
+	// baseFeeMultiplier keeps maxFeePerGas valid while base fee grows for several full blocks in a row
+	baseFeeMultiplier = 2
+)
+
+var defaultPercentiles = []float64{10, 50, 90}
+
+var ErrChainNotAvailable = errors.New("chain is not available")
+
+type cachedInfo struct {
+	info      *entities.GasInfo
+	fetchedAt time.Time
+}
+
+// Service gives gas guidance of chains from eth_feeHistory, chains without EIP-1559 get legacy gas price only
+type Service struct {
+	log         logger.AppLogger
+	blockCount  uint64
+	percentiles []float64
+	cacheTTL    time.Duration
+	// txPercentile and maxFees are fee strategy of sent transactions
+	txPercentile float64
+	maxFees      map[entities.Chain]*big.Int
+
+	mu    sync.Mutex
+	cache map[entities.Chain]*cachedInfo
+}
+
+func NewService(log logger.AppLogger, conf config.GasConfig) (*Service, error) {
+	srv := &Service{
+		log:         log.With(zap.String("service", "gas")),
+		blockCount:  conf.BlockCount,
+		percentiles: conf.Percentiles,
+		cacheTTL:    conf.CacheTTL,
+		cache:       make(map[entities.Chain]*cachedInfo),
+
+		txPercentile: defaultTxPercentile,
+		maxFees:      make(map[entities.Chain]*big.Int, len(conf.MaxFeePerGas)),
+	}
+	if srv.blockCount == 0 {
+		srv.blockCount = defaultBlockCount
+	}
+	if len(srv.percentiles) == 0 {
+		srv.percentiles = defaultPercentiles
+	}
+	if srv.cacheTTL <= 0 {
+		srv.cacheTTL = defaultCacheTTL
+	}
+	if len(srv.percentiles) != 3 || !sort.Float64sAreSorted(srv.percentiles) || srv.percentiles[0] < 0 || srv.percentiles[2] > 100 {
+		return nil, fmt.Errorf("gas percentiles must be 3 ascending values in [0, 100]: %v", srv.percentiles)
+	}
+	if conf.TxPriorityPercentile != nil {
+		srv.txPercentile = *conf.TxPriorityPercentile
+	}
+	if srv.txPercentile < 0 || srv.txPercentile > 100 {
+		return nil, fmt.Errorf("gas tx priority percentile must be in [0, 100]: %v", srv.txPercentile)
+	}
+	for name, gwei := range conf.MaxFeePerGas {
+		chain, err := entities.ChainFromString(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain of max fee per gas %s: %w", name, err)
+		}
+		maxFee, err := decimal.NewFromString(gwei)
+		if err != nil || !maxFee.IsPositive() {
+			return nil, fmt.Errorf("invalid max fee per gas of %s: %s", name, gwei)
+		}
+		srv.maxFees[chain] = utils.GWeiFromETHString(gwei)
+	}
+	return srv, nil
+}
+
+// GetGasInfo returns base fee, recent priority fee percentiles and fee suggestions of chain
+func (s *Service) GetGasInfo(ctx context.Context, chain entities.Chain) (*entities.GasInfo, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, ErrChainNotAvailable
+	}
+	s.mu.Lock()
+	cached, ok := s.cache[chain]
+	s.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < s.cacheTTL {
+		return cached.info, nil
+	}
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	info, err := s.readGasInfo(ctx, client, chain)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.cache[chain] = &cachedInfo{info: info, fetchedAt: time.Now()}
+	s.mu.Unlock()
+	return info, nil
+}
+
+func (s *Service) readGasInfo(ctx context.Context, client *ethclient.Client, chain entities.Chain) (*entities.GasInfo, error) {
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get gas price: %w", err)
+	}
+	info := &entities.GasInfo{
+		Chain:     chain,
+		ChainName: chain.String(),
+		GasPrice:  NewGasFee(gasPrice),
+	}
+	history, err := s.feeHistory(ctx, client, chain, s.percentiles)
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		if info.BlockNumber, err = client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("unable to get latest block: %w", err)
+		}
+		return info, nil
+	}
+	info.EIP1559 = true
+	info.BlockNumber = history.OldestBlock.Uint64() + uint64(len(history.GasUsedRatio)) - 1
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	baseFeeGas := NewGasFee(baseFee)
+	info.BaseFee = &baseFeeGas
+
+	priorityFees := priorityFeePercentiles(history, len(s.percentiles))
+	if priorityFees == nil {
+		// all recent blocks are empty, node estimate is used for every speed
+		tip, errTip := client.SuggestGasTipCap(ctx)
+		if errTip != nil {
+			return nil, fmt.Errorf("unable to get priority fee: %w", errTip)
+		}
+		priorityFees = []*big.Int{tip, tip, tip}
+	} else {
+		for i, fee := range priorityFees {
+			info.Percentiles = append(info.Percentiles, entities.GasPercentile{Percentile: s.percentiles[i], PriorityFee: NewGasFee(fee)})
+		}
+	}
+	info.Suggestions = &entities.GasSuggestions{
+		Slow:   suggestion(baseFee, priorityFees[0]),
+		Normal: suggestion(baseFee, priorityFees[1]),
+		Fast:   suggestion(baseFee, priorityFees[2]),
+	}
+	return info, nil
+}
+
+// priorityFeePercentiles returns median of every reward percentile across non-empty blocks of history,
+// nil is returned when all blocks are empty
+func priorityFeePercentiles(history *ethereum.FeeHistory, count int) []*big.Int {
+	blocks := make([][]*big.Int, 0, len(history.Reward))
+	for block, rewards := range history.Reward {
+		// empty blocks report zero rewards, they say nothing about fee market
+		if (block < len(history.GasUsedRatio) && history.GasUsedRatio[block] == 0) || len(rewards) < count {
+			continue
+		}
+		blocks = append(blocks, rewards)
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	result := make([]*big.Int, count)
+	for i := range result {
+		values := make([]*big.Int, 0, len(blocks))
+		for _, rewards := range blocks {
+			values = append(values, rewards[i])
+		}
+		sort.Slice(values, func(a, b int) bool {
+			return values[a].Cmp(values[b]) < 0
+		})
+		result[i] = values[len(values)/2]
+	}
+	return result
+}
+
+// suggestion returns EIP-1559 fee for next block base fee and priority fee
+func suggestion(baseFee, priorityFee *big.Int) entities.GasSuggestion {
+	return entities.GasSuggestion{
+		MaxFeePerGas:         NewGasFee(maxFeePerGas(baseFee, priorityFee)),
+		MaxPriorityFeePerGas: NewGasFee(priorityFee),
+	}
+}
+
+// maxFeePerGas returns max fee which stays above base fee while it grows for several full blocks
+func maxFeePerGas(baseFee, priorityFee *big.Int) *big.Int {
+	maxFee := new(big.Int).Mul(baseFee, big.NewInt(baseFeeMultiplier))
+	return maxFee.Add(maxFee, priorityFee)
+}
+
+// NewGasFee returns fee in wei and gwei
+func NewGasFee(wei *big.Int) entities.GasFee {
+	return entities.GasFee{Wei: wei.String(), Gwei: utils.ETHFromGWei(wei)}
+}
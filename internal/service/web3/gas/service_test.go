@@ -0,0 +1,76 @@
+package gas
+
+import (
+	"altt/internal/config"
+	"altt/internal/logger"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityFeePercentiles(t *testing.T) {
+	gwei := func(v int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(v), big.NewInt(1_000_000_000))
+	}
+
+	t.Run("median of non-empty blocks", func(t *testing.T) {
+		// given
+		history := &ethereum.FeeHistory{
+			Reward: [][]*big.Int{
+				{gwei(1), gwei(2), gwei(5)},
+				{big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+				{gwei(2), gwei(3), gwei(9)},
+				{gwei(1), gwei(2), gwei(3)},
+			},
+			GasUsedRatio: []float64{0.5, 0, 0.9, 0.4},
+		}
+
+		// when
+		fees := priorityFeePercentiles(history, 3)
+
+		// then
+		require.Equal(t, []*big.Int{gwei(1), gwei(2), gwei(5)}, fees)
+	})
+
+	t.Run("empty blocks", func(t *testing.T) {
+		history := &ethereum.FeeHistory{
+			Reward:       [][]*big.Int{{big.NewInt(0), big.NewInt(0), big.NewInt(0)}},
+			GasUsedRatio: []float64{0},
+		}
+		require.Nil(t, priorityFeePercentiles(history, 3))
+	})
+}
+
+func TestSuggestion(t *testing.T) {
+	result := suggestion(big.NewInt(30_000_000_000), big.NewInt(1_500_000_000))
+	require.Equal(t, "61500000000", result.MaxFeePerGas.Wei)
+	require.Equal(t, "61.5", result.MaxFeePerGas.Gwei)
+	require.Equal(t, "1.5", result.MaxPriorityFeePerGas.Gwei)
+}
+
+func TestNewService(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv, err := NewService(appLog, config.GasConfig{})
+	require.NoError(t, err)
+	require.Equal(t, float64(defaultTxPercentile), srv.txPercentile)
+	for _, percentiles := range [][]float64{{10, 50}, {50, 10, 90}, {10, 50, 101}} {
+		_, err = NewService(appLog, config.GasConfig{Percentiles: percentiles})
+		require.Error(t, err, percentiles)
+	}
+	for _, percentile := range []float64{-1, 101} {
+		percentile := percentile
+		_, err = NewService(appLog, config.GasConfig{TxPriorityPercentile: &percentile})
+		require.Error(t, err, percentile)
+	}
+	zero := 0.0
+	srv, err = NewService(appLog, config.GasConfig{TxPriorityPercentile: &zero})
+	require.NoError(t, err)
+	require.Zero(t, srv.txPercentile)
+	for _, maxFees := range []map[string]string{{"unknown": "100"}, {"eth": "0"}, {"eth": "abc"}} {
+		_, err = NewService(appLog, config.GasConfig{MaxFeePerGas: maxFees})
+		require.Error(t, err, maxFees)
+	}
+}
@@ -0,0 +1,122 @@
+package gas
+
+import (
+	"altt/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+var ErrFeeAboveCap = errors.New("network fee is above max fee per gas of chain")
+
+// methodNotFoundCode is json-rpc error code of method which node does not implement
+const methodNotFoundCode = -32601
+
+// unsupportedMessages are messages of nodes without eth_feeHistory
+var unsupportedMessages = []string{
+	"does not exist",
+	"not supported",
+	"method not found",
+	"not implemented",
+}
+
+// isFeeHistoryUnsupported reports whether node has no eth_feeHistory, so chain gets legacy gas price. Other errors
+// (timeout, rate limit) are failures of the request and are not a reason to treat chain as legacy.
+func isFeeHistoryUnsupported(err error) bool {
+	var rpcErr gethrpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, part := range unsupportedMessages {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// feeHistory reads fee history of chain, nil history is returned for chains without EIP-1559: node has no
+// eth_feeHistory or blocks have no base fee
+func (s *Service) feeHistory(ctx context.Context, backend txFeeBackend, chain entities.Chain, percentiles []float64) (*ethereum.FeeHistory, error) {
+	history, err := backend.FeeHistory(ctx, s.blockCount, nil, percentiles)
+	if err != nil {
+		if !isFeeHistoryUnsupported(err) {
+			return nil, fmt.Errorf("unable to get fee history: %w", err)
+		}
+		s.log.Info("fee history is not available, use legacy gas price", zap.String("chain", chain.String()), zap.String("reason", err.Error()))
+		return nil, nil
+	}
+	if len(history.BaseFee) == 0 || history.BaseFee[len(history.BaseFee)-1].Sign() == 0 {
+		return nil, nil
+	}
+	return history, nil
+}
+
+// txFeeBackend is part of rpc client used by fee strategy
+type txFeeBackend interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// SuggestTxFee returns fee of transaction to send on chain. Priority fee is `gas.tx_priority_percentile` of recent
+// blocks from eth_feeHistory and max fee per gas is capped by `gas.max_fee_per_gas` of chain. Chains without
+// EIP-1559 get legacy gas price.
+func (s *Service) SuggestTxFee(ctx context.Context, client *ethclient.Client, chain entities.Chain) (*entities.TxFee, error) {
+	return s.suggestTxFee(ctx, client, chain)
+}
+
+func (s *Service) suggestTxFee(ctx context.Context, backend txFeeBackend, chain entities.Chain) (*entities.TxFee, error) {
+	maxFee := s.maxFees[chain]
+	history, err := s.feeHistory(ctx, backend, chain, []float64{s.txPercentile})
+	if err != nil {
+		return nil, err
+	}
+	if history == nil {
+		gasPrice, errPrice := backend.SuggestGasPrice(ctx)
+		if errPrice != nil {
+			return nil, fmt.Errorf("unable to get gas price: %w", errPrice)
+		}
+		if maxFee != nil && gasPrice.Cmp(maxFee) > 0 {
+			return nil, fmt.Errorf("%w: gas price %s gwei, cap %s gwei", ErrFeeAboveCap, NewGasFee(gasPrice).Gwei, NewGasFee(maxFee).Gwei)
+		}
+		return &entities.TxFee{GasPrice: gasPrice}, nil
+	}
+
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	var tip *big.Int
+	if tips := priorityFeePercentiles(history, 1); tips != nil {
+		tip = tips[0]
+	} else if tip, err = backend.SuggestGasTipCap(ctx); err != nil {
+		return nil, fmt.Errorf("unable to get priority fee: %w", err)
+	}
+	result := &entities.TxFee{
+		GasFeeCap: maxFeePerGas(baseFee, tip),
+		GasTipCap: tip,
+	}
+	if maxFee == nil || result.GasFeeCap.Cmp(maxFee) <= 0 {
+		return result, nil
+	}
+	// capped fee still has to cover base fee of next block, priority fee is lowered to fit under the cap
+	if maxFee.Cmp(baseFee) <= 0 {
+		return nil, fmt.Errorf("%w: base fee %s gwei, cap %s gwei", ErrFeeAboveCap, NewGasFee(baseFee).Gwei, NewGasFee(maxFee).Gwei)
+	}
+	s.log.Info("max fee per gas is capped",
+		zap.String("chain", chain.String()),
+		zap.String("max_fee_per_gas", NewGasFee(result.GasFeeCap).Gwei),
+		zap.String("cap", NewGasFee(maxFee).Gwei),
+	)
+	result.GasFeeCap = maxFee
+	if headroom := new(big.Int).Sub(maxFee, baseFee); result.GasTipCap.Cmp(headroom) > 0 {
+		result.GasTipCap = headroom
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package gas
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+)
+
+// rpcError is json-rpc error returned by node
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e rpcError) Error() string  { return e.message }
+func (e rpcError) ErrorCode() int { return e.code }
+
+// fakeTxFeeBackend serves fee history of single block, history is not available when it is nil
+type fakeTxFeeBackend struct {
+	history    *ethereum.FeeHistory
+	historyErr error
+	tipCap     *big.Int
+	gasPrice   *big.Int
+}
+
+func (f *fakeTxFeeBackend) FeeHistory(context.Context, uint64, *big.Int, []float64) (*ethereum.FeeHistory, error) {
+	if f.historyErr != nil {
+		return nil, f.historyErr
+	}
+	if f.history == nil {
+		return nil, errors.New("the method eth_feeHistory does not exist")
+	}
+	return f.history, nil
+}
+
+func (f *fakeTxFeeBackend) SuggestGasTipCap(context.Context) (*big.Int, error) {
+	return f.tipCap, nil
+}
+
+func (f *fakeTxFeeBackend) SuggestGasPrice(context.Context) (*big.Int, error) {
+	return f.gasPrice, nil
+}
+
+func TestService_SuggestTxFee(t *testing.T) {
+	gwei := func(v int64) *big.Int {
+		return new(big.Int).Mul(big.NewInt(v), big.NewInt(1_000_000_000))
+	}
+	history := func(baseFee, tip *big.Int) *ethereum.FeeHistory {
+		return &ethereum.FeeHistory{
+			Reward:       [][]*big.Int{{tip}},
+			BaseFee:      []*big.Int{baseFee, baseFee},
+			GasUsedRatio: []float64{0.5},
+		}
+	}
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv, err := NewService(appLog, config.GasConfig{MaxFeePerGas: map[string]string{"eth": "100"}})
+	require.NoError(t, err)
+
+	table := map[string]struct {
+		chain    entities.Chain
+		backend  *fakeTxFeeBackend
+		expected entities.TxFee
+	}{
+		"percentile priority fee": {
+			chain:    entities.ChainEthereum,
+			backend:  &fakeTxFeeBackend{history: history(gwei(30), gwei(2))},
+			expected: entities.TxFee{GasFeeCap: gwei(62), GasTipCap: gwei(2)},
+		},
+		"suggested priority fee on empty blocks": {
+			chain: entities.ChainEthereum,
+			backend: &fakeTxFeeBackend{
+				history: &ethereum.FeeHistory{Reward: [][]*big.Int{{big.NewInt(0)}}, BaseFee: []*big.Int{gwei(30), gwei(30)}, GasUsedRatio: []float64{0}},
+				tipCap:  gwei(1),
+			},
+			expected: entities.TxFee{GasFeeCap: gwei(61), GasTipCap: gwei(1)},
+		},
+		"max fee is capped": {
+			chain:    entities.ChainEthereum,
+			backend:  &fakeTxFeeBackend{history: history(gwei(60), gwei(3))},
+			expected: entities.TxFee{GasFeeCap: gwei(100), GasTipCap: gwei(3)},
+		},
+		"priority fee is lowered under cap": {
+			chain:    entities.ChainEthereum,
+			backend:  &fakeTxFeeBackend{history: history(gwei(98), gwei(5))},
+			expected: entities.TxFee{GasFeeCap: gwei(100), GasTipCap: gwei(2)},
+		},
+		"chain without cap": {
+			chain:    entities.ChainPolygon,
+			backend:  &fakeTxFeeBackend{history: history(gwei(98), gwei(5))},
+			expected: entities.TxFee{GasFeeCap: gwei(201), GasTipCap: gwei(5)},
+		},
+		"legacy gas price": {
+			chain:    entities.ChainEthereum,
+			backend:  &fakeTxFeeBackend{gasPrice: gwei(40)},
+			expected: entities.TxFee{GasPrice: gwei(40)},
+		},
+		"legacy gas price of missing method": {
+			chain:    entities.ChainEthereum,
+			backend:  &fakeTxFeeBackend{historyErr: rpcError{code: -32601, message: "unknown method"}, gasPrice: gwei(40)},
+			expected: entities.TxFee{GasPrice: gwei(40)},
+		},
+		"legacy gas price without base fee": {
+			chain: entities.ChainEthereum,
+			backend: &fakeTxFeeBackend{
+				history:  &ethereum.FeeHistory{Reward: [][]*big.Int{{gwei(1)}}, BaseFee: []*big.Int{big.NewInt(0), big.NewInt(0)}, GasUsedRatio: []float64{0.5}},
+				gasPrice: gwei(40),
+			},
+			expected: entities.TxFee{GasPrice: gwei(40)},
+		},
+	}
+	for name, tc := range table {
+		t.Run(name, func(t *testing.T) {
+			// when
+			fee, err := srv.suggestTxFee(context.Background(), tc.backend, tc.chain)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *fee)
+		})
+	}
+
+	t.Run("base fee above cap", func(t *testing.T) {
+		_, err := srv.suggestTxFee(context.Background(), &fakeTxFeeBackend{history: history(gwei(100), gwei(1))}, entities.ChainEthereum)
+		require.ErrorIs(t, err, ErrFeeAboveCap)
+	})
+
+	t.Run("failed fee history is not legacy", func(t *testing.T) {
+		backend := &fakeTxFeeBackend{historyErr: rpcError{code: 429, message: "too many requests"}, gasPrice: gwei(40)}
+		_, err := srv.suggestTxFee(context.Background(), backend, entities.ChainEthereum)
+		require.ErrorIs(t, err, backend.historyErr)
+	})
+
+	t.Run("legacy gas price above cap", func(t *testing.T) {
+		_, err := srv.suggestTxFee(context.Background(), &fakeTxFeeBackend{gasPrice: gwei(101)}, entities.ChainEthereum)
+		require.ErrorIs(t, err, ErrFeeAboveCap)
+	})
+}
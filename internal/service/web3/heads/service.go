@@ -0,0 +1,129 @@
+package heads
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/web3"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	subscriberBuffer    = 4
+)
+
+// Service polls latest header of chains which have subscribers and fans new heads out to them.
+// Configured rpc nodes are plain http endpoints, so polling is used instead of eth_subscribe.
+type Service struct {
+	log          logger.AppLogger
+	pollInterval time.Duration
+
+	mu      sync.Mutex
+	nextID  int
+	subs    map[entities.Chain]map[int]chan *types.Header
+	pollers map[entities.Chain]context.CancelFunc
+}
+
+func NewService(log logger.AppLogger, pollInterval time.Duration) *Service {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	return &Service{
+		log:          log.With(zap.String("service", "heads")),
+		pollInterval: pollInterval,
+		subs:         make(map[entities.Chain]map[int]chan *types.Header),
+		pollers:      make(map[entities.Chain]context.CancelFunc),
+	}
+}
+
+// Subscribe returns channel with new heads of the chain and function to cancel subscription.
+// Slow subscriber misses heads instead of blocking others, so consumers should rely on head number, not on every head.
+func (s *Service) Subscribe(chain entities.Chain) (<-chan *types.Header, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	ch := make(chan *types.Header, subscriberBuffer)
+	if _, ok := s.subs[chain]; !ok {
+		s.subs[chain] = make(map[int]chan *types.Header)
+	}
+	s.subs[chain][id] = ch
+	if _, ok := s.pollers[chain]; !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.pollers[chain] = cancel
+		go s.poll(ctx, chain)
+	}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.unsubscribe(chain, id)
+		})
+	}
+}
+
+func (s *Service) unsubscribe(chain entities.Chain, id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch, ok := s.subs[chain][id]
+	if !ok {
+		return
+	}
+	delete(s.subs[chain], id)
+	close(ch)
+	if len(s.subs[chain]) > 0 {
+		return
+	}
+	delete(s.subs, chain)
+	if cancel, ok := s.pollers[chain]; ok {
+		cancel()
+		delete(s.pollers, chain)
+	}
+}
+
+func (s *Service) poll(ctx context.Context, chain entities.Chain) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	var last uint64
+	for {
+		head, err := s.latestHeader(ctx, chain)
+		if err != nil {
+			s.log.Error("unable to get latest header", err, zap.String("chain", chain.String()))
+		} else if head.Number.Uint64() > last {
+			last = head.Number.Uint64()
+			s.publish(chain, head)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) latestHeader(ctx context.Context, chain entities.Chain) (*types.Header, error) {
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(ctx, s.pollInterval)
+	defer cancel()
+	return client.HeaderByNumber(ctx, nil)
+}
+
+func (s *Service) publish(chain entities.Chain, head *types.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.subs[chain] {
+		select {
+		case ch <- head:
+		default:
+		}
+	}
+}
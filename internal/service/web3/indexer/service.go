@@ -0,0 +1,479 @@
+package indexer
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/approver"
+	"altt/internal/service/web3/follower"
+	"altt/internal/storage"
+	"container/list"
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReconcileInterval = 10 * time.Minute
+	defaultMaxTracked        = 10_000
+	defaultMaxBlockRange     = 2_000
+	defaultMaxStaleness      = time.Minute
+)
+
+// Service keeps erc20 balances of queried addresses up to date from Transfer logs, so repeat lookups are served locally.
+// Logs are filtered for every block added to canonical chain as configured rpc nodes are plain http endpoints without
+// log subscriptions. Removed block makes balances to be read again, as its transfers may be not part of new chain.
+// Indexed balances are periodically compared with balanceOf, which also repairs drift of rebasing or fee-on-transfer tokens.
+// Cursor and balances changed by block are saved to storage after every indexed block, so after restart indexing
+// continues from saved cursor.
+// Balances of chain which was not indexed within MaxStaleness (node errors, follower lag) are not served.
+type Service struct {
+	log      logger.AppLogger
+	erc20    *approver.Service
+	follower *follower.Service
+	storage  *storage.Repository
+	conf     config.IndexerConfig
+
+	mu     sync.Mutex
+	chains map[entities.Chain]*chainIndex
+	// lru orders tracked balances of all chains from most to least recently queried
+	lru    *list.List
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type balanceKey struct {
+	token  common.Address
+	holder common.Address
+}
+
+type chainIndex struct {
+	unsubscribe func()
+	cursor      uint64
+	// indexedAt is when cursor was moved last time
+	indexedAt     time.Time
+	lastReconcile time.Time
+	balances      map[balanceKey]*indexedBalance
+	// dirty is balances changed or removed since state was saved last time
+	dirty map[balanceKey]struct{}
+}
+
+type indexedBalance struct {
+	// value is nil until balance is read at block
+	value   *big.Int
+	block   uint64
+	element *list.Element
+}
+
+// lruEntry is value of lru list element
+type lruEntry struct {
+	chain entities.Chain
+	key   balanceKey
+}
+
+func NewService(
+	log logger.AppLogger,
+	erc20 *approver.Service,
+	followerService *follower.Service,
+	repository *storage.Repository,
+	conf config.IndexerConfig,
+) *Service {
+	if conf.ReconcileInterval <= 0 {
+		conf.ReconcileInterval = defaultReconcileInterval
+	}
+	if conf.MaxTracked <= 0 {
+		conf.MaxTracked = defaultMaxTracked
+	}
+	if conf.MaxBlockRange == 0 {
+		conf.MaxBlockRange = defaultMaxBlockRange
+	}
+	if conf.MaxStaleness <= 0 {
+		conf.MaxStaleness = defaultMaxStaleness
+	}
+	return &Service{
+		log:      log.With(zap.String("service", "indexer")),
+		erc20:    erc20,
+		follower: followerService,
+		storage:  repository,
+		conf:     conf,
+		chains:   make(map[entities.Chain]*chainIndex),
+		lru:      list.New(),
+	}
+}
+
+// Start restores saved state and starts following chains of tracked balances. Disabled indexer does nothing.
+func (s *Service) Start() {
+	if !s.conf.Enabled {
+		return
+	}
+	states, err := s.storage.LoadIndexerStates()
+	if err != nil {
+		s.log.Error("unable to load indexer state, start from scratch", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, state := range states {
+		s.restore(state)
+	}
+	s.evict()
+	for chain := range s.chains {
+		s.followChain(chain)
+	}
+	s.log.Info("indexer started")
+}
+
+// restore loads saved state of chain, must be called under lock
+func (s *Service) restore(state *storage.IndexerState) {
+	index := &chainIndex{
+		cursor:        state.Cursor,
+		indexedAt:     state.IndexedAt,
+		lastReconcile: state.LastReconcile,
+		balances:      make(map[balanceKey]*indexedBalance, len(state.Balances)),
+		dirty:         make(map[balanceKey]struct{}),
+	}
+	for _, balance := range state.Balances {
+		key := balanceKey{token: balance.Token, holder: balance.Holder}
+		block := balance.Block
+		if balance.Value != nil && block < state.Cursor {
+			// unchanged balances are not saved again when cursor moves, they are valid at cursor
+			block = state.Cursor
+		}
+		index.balances[key] = &indexedBalance{
+			value:   balance.Value,
+			block:   block,
+			element: s.lru.PushBack(&lruEntry{chain: state.Chain, key: key}),
+		}
+	}
+	s.chains[state.Chain] = index
+}
+
+// export returns cursor of chain with balances changed since last export and keys of removed balances,
+// must be called under lock
+func (s *Service) export(chain entities.Chain, index *chainIndex) (*storage.IndexerState, []storage.IndexedBalance) {
+	state := &storage.IndexerState{
+		Chain:         chain,
+		Cursor:        index.cursor,
+		IndexedAt:     index.indexedAt,
+		LastReconcile: index.lastReconcile,
+		Balances:      make([]storage.IndexedBalance, 0, len(index.dirty)),
+	}
+	removed := make([]storage.IndexedBalance, 0)
+	for key := range index.dirty {
+		balance, ok := index.balances[key]
+		if !ok {
+			removed = append(removed, storage.IndexedBalance{Token: key.token, Holder: key.holder})
+			continue
+		}
+		state.Balances = append(state.Balances, storage.IndexedBalance{
+			Token:  key.token,
+			Holder: key.holder,
+			Value:  balance.value,
+			Block:  balance.block,
+		})
+	}
+	index.dirty = make(map[balanceKey]struct{})
+	return state, removed
+}
+
+// markDirty makes balances to be saved with next state of chain, must be called under lock
+func (s *Service) markDirty(chain entities.Chain, keys ...balanceKey) {
+	index, ok := s.chains[chain]
+	if !ok {
+		return
+	}
+	for _, key := range keys {
+		index.dirty[key] = struct{}{}
+	}
+}
+
+func (s *Service) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	for _, index := range s.chains {
+		if index.unsubscribe != nil {
+			index.unsubscribe()
+			index.unsubscribe = nil
+		}
+	}
+}
+
+// GetBalance returns indexed balance of holder and block it is indexed at. False is returned until balance is tracked
+// and read at least once, and while chain was not indexed within MaxStaleness.
+func (s *Service) GetBalance(chain entities.Chain, token entities.Token, holder common.Address) (*big.Int, uint64, bool) {
+	tokenAddress, err := entities.GetTokenAddress(chain, token)
+	if err != nil {
+		return nil, 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, ok := s.chains[chain]
+	if !ok {
+		return nil, 0, false
+	}
+	balance, ok := index.balances[balanceKey{token: tokenAddress, holder: holder}]
+	if !ok {
+		return nil, 0, false
+	}
+	s.lru.MoveToFront(balance.element)
+	if balance.value == nil || s.isStale(index) {
+		return nil, 0, false
+	}
+	return new(big.Int).Set(balance.value), balance.block, true
+}
+
+// GetStatus returns indexed head of every followed chain with its age
+func (s *Service) GetStatus() []*entities.IndexerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*entities.IndexerStatus, 0, len(s.chains))
+	for chain, index := range s.chains {
+		status := &entities.IndexerStatus{
+			Chain:     chain,
+			ChainName: chain.String(),
+			Head:      index.cursor,
+			Tracked:   len(index.balances),
+			Stale:     s.isStale(index),
+		}
+		if !index.indexedAt.IsZero() {
+			status.IndexedAt = index.indexedAt.Unix()
+			status.AgeSeconds = int64(time.Since(index.indexedAt).Seconds())
+		}
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Chain < result[j].Chain
+	})
+	return result
+}
+
+// isStale reports whether chain was not indexed within MaxStaleness, must be called under lock
+func (s *Service) isStale(index *chainIndex) bool {
+	return index.cursor == 0 || time.Since(index.indexedAt) > s.conf.MaxStaleness
+}
+
+// Track starts indexing balance of holder. Native coins have no Transfer logs and are not tracked.
+func (s *Service) Track(chain entities.Chain, token entities.Token, holder common.Address) {
+	if !s.conf.Enabled || entities.IsFuel(chain, token) {
+		return
+	}
+	tokenAddress, err := entities.GetTokenAddress(chain, token)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, ok := s.chains[chain]
+	if !ok {
+		index = &chainIndex{balances: make(map[balanceKey]*indexedBalance), dirty: make(map[balanceKey]struct{})}
+		s.chains[chain] = index
+	}
+	key := balanceKey{token: tokenAddress, holder: holder}
+	if balance, exists := index.balances[key]; exists {
+		s.lru.MoveToFront(balance.element)
+		return
+	}
+	index.balances[key] = &indexedBalance{element: s.lru.PushFront(&lruEntry{chain: chain, key: key})}
+	s.markDirty(chain, key)
+	s.evict()
+	s.followChain(chain)
+}
+
+// evict drops least recently queried balances over MaxTracked limit, must be called under lock
+func (s *Service) evict() {
+	for s.lru.Len() > s.conf.MaxTracked {
+		entry := s.lru.Remove(s.lru.Back()).(*lruEntry)
+		delete(s.chains[entry.chain].balances, entry.key)
+		s.markDirty(entry.chain, entry.key)
+	}
+}
+
+// followChain starts indexing on blocks of chain if not started yet, must be called under lock
+func (s *Service) followChain(chain entities.Chain) {
+	index := s.chains[chain]
+	if index.unsubscribe != nil || s.ctx == nil {
+		return
+	}
+	events, unsubscribe := s.follower.Subscribe(chain, 0)
+	index.unsubscribe = unsubscribe
+	go func() {
+		for event := range events {
+			switch event.Type {
+			case follower.BlockAdded:
+				s.indexHead(chain, event.Header)
+			case follower.BlockRemoved, follower.Resync:
+				s.rollback(chain)
+			}
+		}
+		// channel is closed on Stop or when follower dropped slow subscription, in latter case follow again from scratch
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if index.unsubscribe == nil || s.ctx.Err() != nil {
+			return
+		}
+		index.unsubscribe = nil
+		index.cursor = 0
+		s.followChain(chain)
+	}()
+}
+
+// rollback makes all balances of chain to be read again on next block
+func (s *Service) rollback(chain entities.Chain) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chains[chain].cursor = 0
+}
+
+func (s *Service) indexHead(chain entities.Chain, head *types.Header) {
+	to := head.Number.Uint64()
+	s.mu.Lock()
+	index := s.chains[chain]
+	from := index.cursor + 1
+	resync := index.cursor == 0 || to < from || to-index.cursor > s.conf.MaxBlockRange
+	reconcile := time.Since(index.lastReconcile) >= s.conf.ReconcileInterval
+	holders := make(map[common.Address][]common.Address)
+	unsynced := make([]balanceKey, 0)
+	for key, balance := range index.balances {
+		holders[key.token] = append(holders[key.token], key.holder)
+		if balance.value == nil || resync || reconcile {
+			unsynced = append(unsynced, key)
+		}
+	}
+	s.mu.Unlock()
+	if len(holders) == 0 {
+		return
+	}
+
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		s.log.Error("unable to get web3 client", err, zap.String("chain", chain.String()))
+		return
+	}
+	defer client.Close()
+
+	transfers := make([]*approver.Erc20Transfer, 0)
+	if !resync {
+		for token, tokenHolders := range holders {
+			tokenTransfers, errTransfers := s.erc20.GetTransfers(s.ctx, client, token, from, to, tokenHolders)
+			if errTransfers != nil {
+				// cursor is not moved, range is queried again on next head
+				s.log.Error("unable to get transfers", errTransfers, zap.String("chain", chain.String()), zap.String("token", token.String()))
+				return
+			}
+			transfers = append(transfers, tokenTransfers...)
+		}
+	}
+	read := s.readBalances(client, chain, unsynced, to)
+
+	state, removed := s.commit(chain, index, to, resync, reconcile, transfers, read)
+	if err = s.storage.SaveIndexerDelta(state, removed); err != nil {
+		s.log.Error("unable to save indexer state", err, zap.String("chain", chain.String()))
+		// changes are saved with next block
+		s.mu.Lock()
+		for _, balance := range append(state.Balances, removed...) {
+			s.markDirty(chain, balanceKey{token: balance.Token, holder: balance.Holder})
+		}
+		s.mu.Unlock()
+	}
+}
+
+// commit applies transfers and read balances of block to index and returns its changes to be saved
+func (s *Service) commit(
+	chain entities.Chain,
+	index *chainIndex,
+	to uint64,
+	resync, reconcile bool,
+	transfers []*approver.Erc20Transfer,
+	read map[balanceKey]*big.Int,
+) (*storage.IndexerState, []storage.IndexedBalance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resync {
+		// transfers of skipped range are unknown, balances which were not read again are unsynced
+		for key, balance := range index.balances {
+			if balance.value != nil {
+				balance.value = nil
+				s.markDirty(chain, key)
+			}
+		}
+	}
+	s.markDirty(chain, applyTransfers(index.balances, transfers)...)
+	for key, value := range read {
+		balance, ok := index.balances[key]
+		if !ok {
+			continue
+		}
+		if balance.value == nil || balance.value.Cmp(value) != 0 {
+			s.markDirty(chain, key)
+		}
+		if reconcile && balance.value != nil && balance.value.Cmp(value) != 0 {
+			s.log.Info("indexed balance drifted, reconciled",
+				zap.String("chain", chain.String()),
+				zap.String("token", key.token.String()),
+				zap.String("holder", key.holder.String()),
+				zap.String("indexed", balance.value.String()),
+				zap.String("actual", value.String()),
+			)
+		}
+		balance.value, balance.block = value, to
+	}
+	for _, balance := range index.balances {
+		if balance.value != nil && balance.block < to {
+			balance.block = to
+		}
+	}
+	index.cursor, index.indexedAt = to, time.Now()
+	if reconcile {
+		index.lastReconcile = time.Now()
+	}
+	return s.export(chain, index)
+}
+
+// readBalances reads balances at block, failed reads are skipped and retried on next head
+func (s *Service) readBalances(client *ethclient.Client, chain entities.Chain, keys []balanceKey, block uint64) map[balanceKey]*big.Int {
+	result := make(map[balanceKey]*big.Int, len(keys))
+	for _, key := range keys {
+		value, err := s.erc20.GetERC20TokenBalanceAt(s.ctx, client, key.token, key.holder, block)
+		if err != nil {
+			s.log.Error("unable to read indexed balance", err, zap.String("chain", chain.String()), zap.String("holder", key.holder.String()))
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// applyTransfers moves transfer values between indexed balances. Transfers at or before block balance was read at
+// are already part of it and are skipped. Balance which would become negative is marked unsynced to be read again.
+// Returns keys of changed balances.
+func applyTransfers(balances map[balanceKey]*indexedBalance, transfers []*approver.Erc20Transfer) []balanceKey {
+	changed := make([]balanceKey, 0)
+	for _, transfer := range transfers {
+		apply := func(holder common.Address, delta *big.Int) {
+			key := balanceKey{token: transfer.Raw.Address, holder: holder}
+			balance, ok := balances[key]
+			if !ok || balance.value == nil || transfer.Raw.BlockNumber <= balance.block {
+				return
+			}
+			changed = append(changed, key)
+			balance.value = new(big.Int).Add(balance.value, delta)
+			if balance.value.Sign() < 0 {
+				balance.value = nil
+			}
+		}
+		apply(transfer.From, new(big.Int).Neg(transfer.Value))
+		apply(transfer.To, transfer.Value)
+	}
+	return changed
+}
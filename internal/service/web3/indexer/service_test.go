@@ -0,0 +1,160 @@
+package indexer
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/web3/approver"
+	"altt/internal/storage"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	token = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	alice = common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	bob   = common.HexToAddress("0xDf8ac28156209F5cbc89cDec419dd3dB3D3E326a")
+)
+
+func TestApplyTransfers(t *testing.T) {
+	// given alice balance read at block 10 and bob balance not read yet
+	balances := map[balanceKey]*indexedBalance{
+		{token: token, holder: alice}: {value: big.NewInt(100), block: 10},
+		{token: token, holder: bob}:   {},
+	}
+
+	// when
+	changed := applyTransfers(balances, []*approver.Erc20Transfer{
+		transfer(alice, bob, 50, 10), // already part of balance read at block 10
+		transfer(alice, bob, 30, 11),
+		transfer(bob, alice, 5, 12),
+	})
+
+	// then
+	require.Equal(t, []balanceKey{{token: token, holder: alice}, {token: token, holder: alice}}, changed)
+	require.Equal(t, big.NewInt(75), balances[balanceKey{token: token, holder: alice}].value)
+	require.Nil(t, balances[balanceKey{token: token, holder: bob}].value)
+
+	// balance which would become negative is unsynced
+	applyTransfers(balances, []*approver.Erc20Transfer{transfer(alice, bob, 100, 13)})
+	require.Nil(t, balances[balanceKey{token: token, holder: alice}].value)
+}
+
+func TestService_Track(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+
+	t.Run("disabled indexer does not track", func(t *testing.T) {
+		srv := NewService(appLog, nil, nil, nil, config.IndexerConfig{})
+		srv.Track(entities.ChainEthereum, entities.USDC, alice)
+		require.Empty(t, srv.chains)
+	})
+
+	t.Run("balance is served after it is read", func(t *testing.T) {
+		srv := NewService(appLog, nil, nil, nil, config.IndexerConfig{Enabled: true})
+		srv.Track(entities.ChainEthereum, entities.USDC, alice)
+		srv.Track(entities.ChainEthereum, entities.ETH, alice) // native coins have no transfer logs
+		_, _, ok := srv.GetBalance(entities.ChainEthereum, entities.USDC, alice)
+		require.False(t, ok)
+		require.Len(t, srv.chains[entities.ChainEthereum].balances, 1)
+
+		index := srv.chains[entities.ChainEthereum]
+		index.balances[balanceKey{token: token, holder: alice}].value = big.NewInt(42)
+		index.balances[balanceKey{token: token, holder: alice}].block = 100
+		index.cursor, index.indexedAt = 100, time.Now()
+		value, block, ok := srv.GetBalance(entities.ChainEthereum, entities.USDC, alice)
+		require.True(t, ok)
+		require.Equal(t, big.NewInt(42), value)
+		require.Equal(t, uint64(100), block)
+	})
+
+	t.Run("balance of stale chain is not served", func(t *testing.T) {
+		srv := NewService(appLog, nil, nil, nil, config.IndexerConfig{Enabled: true, MaxStaleness: time.Minute})
+		srv.Track(entities.ChainEthereum, entities.USDC, alice)
+		index := srv.chains[entities.ChainEthereum]
+		index.balances[balanceKey{token: token, holder: alice}].value = big.NewInt(42)
+		index.cursor, index.indexedAt = 100, time.Now().Add(-2*time.Minute)
+
+		_, _, ok := srv.GetBalance(entities.ChainEthereum, entities.USDC, alice)
+		require.False(t, ok)
+		status := srv.GetStatus()
+		require.Len(t, status, 1)
+		require.True(t, status[0].Stale)
+		require.Equal(t, uint64(100), status[0].Head)
+		require.GreaterOrEqual(t, status[0].AgeSeconds, int64(120))
+	})
+
+	t.Run("least recently queried balance is evicted", func(t *testing.T) {
+		srv := NewService(appLog, nil, nil, nil, config.IndexerConfig{Enabled: true, MaxTracked: 2})
+		srv.Track(entities.ChainEthereum, entities.USDC, alice)
+		srv.Track(entities.ChainEthereum, entities.USDC, bob)
+		srv.GetBalance(entities.ChainEthereum, entities.USDC, alice)
+		srv.Track(entities.ChainPolygon, entities.USDC, bob)
+		require.Len(t, srv.chains[entities.ChainEthereum].balances, 1)
+		require.Contains(t, srv.chains[entities.ChainEthereum].balances, balanceKey{token: token, holder: alice})
+		require.Len(t, srv.chains[entities.ChainPolygon].balances, 1)
+		require.Equal(t, 2, srv.lru.Len())
+		_, removed := srv.export(entities.ChainEthereum, srv.chains[entities.ChainEthereum])
+		require.Equal(t, []storage.IndexedBalance{{Token: token, Holder: bob}}, removed)
+	})
+}
+
+func TestService_Restore(t *testing.T) {
+	// given indexer state saved to storage
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	store, err := storage.Open("")
+	require.NoError(t, err)
+	repository := storage.NewRepository(store)
+	conf := config.IndexerConfig{Enabled: true}
+
+	srv := NewService(appLog, nil, nil, repository, conf)
+	srv.Track(entities.ChainEthereum, entities.USDC, alice)
+	srv.Track(entities.ChainEthereum, entities.USDC, bob)
+	index := srv.chains[entities.ChainEthereum]
+	index.cursor, index.indexedAt = 100, time.Now()
+	index.balances[balanceKey{token: token, holder: alice}].value = big.NewInt(42)
+	index.balances[balanceKey{token: token, holder: alice}].block = 100
+	require.NoError(t, repository.SaveIndexerDelta(srv.export(entities.ChainEthereum, index)))
+
+	// and next block which changed no balance saves cursor only
+	index.cursor = 101
+	state, removed := srv.export(entities.ChainEthereum, index)
+	require.Empty(t, state.Balances)
+	require.Empty(t, removed)
+	require.NoError(t, repository.SaveIndexerDelta(state, removed))
+
+	// when state is loaded after restart
+	restarted := NewService(appLog, nil, nil, repository, conf)
+	states, err := repository.LoadIndexerStates()
+	require.NoError(t, err)
+	for _, state := range states {
+		restarted.restore(state)
+	}
+
+	// then
+	restored := restarted.chains[entities.ChainEthereum]
+	require.Equal(t, uint64(101), restored.cursor)
+	require.Len(t, restored.balances, 2)
+	require.Equal(t, 2, restarted.lru.Len())
+	value, block, ok := restarted.GetBalance(entities.ChainEthereum, entities.USDC, alice)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(42), value)
+	require.Equal(t, uint64(101), block)
+	_, _, ok = restarted.GetBalance(entities.ChainEthereum, entities.USDC, bob)
+	require.False(t, ok)
+}
+
+func transfer(from, to common.Address, value int64, block uint64) *approver.Erc20Transfer {
+	return &approver.Erc20Transfer{
+		From:  from,
+		To:    to,
+		Value: big.NewInt(value),
+		Raw:   types.Log{Address: token, BlockNumber: block},
+	}
+}
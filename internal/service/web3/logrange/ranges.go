@@ -0,0 +1,211 @@
+package logrange
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+const (
+	// rejectionTTL is time after which smallest rejected range of chain is forgotten, so range can recover after
+	// provider is rotated or its limits are raised
+	rejectionTTL = time.Hour
+	// rateLimitRetries is number of retries of chunk rejected by provider rate limit
+	rateLimitRetries = 4
+	rateLimitBackoff = 500 * time.Millisecond
+)
+
+var (
+	ErrRangeLimitUnhandled = errors.New("provider rejects even single block range")
+	// ErrStopWalk is returned by fetch to end walk early without error
+	ErrStopWalk = errors.New("walk stopped")
+)
+
+// limitMessages are parts of provider errors for too wide eth_getLogs ranges or too big responses
+var limitMessages = []string{
+	"block range",
+	"range limit",
+	"range is too",
+	"range too large",
+	"is limited to",
+	"too many blocks",
+	"query returned more than",
+	"response size",
+	"results exceed",
+}
+
+// rateLimitMessages are parts of provider errors for exceeded request rate or credits. Providers report both rate limits
+// and rejected ranges with code -32005, so errors are told apart by message.
+var rateLimitMessages = []string{
+	"rate limit",
+	"too many requests",
+	"request limit",
+	"request count",
+	"request rate",
+	"capacity",
+	"credits",
+	"throughput",
+}
+
+// Ranges keeps eth_getLogs block range of every chain adapted to limits of providers. Range is halved when provider
+// rejects it and doubles after success, but stays below smallest range rejected on the chain during last rejectionTTL.
+type Ranges struct {
+	log     logger.AppLogger
+	initial uint64
+	max     uint64
+	backoff time.Duration
+
+	mu       sync.Mutex
+	ranges   map[entities.Chain]uint64
+	rejected map[entities.Chain]rejection
+}
+
+type rejection struct {
+	size uint64
+	at   time.Time
+}
+
+func New(log logger.AppLogger, initial, max uint64) *Ranges {
+	if initial > max {
+		initial = max
+	}
+	return &Ranges{
+		log:      log,
+		initial:  initial,
+		max:      max,
+		backoff:  rateLimitBackoff,
+		ranges:   make(map[entities.Chain]uint64),
+		rejected: make(map[entities.Chain]rejection),
+	}
+}
+
+// Get returns current range of chain
+func (r *Ranges) Get(chain entities.Chain) uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if blockRange, ok := r.ranges[chain]; ok {
+		return blockRange
+	}
+	return r.initial
+}
+
+// Shrink halves range of chain after provider rejected range of given size
+func (r *Ranges) Shrink(chain entities.Chain, rejected uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if current, ok := r.rejected[chain]; !ok || rejected <= current.size || time.Since(current.at) > rejectionTTL {
+		r.rejected[chain] = rejection{size: rejected, at: time.Now()}
+	}
+	r.ranges[chain] = rejected / 2
+	r.log.Info("eth_getLogs range rejected, shrink range", zap.String("chain", chain.String()), zap.Uint64("range", rejected/2))
+}
+
+// Grow doubles range of chain after accepted range, but keeps it below smallest recently rejected range
+func (r *Ranges) Grow(chain entities.Chain, accepted uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	next := accepted * 2
+	if next > r.max {
+		next = r.max
+	}
+	if rejected, ok := r.rejected[chain]; ok {
+		if time.Since(rejected.at) > rejectionTTL {
+			delete(r.rejected, chain)
+		} else if next >= rejected.size {
+			next = rejected.size - 1
+		}
+	}
+	if next > r.ranges[chain] {
+		r.ranges[chain] = next
+	}
+}
+
+// Walk calls fetch for consecutive chunks of blocks from `to` down to `from`. Chunk rejected for its range is retried
+// with smaller range, chunk rejected by rate limit is retried with the same range after backoff, other errors stop the walk.
+// Fetch returns ErrStopWalk to stop after its chunk.
+func (r *Ranges) Walk(ctx context.Context, chain entities.Chain, from, to uint64, fetch func(ctx context.Context, start, end uint64) error) error {
+	end := to
+	retries := 0
+	for end >= from {
+		start := from
+		if blockRange := r.Get(chain); end-from+1 > blockRange {
+			start = end - blockRange + 1
+		}
+		if err := fetch(ctx, start, end); err != nil {
+			if errors.Is(err, ErrStopWalk) {
+				r.Grow(chain, end-start+1)
+				return nil
+			}
+			if IsRateLimitError(err) && retries < rateLimitRetries {
+				if errWait := r.wait(ctx, retries); errWait != nil {
+					return errWait
+				}
+				retries++
+				continue
+			}
+			if !IsLimitError(err) {
+				return err
+			}
+			if end == start {
+				return fmt.Errorf("%w: %s", ErrRangeLimitUnhandled, err)
+			}
+			r.Shrink(chain, end-start+1)
+			continue
+		}
+		retries = 0
+		r.Grow(chain, end-start+1)
+		if start == 0 {
+			break
+		}
+		end = start - 1
+	}
+	return nil
+}
+
+// wait sleeps before retry after rate limit, doubling backoff with every attempt
+func (r *Ranges) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(r.backoff << attempt)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// IsLimitError reports whether provider rejected eth_getLogs because of range or response size. Rate limits are not
+// limit errors, even when provider reports them with the same code.
+func IsLimitError(err error) bool {
+	if IsRateLimitError(err) {
+		return false
+	}
+	return containsAny(strings.ToLower(err.Error()), limitMessages)
+}
+
+// IsRateLimitError reports whether provider rejected request because of request rate or used credits
+func IsRateLimitError(err error) bool {
+	var httpErr gethrpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return containsAny(strings.ToLower(err.Error()), rateLimitMessages)
+}
+
+func containsAny(message string, parts []string) bool {
+	for _, part := range parts {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+	return false
+}
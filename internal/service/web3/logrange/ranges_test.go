@@ -0,0 +1,161 @@
+package logrange
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+const limitExceededCode = -32005
+
+type rpcError struct {
+	code    int
+	message string
+}
+
+func (e rpcError) Error() string  { return e.message }
+func (e rpcError) ErrorCode() int { return e.code }
+
+func TestRanges_Walk(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+
+	t.Run("range adapts to provider limit", func(t *testing.T) {
+		// given provider which accepts at most 30 blocks
+		ranges := New(appLog, 100, 1000)
+		covered := make(map[uint64]int)
+
+		// when
+		err := ranges.Walk(context.Background(), entities.ChainEthereum, 0, 999, func(ctx context.Context, start, end uint64) error {
+			if end-start+1 > 30 {
+				return errors.New("eth_getLogs block range is too wide")
+			}
+			for block := start; block <= end; block++ {
+				covered[block]++
+			}
+			return nil
+		})
+
+		// then every block is fetched exactly once and range stays below rejected one
+		require.NoError(t, err)
+		require.Len(t, covered, 1000)
+		for _, count := range covered {
+			require.Equal(t, 1, count)
+		}
+		require.Less(t, ranges.Get(entities.ChainEthereum), ranges.rejected[entities.ChainEthereum].size)
+		require.LessOrEqual(t, ranges.rejected[entities.ChainEthereum].size, uint64(60))
+	})
+
+	t.Run("range grows up to max", func(t *testing.T) {
+		ranges := New(appLog, 10, 80)
+		require.NoError(t, ranges.Walk(context.Background(), entities.ChainEthereum, 0, 999, func(ctx context.Context, start, end uint64) error {
+			return nil
+		}))
+		require.Equal(t, uint64(80), ranges.Get(entities.ChainEthereum))
+		require.Equal(t, uint64(10), ranges.Get(entities.ChainPolygon))
+	})
+
+	t.Run("other errors stop walk", func(t *testing.T) {
+		ranges := New(appLog, 100, 1000)
+		err := ranges.Walk(context.Background(), entities.ChainEthereum, 0, 999, func(ctx context.Context, start, end uint64) error {
+			return errors.New("connection refused")
+		})
+		require.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("fetch stops walk", func(t *testing.T) {
+		ranges := New(appLog, 100, 1000)
+		calls := 0
+		err := ranges.Walk(context.Background(), entities.ChainEthereum, 0, 999, func(ctx context.Context, start, end uint64) error {
+			calls++
+			return ErrStopWalk
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("single block rejected", func(t *testing.T) {
+		ranges := New(appLog, 4, 1000)
+		err := ranges.Walk(context.Background(), entities.ChainEthereum, 0, 999, func(ctx context.Context, start, end uint64) error {
+			return rpcError{code: limitExceededCode, message: "query returned more than 10000 results"}
+		})
+		require.ErrorIs(t, err, ErrRangeLimitUnhandled)
+	})
+
+	t.Run("rate limited chunk is retried without shrinking range", func(t *testing.T) {
+		// given provider which rate limits first two requests
+		ranges := New(appLog, 100, 100)
+		ranges.backoff = time.Millisecond
+		calls := 0
+
+		// when
+		err := ranges.Walk(context.Background(), entities.ChainEthereum, 0, 199, func(ctx context.Context, start, end uint64) error {
+			calls++
+			if calls <= 2 {
+				return rpcError{code: limitExceededCode, message: "daily request count exceeded, request rate limited"}
+			}
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 4, calls)
+		require.Equal(t, uint64(100), ranges.Get(entities.ChainEthereum))
+		require.Empty(t, ranges.rejected)
+	})
+
+	t.Run("rate limit is returned after retries", func(t *testing.T) {
+		ranges := New(appLog, 100, 100)
+		ranges.backoff = time.Millisecond
+		err := ranges.Walk(context.Background(), entities.ChainEthereum, 0, 199, func(ctx context.Context, start, end uint64) error {
+			return gethrpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"}
+		})
+		require.True(t, IsRateLimitError(err))
+	})
+}
+
+func TestRanges_Grow(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+
+	// given range rejected long ago
+	ranges := New(appLog, 100, 1000)
+	ranges.Shrink(entities.ChainEthereum, 100)
+	ranges.Grow(entities.ChainEthereum, 50)
+	require.Equal(t, uint64(99), ranges.Get(entities.ChainEthereum))
+	ranges.rejected[entities.ChainEthereum] = rejection{size: 100, at: time.Now().Add(-rejectionTTL - time.Minute)}
+
+	// when
+	ranges.Grow(entities.ChainEthereum, 99)
+
+	// then learned limit recovers
+	require.Equal(t, uint64(198), ranges.Get(entities.ChainEthereum))
+	require.Empty(t, ranges.rejected)
+}
+
+func TestIsLimitError(t *testing.T) {
+	require.True(t, IsLimitError(rpcError{code: limitExceededCode, message: "query returned more than 10000 results"}))
+	require.True(t, IsLimitError(fmt.Errorf("wrapped: %w", rpcError{code: -32000, message: "exceed maximum block range: 5000"})))
+	require.True(t, IsLimitError(errors.New("Log response size exceeded")))
+	require.True(t, IsLimitError(errors.New("eth_getLogs is limited to a 10,000 range")))
+	require.False(t, IsLimitError(rpcError{code: limitExceededCode, message: "daily request count exceeded, request rate limited"}))
+	require.False(t, IsLimitError(errors.New("too many requests")))
+	require.False(t, IsLimitError(errors.New("exceeded its compute units per second capacity")))
+	require.False(t, IsLimitError(rpcError{code: -32000, message: "rpc error"}))
+	require.False(t, IsLimitError(errors.New("connection refused")))
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	require.True(t, IsRateLimitError(gethrpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429"}))
+	require.True(t, IsRateLimitError(rpcError{code: -32005, message: "project ID request rate exceeded"}))
+	require.False(t, IsRateLimitError(rpcError{code: -32005, message: "query returned more than 10000 results"}))
+	require.False(t, IsRateLimitError(errors.New("connection refused")))
+}
@@ -0,0 +1,444 @@
+package pricing
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/service/web3/approver"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	// spot sources are read from current pool state, which can be moved within single block
+	SourceUniswapV2Spot = "uniswap_v2_spot"
+	SourceUniswapV2TWAP = "uniswap_v2_twap"
+	SourceUniswapV3Spot = "uniswap_v3_spot"
+	SourceUniswapV3TWAP = "uniswap_v3_twap"
+
+	PoolTypeUniswapV2 = "uniswap_v2"
+	PoolTypeUniswapV3 = "uniswap_v3"
+
+	defaultMinLiquidityUSD = 10_000
+	// maxQuoteDepth limits routing through pools whose quote asset is priced by other pool
+	maxQuoteDepth  = 2
+	floatPrecision = 256
+	// blockTimeSample is number of blocks used to estimate block time when block window ago is searched
+	blockTimeSample = 100
+	// uq112 is resolution of v2 cumulative prices
+	uq112 = 112
+)
+
+var (
+	ErrThinPool     = errors.New("pool liquidity below floor")
+	ErrPoolMismatch = errors.New("pool does not contain token")
+	ErrUnknownPool  = errors.New("unknown pool type")
+	// ErrInvalidTWAPWindow is window shorter than a second, observations of v3 pools are indexed by seconds
+	ErrInvalidTWAPWindow = errors.New("twap window must be at least 1s")
+)
+
+type dexPool struct {
+	chain   entities.Chain
+	token   entities.Token
+	quote   entities.Token
+	kind    string
+	address common.Address
+}
+
+func parseDEXPools(pools []config.DEXPoolConfig) (map[entities.Token][]dexPool, error) {
+	result := make(map[entities.Token][]dexPool, len(pools))
+	for _, pool := range pools {
+		chain, err := entities.ChainFromString(pool.Chain)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse pool chain %s: %w", pool.Chain, err)
+		}
+		token, err := entities.TokenFromString(pool.Token)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse pool token %s: %w", pool.Token, err)
+		}
+		quote, err := entities.TokenFromString(pool.Quote)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse pool quote %s: %w", pool.Quote, err)
+		}
+		if pool.Type != PoolTypeUniswapV2 && pool.Type != PoolTypeUniswapV3 {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPool, pool.Type)
+		}
+		if pool.PoolAddress == (common.Address{}) {
+			return nil, fmt.Errorf("pool address is not set: %s", pool.Pool)
+		}
+		result[token] = append(result[token], dexPool{
+			chain:   chain,
+			token:   token,
+			quote:   quote,
+			kind:    pool.Type,
+			address: pool.PoolAddress,
+		})
+	}
+	return result, nil
+}
+
+// getPools returns pools of token, pools on requested chain go first
+func (s *Service) getPools(chain entities.Chain, token entities.Token) []dexPool {
+	pools := append([]dexPool(nil), s.pools[token]...)
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].chain == chain && pools[j].chain != chain
+	})
+	return pools
+}
+
+// poolSides is pool state from perspective of priced token
+type poolSides struct {
+	baseIsToken0  bool
+	baseDecimals  int
+	quoteAddress  common.Address
+	quoteDecimals int
+}
+
+func (s *Service) readPool(ctx context.Context, client *ethclient.Client, pool dexPool, quoteUSD decimal.Decimal) (*entities.Price, error) {
+	baseAddress, err := entities.GetTokenAddress(pool.chain, pool.token)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get token address: %w", err)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	switch pool.kind {
+	case PoolTypeUniswapV2:
+		return s.readV2Pool(ctx, opts, client, pool, baseAddress, quoteUSD)
+	case PoolTypeUniswapV3:
+		return s.readV3Pool(opts, client, pool, baseAddress, quoteUSD)
+	}
+	return nil, ErrUnknownPool
+}
+
+func (s *Service) readV2Pool(
+	ctx context.Context,
+	opts *bind.CallOpts,
+	client *ethclient.Client,
+	pool dexPool,
+	baseAddress common.Address,
+	quoteUSD decimal.Decimal,
+) (*entities.Price, error) {
+	pair, err := NewUniswapV2PairCaller(pool.address, client)
+	if err != nil {
+		return nil, err
+	}
+	sides, err := s.resolveSides(opts, client, pool.token, baseAddress, pair.Token0, pair.Token1)
+	if err != nil {
+		return nil, err
+	}
+	reserves, err := pair.GetReserves(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pair reserves: %w", err)
+	}
+	baseReserve, quoteReserve := reserves.Reserve0, reserves.Reserve1
+	if !sides.baseIsToken0 {
+		baseReserve, quoteReserve = quoteReserve, baseReserve
+	}
+	if err = s.checkLiquidity(quoteReserve, sides.quoteDecimals, quoteUSD); err != nil {
+		return nil, err
+	}
+	if s.twapWindow > 0 {
+		price, errTWAP := s.readV2TWAP(ctx, client, pair, sides.baseIsToken0)
+		if errTWAP == nil {
+			return s.dexPrice(pool.token, scaleDecimals(price, sides.baseDecimals-sides.quoteDecimals), quoteUSD, SourceUniswapV2TWAP)
+		}
+		s.log.Info("unable to read pair twap, using spot price",
+			zap.String("pool", pool.address.String()),
+			zap.String("reason", errTWAP.Error()),
+		)
+	}
+	return s.dexPrice(pool.token, reservesPrice(baseReserve, quoteReserve, sides.baseDecimals, sides.quoteDecimals), quoteUSD, SourceUniswapV2Spot)
+}
+
+// readV2TWAP returns raw average price of base token over configured window from cumulative prices of pair at latest
+// block and at block window ago. Reading old state needs archive node when window is longer than state kept by node.
+func (s *Service) readV2TWAP(ctx context.Context, client *ethclient.Client, pair *UniswapV2PairCaller, baseIsToken0 bool) (*big.Float, error) {
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get latest header: %w", err)
+	}
+	start, err := blockBefore(ctx, client, latest, s.twapWindow)
+	if err != nil {
+		return nil, err
+	}
+	begin, err := readV2Observation(ctx, pair, start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := readV2Observation(ctx, pair, latest)
+	if err != nil {
+		return nil, err
+	}
+	if end.timestamp <= begin.timestamp {
+		return nil, fmt.Errorf("invalid twap window: %d..%d", begin.timestamp, end.timestamp)
+	}
+	if baseIsToken0 {
+		return averagePrice(begin.cumulative0, end.cumulative0, end.timestamp-begin.timestamp), nil
+	}
+	return averagePrice(begin.cumulative1, end.cumulative1, end.timestamp-begin.timestamp), nil
+}
+
+// v2Observation is cumulative prices of pair extended to block timestamp
+type v2Observation struct {
+	timestamp   uint64
+	cumulative0 *big.Int
+	cumulative1 *big.Int
+}
+
+func readV2Observation(ctx context.Context, pair *UniswapV2PairCaller, header *types.Header) (*v2Observation, error) {
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: header.Number}
+	cumulative0, err := pair.Price0CumulativeLast(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get price0 cumulative at block %d: %w", header.Number.Uint64(), err)
+	}
+	cumulative1, err := pair.Price1CumulativeLast(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get price1 cumulative at block %d: %w", header.Number.Uint64(), err)
+	}
+	reserves, err := pair.GetReserves(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pair reserves at block %d: %w", header.Number.Uint64(), err)
+	}
+	cumulative0, cumulative1 = cumulativeAt(cumulative0, cumulative1, reserves.Reserve0, reserves.Reserve1, reserves.BlockTimestampLast, header.Time)
+	return &v2Observation{timestamp: header.Time, cumulative0: cumulative0, cumulative1: cumulative1}, nil
+}
+
+// blockBefore returns header of block produced about window before latest, block time is estimated from recent blocks
+func blockBefore(ctx context.Context, client *ethclient.Client, latest *types.Header, window time.Duration) (*types.Header, error) {
+	if latest.Number.Uint64() <= blockTimeSample {
+		return nil, fmt.Errorf("chain is too short for twap: %d blocks", latest.Number.Uint64())
+	}
+	sample, err := client.HeaderByNumber(ctx, new(big.Int).Sub(latest.Number, big.NewInt(blockTimeSample)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get sample header: %w", err)
+	}
+	blockTime := float64(latest.Time-sample.Time) / blockTimeSample
+	if blockTime <= 0 {
+		blockTime = 1
+	}
+	blocks := uint64(math.Ceil(window.Seconds() / blockTime))
+	if blocks >= latest.Number.Uint64() {
+		return nil, fmt.Errorf("chain is too short for twap: %d blocks", latest.Number.Uint64())
+	}
+	header, err := client.HeaderByNumber(ctx, new(big.Int).Sub(latest.Number, new(big.Int).SetUint64(blocks)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get header window ago: %w", err)
+	}
+	return header, nil
+}
+
+func (s *Service) readV3Pool(opts *bind.CallOpts, client *ethclient.Client, pool dexPool, baseAddress common.Address, quoteUSD decimal.Decimal) (*entities.Price, error) {
+	v3Pool, err := NewUniswapV3PoolCaller(pool.address, client)
+	if err != nil {
+		return nil, err
+	}
+	sides, err := s.resolveSides(opts, client, pool.token, baseAddress, v3Pool.Token0, v3Pool.Token1)
+	if err != nil {
+		return nil, err
+	}
+	quoteToken, err := approver.NewErc20Caller(sides.quoteAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	quoteBalance, err := quoteToken.BalanceOf(opts, pool.address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pool quote balance: %w", err)
+	}
+	if err = s.checkLiquidity(quoteBalance, sides.quoteDecimals, quoteUSD); err != nil {
+		return nil, err
+	}
+	decimals0, decimals1 := sides.baseDecimals, sides.quoteDecimals
+	if !sides.baseIsToken0 {
+		decimals0, decimals1 = decimals1, decimals0
+	}
+	price, source, err := s.readV3Price(opts, v3Pool, decimals0, decimals1)
+	if err != nil {
+		return nil, err
+	}
+	if !sides.baseIsToken0 {
+		if price.Sign() == 0 {
+			return nil, ErrInvalidData
+		}
+		price = new(big.Float).SetPrec(floatPrecision).Quo(big.NewFloat(1), price)
+	}
+	return s.dexPrice(pool.token, price, quoteUSD, source)
+}
+
+// readV3Price returns price of token0 in token1. TWAP over configured window is preferred,
+// spot price is used when pool has not enough observations for the window.
+func (s *Service) readV3Price(opts *bind.CallOpts, pool *UniswapV3PoolCaller, decimals0, decimals1 int) (*big.Float, string, error) {
+	if s.twapWindow > 0 {
+		window := uint32(s.twapWindow / time.Second)
+		if window == 0 {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidTWAPWindow, s.twapWindow)
+		}
+		observation, errObserve := pool.Observe(opts, []uint32{window, 0})
+		if errObserve == nil && len(observation.TickCumulatives) != 2 {
+			errObserve = fmt.Errorf("unexpected number of observations: %d", len(observation.TickCumulatives))
+		}
+		if errObserve == nil {
+			tick, errTick := averageTick(observation.TickCumulatives[0], observation.TickCumulatives[1], window)
+			if errTick != nil {
+				return nil, "", errTick
+			}
+			return tickPrice(tick, decimals0, decimals1), SourceUniswapV3TWAP, nil
+		}
+		s.log.Info("unable to observe pool, using spot price", zap.String("reason", errObserve.Error()))
+	}
+	slot0, err := pool.Slot0(opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to get pool slot0: %w", err)
+	}
+	return sqrtPrice(slot0.SqrtPriceX96, decimals0, decimals1), SourceUniswapV3Spot, nil
+}
+
+func (s *Service) resolveSides(
+	opts *bind.CallOpts,
+	client *ethclient.Client,
+	token entities.Token,
+	baseAddress common.Address,
+	token0, token1 func(opts *bind.CallOpts) (common.Address, error),
+) (*poolSides, error) {
+	address0, err := token0(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pool token0: %w", err)
+	}
+	address1, err := token1(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pool token1: %w", err)
+	}
+	sides := &poolSides{baseDecimals: entities.GetTokenDecimals(token)}
+	switch baseAddress {
+	case address0:
+		sides.baseIsToken0, sides.quoteAddress = true, address1
+	case address1:
+		sides.quoteAddress = address0
+	default:
+		return nil, ErrPoolMismatch
+	}
+	quoteToken, err := approver.NewErc20Caller(sides.quoteAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	quoteDecimals, err := quoteToken.Decimals(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get quote decimals: %w", err)
+	}
+	sides.quoteDecimals = int(quoteDecimals)
+	return sides, nil
+}
+
+// checkLiquidity estimates pool liquidity as twice the value of its quote side
+func (s *Service) checkLiquidity(quoteAmount *big.Int, quoteDecimals int, quoteUSD decimal.Decimal) error {
+	liquidity := decimal.NewFromBigInt(quoteAmount, -int32(quoteDecimals)).Mul(quoteUSD).Mul(decimal.NewFromInt(2))
+	if liquidity.LessThan(decimal.NewFromFloat(s.minLiquidityUSD)) {
+		return fmt.Errorf("%w: %s USD", ErrThinPool, liquidity.StringFixed(2))
+	}
+	return nil
+}
+
+func (s *Service) dexPrice(token entities.Token, priceInQuote *big.Float, quoteUSD decimal.Decimal, source string) (*entities.Price, error) {
+	price, err := decimal.NewFromString(priceInQuote.Text('g', 20))
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert pool price: %w", err)
+	}
+	usdPrice := price.Mul(quoteUSD).Round(18)
+	if usdPrice.Sign() <= 0 {
+		return nil, ErrInvalidData
+	}
+	return &entities.Price{
+		Token:     token,
+		USDPrice:  usdPrice.String(),
+		UpdatedAt: time.Now().Unix(),
+		Source:    source,
+	}, nil
+}
+
+// reservesPrice returns price of base token in quote token from v2 pair reserves
+func reservesPrice(baseReserve, quoteReserve *big.Int, baseDecimals, quoteDecimals int) *big.Float {
+	if baseReserve.Sign() == 0 {
+		return new(big.Float)
+	}
+	price := new(big.Float).SetPrec(floatPrecision).SetInt(quoteReserve)
+	price.Quo(price, new(big.Float).SetPrec(floatPrecision).SetInt(baseReserve))
+	return scaleDecimals(price, baseDecimals-quoteDecimals)
+}
+
+// cumulativeAt extends cumulative prices of v2 pair from its last update to timestamp with current reserves,
+// as UniswapV2OracleLibrary.currentCumulativePrices does. Timestamps are uint32 and wrap like in pair contract.
+func cumulativeAt(cumulative0, cumulative1, reserve0, reserve1 *big.Int, lastUpdate uint32, timestamp uint64) (*big.Int, *big.Int) {
+	elapsed := uint32(timestamp) - lastUpdate
+	if elapsed == 0 || reserve0.Sign() == 0 || reserve1.Sign() == 0 {
+		return cumulative0, cumulative1
+	}
+	seconds := new(big.Int).SetUint64(uint64(elapsed))
+	price0 := new(big.Int).Div(new(big.Int).Lsh(reserve1, uq112), reserve0)
+	price1 := new(big.Int).Div(new(big.Int).Lsh(reserve0, uq112), reserve1)
+	return new(big.Int).Add(cumulative0, price0.Mul(price0, seconds)), new(big.Int).Add(cumulative1, price1.Mul(price1, seconds))
+}
+
+// averagePrice returns raw price from UQ112x112 cumulative prices taken elapsed seconds apart, cumulative prices
+// overflow uint256 in pair contract, so difference is taken modulo 2^256
+func averagePrice(start, end *big.Int, elapsed uint64) *big.Float {
+	delta := new(big.Int).Sub(end, start)
+	delta.Mod(delta, new(big.Int).Lsh(big.NewInt(1), 256))
+	price := new(big.Float).SetPrec(floatPrecision).SetInt(delta)
+	price.Quo(price, new(big.Float).SetPrec(floatPrecision).SetInt(new(big.Int).Lsh(new(big.Int).SetUint64(elapsed), uq112)))
+	return price
+}
+
+// sqrtPrice returns price of token0 in token1 from v3 sqrtPriceX96
+func sqrtPrice(sqrtPriceX96 *big.Int, decimals0, decimals1 int) *big.Float {
+	ratio := new(big.Float).SetPrec(floatPrecision).SetInt(sqrtPriceX96)
+	ratio.Quo(ratio, new(big.Float).SetPrec(floatPrecision).SetInt(new(big.Int).Lsh(big.NewInt(1), 96)))
+	ratio.Mul(ratio, ratio)
+	return scaleDecimals(ratio, decimals0-decimals1)
+}
+
+// tickPrice returns price of token0 in token1 for v3 tick, price = 1.0001^tick
+func tickPrice(tick int64, decimals0, decimals1 int) *big.Float {
+	price := new(big.Float).SetPrec(floatPrecision).SetFloat64(math.Pow(1.0001, float64(tick)))
+	return scaleDecimals(price, decimals0-decimals1)
+}
+
+// averageTick returns arithmetic mean tick between two observations, rounded to negative infinity as uniswap OracleLibrary does
+func averageTick(tickCumulativeStart, tickCumulativeEnd *big.Int, window uint32) (int64, error) {
+	if window == 0 {
+		return 0, ErrInvalidTWAPWindow
+	}
+	delta := new(big.Int).Sub(tickCumulativeEnd, tickCumulativeStart)
+	seconds := big.NewInt(int64(window))
+	tick, remainder := new(big.Int).QuoRem(delta, seconds, new(big.Int))
+	if delta.Sign() < 0 && remainder.Sign() != 0 {
+		tick.Sub(tick, big.NewInt(1))
+	}
+	return tick.Int64(), nil
+}
+
+func scaleDecimals(value *big.Float, exp int) *big.Float {
+	if exp == 0 {
+		return value
+	}
+	scale := new(big.Float).SetPrec(floatPrecision).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt(exp))), nil))
+	if exp > 0 {
+		return value.Mul(value, scale)
+	}
+	return value.Quo(value, scale)
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
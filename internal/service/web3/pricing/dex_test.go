@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservesPrice(t *testing.T) {
+	// given 1000 tokens with 18 decimals against 2000 tokens with 6 decimals
+	base := new(big.Int).Mul(big.NewInt(1000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+	quote := big.NewInt(2000_000000)
+
+	// when
+	price, _ := reservesPrice(base, quote, 18, 6).Float64()
+
+	// then
+	require.InDelta(t, 2, price, 1e-12)
+	zero, _ := reservesPrice(new(big.Int), quote, 18, 6).Float64()
+	require.Zero(t, zero)
+}
+
+func TestSqrtPrice(t *testing.T) {
+	q96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	price, _ := sqrtPrice(q96, 18, 18).Float64()
+	require.InDelta(t, 1, price, 1e-12)
+
+	price, _ = sqrtPrice(new(big.Int).Mul(q96, big.NewInt(2)), 18, 18).Float64()
+	require.InDelta(t, 4, price, 1e-12)
+
+	price, _ = sqrtPrice(new(big.Int).Mul(q96, big.NewInt(2)), 6, 18).Float64()
+	require.InDelta(t, 4e-12, price, 1e-24)
+}
+
+func TestTickPrice(t *testing.T) {
+	// given usdc/weth pool, token0 is usdc with 6 decimals and token1 is weth with 18 decimals
+	price, _ := tickPrice(200000, 6, 18).Float64()
+
+	// then weth price in usdc is inverse of token0 price
+	require.InDelta(t, 2063.2, 1/price, 0.1)
+	price, _ = tickPrice(0, 18, 18).Float64()
+	require.InDelta(t, 1, price, 1e-12)
+}
+
+func TestAverageTick(t *testing.T) {
+	for _, tc := range []struct {
+		start, end int64
+		expected   int64
+	}{{100, 110, 3}, {110, 100, -4}, {15, 0, -5}} {
+		tick, err := averageTick(big.NewInt(tc.start), big.NewInt(tc.end), 3)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, tick)
+	}
+	_, err := averageTick(big.NewInt(0), big.NewInt(10), 0)
+	require.ErrorIs(t, err, ErrInvalidTWAPWindow)
+}
+
+func TestV2CumulativePrices(t *testing.T) {
+	// given pair with price of token0 = 2 token1 last updated at 100
+	q112 := new(big.Int).Lsh(big.NewInt(1), 112)
+	start0 := new(big.Int).Mul(q112, big.NewInt(50))
+
+	// when cumulative prices are extended to 110
+	end0, end1 := cumulativeAt(start0, new(big.Int), big.NewInt(1000), big.NewInt(2000), 100, 110)
+
+	// then
+	require.Equal(t, new(big.Int).Mul(q112, big.NewInt(70)), end0)
+	require.Equal(t, new(big.Int).Mul(q112, big.NewInt(5)), end1)
+	price, _ := averagePrice(start0, end0, 10).Float64()
+	require.InDelta(t, 2, price, 1e-12)
+
+	unchanged, _ := cumulativeAt(start0, new(big.Int), big.NewInt(1000), big.NewInt(2000), 110, 110)
+	require.Equal(t, start0, unchanged)
+}
+
+func TestAveragePrice_Overflow(t *testing.T) {
+	// given cumulative price which wrapped uint256 in pair contract
+	q112 := new(big.Int).Lsh(big.NewInt(1), 112)
+	start := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), new(big.Int).Mul(q112, big.NewInt(10)))
+	end := new(big.Int).Mul(q112, big.NewInt(20))
+
+	// when
+	price, _ := averagePrice(start, end, 10).Float64()
+
+	// then
+	require.InDelta(t, 3, price, 1e-12)
+}
+
+func TestParseDEXPools(t *testing.T) {
+	pool := common.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc")
+	pools, err := parseDEXPools([]config.DEXPoolConfig{{
+		Chain:       "eth",
+		Token:       "STG",
+		Quote:       "USDC",
+		Type:        PoolTypeUniswapV2,
+		Pool:        "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc",
+		PoolAddress: pool,
+	}})
+	require.NoError(t, err)
+	require.Len(t, pools[entities.STG], 1)
+	require.Equal(t, entities.USDC, pools[entities.STG][0].quote)
+	require.Equal(t, pool, pools[entities.STG][0].address)
+
+	_, err = parseDEXPools([]config.DEXPoolConfig{{Chain: "eth", Token: "STG", Quote: "USDC", Type: "curve", Pool: "0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc", PoolAddress: pool}})
+	require.ErrorIs(t, err, ErrUnknownPool)
+
+	_, err = parseDEXPools([]config.DEXPoolConfig{{Chain: "eth", Token: "STG", Quote: "USDC", Type: PoolTypeUniswapV3, Pool: "0x123"}})
+	require.Error(t, err)
+}
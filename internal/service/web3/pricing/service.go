@@ -0,0 +1,258 @@
+package pricing
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+const (
+	SourceChainlink = "chainlink"
+
+	defaultHeartbeat      = 24 * time.Hour // longest heartbeat of Chainlink feeds
+	defaultHeartbeatGrace = 10 * time.Minute
+	defaultCacheTTL       = 30 * time.Second
+)
+
+var (
+	ErrNoPrice     = errors.New("no price for token")
+	ErrStaleRound  = errors.New("stale price round")
+	ErrInvalidData = errors.New("invalid price round")
+)
+
+// Service reads USD prices from Chainlink aggregators of entities.PriceFeeds.
+// Tokens without feed are priced from configured dex pools against quote asset.
+type Service struct {
+	log             logger.AppLogger
+	heartbeat       time.Duration
+	heartbeats      map[common.Address]time.Duration
+	grace           time.Duration
+	cacheTTL        time.Duration
+	twapWindow      time.Duration
+	minLiquidityUSD float64
+	pools           map[entities.Token][]dexPool
+
+	mu    sync.Mutex
+	cache map[priceKey]*cachedPrice
+}
+
+// priceKey is token priced for chain, fallback feed and pools depend on requested chain
+type priceKey struct {
+	chain entities.Chain
+	token entities.Token
+}
+
+type cachedPrice struct {
+	price     *entities.Price
+	fetchedAt time.Time
+}
+
+func NewService(log logger.AppLogger, conf config.PricingConfig) (*Service, error) {
+	if conf.TWAPWindow < 0 || (conf.TWAPWindow > 0 && conf.TWAPWindow < time.Second) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTWAPWindow, conf.TWAPWindow)
+	}
+	pools, err := parseDEXPools(conf.DEXPools)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse dex pools: %w", err)
+	}
+	srv := &Service{
+		log:             log.With(zap.String("service", "pricing")),
+		heartbeat:       conf.MaxPriceAge,
+		heartbeats:      make(map[common.Address]time.Duration, len(entities.PriceFeedHeartbeats)+len(conf.FeedHeartbeats)),
+		grace:           conf.HeartbeatGrace,
+		cacheTTL:        conf.CacheTTL,
+		twapWindow:      conf.TWAPWindow,
+		minLiquidityUSD: conf.MinLiquidityUSD,
+		pools:           pools,
+		cache:           make(map[priceKey]*cachedPrice),
+	}
+	if srv.heartbeat <= 0 {
+		srv.heartbeat = defaultHeartbeat
+	}
+	if srv.grace <= 0 {
+		srv.grace = defaultHeartbeatGrace
+	}
+	for feed, heartbeat := range entities.PriceFeedHeartbeats {
+		srv.heartbeats[feed] = heartbeat
+	}
+	for feed, heartbeat := range conf.FeedHeartbeats {
+		if !common.IsHexAddress(feed) || heartbeat <= 0 {
+			return nil, fmt.Errorf("invalid heartbeat of price feed %s: %s", feed, heartbeat)
+		}
+		srv.heartbeats[common.HexToAddress(feed)] = heartbeat
+	}
+	if srv.cacheTTL <= 0 {
+		srv.cacheTTL = defaultCacheTTL
+	}
+	if srv.minLiquidityUSD <= 0 {
+		srv.minLiquidityUSD = defaultMinLiquidityUSD
+	}
+	return srv, nil
+}
+
+// GetUSDPrice returns USD price of token. Feed on requested chain is preferred, feeds on other configured chains are fallback.
+// Dex pools are used only when no feed returned valid price.
+func (s *Service) GetUSDPrice(ctx context.Context, chain entities.Chain, token entities.Token) (*entities.Price, error) {
+	return s.getUSDPrice(ctx, chain, token, 0)
+}
+
+func (s *Service) getUSDPrice(ctx context.Context, chain entities.Chain, token entities.Token, depth int) (*entities.Price, error) {
+	if price := s.getCached(chain, token); price != nil {
+		return price, nil
+	}
+	for _, feed := range entities.GetPriceFeeds(chain, token) {
+		if !rpc.ChainAvailable(feed.Chain) {
+			continue
+		}
+		price, err := s.readFeed(ctx, token, feed)
+		if err != nil {
+			s.log.Error("unable to read price feed", err,
+				zap.String("token", string(token)),
+				zap.String("chain", feed.Chain.String()),
+				zap.String("feed", feed.Address.String()),
+			)
+			continue
+		}
+		s.setCached(chain, token, price)
+		return price, nil
+	}
+	if depth >= maxQuoteDepth {
+		return nil, ErrNoPrice
+	}
+	for _, pool := range s.getPools(chain, token) {
+		if !rpc.ChainAvailable(pool.chain) {
+			continue
+		}
+		price, err := s.readDEXPrice(ctx, pool, depth)
+		if err != nil {
+			s.log.Error("unable to read dex pool price", err,
+				zap.String("token", string(token)),
+				zap.String("chain", pool.chain.String()),
+				zap.String("pool", pool.address.String()),
+			)
+			continue
+		}
+		s.setCached(chain, token, price)
+		return price, nil
+	}
+	return nil, ErrNoPrice
+}
+
+func (s *Service) readDEXPrice(ctx context.Context, pool dexPool, depth int) (*entities.Price, error) {
+	quotePrice, err := s.getUSDPrice(ctx, pool.chain, pool.quote, depth+1)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get quote price: %w", err)
+	}
+	quoteUSD, err := decimal.NewFromString(quotePrice.USDPrice)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse quote price: %w", err)
+	}
+	client, err := web3.GetWeb3Client(pool.chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return s.readPool(ctx, client, pool, quoteUSD)
+}
+
+// Valuate sets USD price and value of balance. Balance is left without valuation if there is no reliable price.
+func (s *Service) Valuate(ctx context.Context, balance *entities.Balance) {
+	price, err := s.GetUSDPrice(ctx, balance.Chain, balance.Token)
+	if err != nil {
+		return
+	}
+	amount, err := decimal.NewFromString(balance.TokenBalance)
+	if err != nil {
+		return
+	}
+	usdPrice, err := decimal.NewFromString(price.USDPrice)
+	if err != nil {
+		return
+	}
+	balance.USDPrice = price.USDPrice
+	balance.USDValue = amount.Mul(usdPrice).StringFixed(2)
+	balance.USDPriceUpdatedAt = price.UpdatedAt
+	balance.USDPriceSource = price.Source
+}
+
+func (s *Service) readFeed(ctx context.Context, token entities.Token, feed entities.ChainAddress) (*entities.Price, error) {
+	client, err := web3.GetWeb3Client(feed.Chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	aggregator, err := NewAggregatorV3Caller(feed.Address, client)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	decimals, err := aggregator.Decimals(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get feed decimals: %w", err)
+	}
+	round, err := aggregator.LatestRoundData(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get latest round: %w", err)
+	}
+	if err = validateRound(round.RoundId, round.Answer, round.UpdatedAt, round.AnsweredInRound, s.maxAge(feed.Address)); err != nil {
+		return nil, err
+	}
+	return &entities.Price{
+		Token:     token,
+		USDPrice:  decimal.NewFromBigInt(round.Answer, -int32(decimals)).String(),
+		UpdatedAt: round.UpdatedAt.Int64(),
+		Source:    SourceChainlink,
+	}, nil
+}
+
+// maxAge is heartbeat of feed with grace, feeds without known heartbeat use configured default
+func (s *Service) maxAge(feed common.Address) time.Duration {
+	heartbeat, ok := s.heartbeats[feed]
+	if !ok {
+		heartbeat = s.heartbeat
+	}
+	return heartbeat + s.grace
+}
+
+// validateRound rejects rounds with non-positive answer, incomplete rounds and rounds older than maxAge
+func validateRound(roundID, answer, updatedAt, answeredInRound *big.Int, maxAge time.Duration) error {
+	if answer == nil || answer.Sign() <= 0 || updatedAt == nil || updatedAt.Sign() == 0 {
+		return ErrInvalidData
+	}
+	if answeredInRound.Cmp(roundID) < 0 {
+		return ErrStaleRound
+	}
+	if time.Since(time.Unix(updatedAt.Int64(), 0)) > maxAge {
+		return ErrStaleRound
+	}
+	return nil
+}
+
+func (s *Service) getCached(chain entities.Chain, token entities.Token) *entities.Price {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cached, ok := s.cache[priceKey{chain: chain, token: token}]
+	if !ok || time.Since(cached.fetchedAt) > s.cacheTTL {
+		return nil
+	}
+	return cached.price
+}
+
+func (s *Service) setCached(chain entities.Chain, token entities.Token, price *entities.Price) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[priceKey{chain: chain, token: token}] = &cachedPrice{price: price, fetchedAt: time.Now()}
+}
@@ -0,0 +1,86 @@
+package pricing
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRound(t *testing.T) {
+	now := time.Now().Unix()
+	table := map[string]struct {
+		roundID, answer, updatedAt, answeredInRound int64
+		expected                                    error
+	}{
+		"fresh round":      {roundID: 10, answer: 180000000000, updatedAt: now - 60, answeredInRound: 10},
+		"old round":        {roundID: 10, answer: 180000000000, updatedAt: now - 7200, answeredInRound: 10, expected: ErrStaleRound},
+		"carried over":     {roundID: 10, answer: 180000000000, updatedAt: now - 60, answeredInRound: 9, expected: ErrStaleRound},
+		"negative answer":  {roundID: 10, answer: -1, updatedAt: now - 60, answeredInRound: 10, expected: ErrInvalidData},
+		"incomplete round": {roundID: 10, answer: 180000000000, updatedAt: 0, answeredInRound: 10, expected: ErrInvalidData},
+	}
+	for name, tc := range table {
+		err := validateRound(big.NewInt(tc.roundID), big.NewInt(tc.answer), big.NewInt(tc.updatedAt), big.NewInt(tc.answeredInRound), time.Hour)
+		require.ErrorIs(t, err, tc.expected, name)
+	}
+}
+
+func TestService_MaxAge(t *testing.T) {
+	// given
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	overridden := entities.PriceFeeds[entities.ETH][entities.ChainOptimism]
+	srv, err := NewService(appLog, config.PricingConfig{
+		HeartbeatGrace: time.Minute,
+		FeedHeartbeats: map[string]time.Duration{strings.ToLower(overridden.Hex()): 20 * time.Minute},
+	})
+	require.NoError(t, err)
+
+	// then
+	require.Equal(t, 61*time.Minute, srv.maxAge(entities.PriceFeeds[entities.ETH][entities.ChainEthereum]))
+	require.Equal(t, 21*time.Minute, srv.maxAge(overridden))
+	require.Equal(t, 24*time.Hour+time.Minute, srv.maxAge(entities.PriceFeeds[entities.ETH][entities.ChainArbitrum]))
+
+	_, err = NewService(appLog, config.PricingConfig{FeedHeartbeats: map[string]time.Duration{"0x1": time.Hour}})
+	require.Error(t, err)
+}
+
+func TestNewService_TWAPWindow(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	for _, window := range []time.Duration{0, time.Second, 30 * time.Minute} {
+		_, err = NewService(appLog, config.PricingConfig{TWAPWindow: window})
+		require.NoError(t, err, window)
+	}
+	for _, window := range []time.Duration{-time.Second, 500 * time.Millisecond} {
+		_, err = NewService(appLog, config.PricingConfig{TWAPWindow: window})
+		require.ErrorIs(t, err, ErrInvalidTWAPWindow, window)
+	}
+}
+
+func TestGetPriceFeeds(t *testing.T) {
+	chains := func(feeds []entities.ChainAddress) []entities.Chain {
+		result := make([]entities.Chain, 0, len(feeds))
+		for _, feed := range feeds {
+			result = append(result, feed.Chain)
+		}
+		return result
+	}
+
+	t.Run("feed of requested chain goes first", func(t *testing.T) {
+		feeds := chains(entities.GetPriceFeeds(entities.ChainPolygon, entities.ETH))
+		require.Equal(t, entities.ChainPolygon, feeds[0])
+		require.IsIncreasing(t, feeds[1:])
+	})
+
+	t.Run("chain without feed gets stable fallback order", func(t *testing.T) {
+		feeds := chains(entities.GetPriceFeeds(entities.ChainBNB, entities.ETH))
+		require.Len(t, feeds, len(entities.PriceFeeds[entities.ETH]))
+		require.IsIncreasing(t, feeds)
+	})
+}
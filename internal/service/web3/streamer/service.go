@@ -0,0 +1,43 @@
+package streamer
+
+import (
+	"altt/internal/logger"
+	"altt/internal/service/web3/approver"
+	"altt/internal/service/web3/heads"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+const defaultMaxSubscriptions = 20
+
+var (
+	ErrSubscriptionLimit = errors.New("subscription limit reached")
+	ErrChainNotAvailable = errors.New("chain is not available")
+)
+
+// Service streams balance changes of subscribed (chain, token, address) tuples.
+// Balances are re-read on every new head, erc20 balances only if Transfer logs of the block range touch the address.
+type Service struct {
+	log              logger.AppLogger
+	erc20            *approver.Service
+	heads            *heads.Service
+	maxSubscriptions int
+}
+
+func NewService(log logger.AppLogger, erc20 *approver.Service, headsService *heads.Service, maxSubscriptions int) *Service {
+	if maxSubscriptions <= 0 {
+		maxSubscriptions = defaultMaxSubscriptions
+	}
+	return &Service{
+		log:              log.With(zap.String("service", "streamer")),
+		erc20:            erc20,
+		heads:            headsService,
+		maxSubscriptions: maxSubscriptions,
+	}
+}
+
+// MaxSubscriptions returns limit of subscriptions per session
+func (s *Service) MaxSubscriptions() int {
+	return s.maxSubscriptions
+}
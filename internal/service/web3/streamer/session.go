@@ -0,0 +1,256 @@
+package streamer
+
+import (
+	"altt/internal/entities"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+const updatesBuffer = 64
+
+// Subscription is a balance to watch. Empty token means native coin of the chain.
+type Subscription struct {
+	Chain   entities.Chain
+	Token   entities.Token
+	Address common.Address
+}
+
+func (s Subscription) key() string {
+	return fmt.Sprintf("%s-%s-%s", s.Chain.String(), string(s.Token), s.Address.String())
+}
+
+func (s Subscription) native() bool {
+	return s.Token == ""
+}
+
+// normalized treats fuel token of the chain as native coin
+func (s Subscription) normalized() Subscription {
+	if s.Token == entities.MapChainToFuel(s.Chain) {
+		s.Token = ""
+	}
+	return s
+}
+
+type watchedBalance struct {
+	sub     Subscription
+	lastWei string
+}
+
+// Session holds subscriptions of one client connection.
+type Session struct {
+	srv     *Service
+	ctx     context.Context
+	cancel  context.CancelFunc
+	updates chan *entities.Balance
+
+	mu     sync.Mutex
+	subs   map[string]*watchedBalance
+	chains map[entities.Chain]func()
+}
+
+func (s *Service) NewSession() *Session {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Session{
+		srv:     s,
+		ctx:     ctx,
+		cancel:  cancel,
+		updates: make(chan *entities.Balance, updatesBuffer),
+		subs:    make(map[string]*watchedBalance),
+		chains:  make(map[entities.Chain]func()),
+	}
+}
+
+// Updates returns channel with balance snapshots: current balance right after subscribe and every change after.
+func (ss *Session) Updates() <-chan *entities.Balance {
+	return ss.updates
+}
+
+// Done is closed when session is closed
+func (ss *Session) Done() <-chan struct{} {
+	return ss.ctx.Done()
+}
+
+// Subscribe reads current balance and starts watching it. onSubscribed is called once subscription is accepted and
+// before current balance is sent to updates, so client gets acknowledgement ahead of the first update. Nothing is
+// registered when balance can not be read. Subscribe and Unsubscribe of a session are called from one goroutine.
+func (ss *Session) Subscribe(sub Subscription, onSubscribed func()) error {
+	if !rpc.ChainAvailable(sub.Chain) {
+		return ErrChainNotAvailable
+	}
+	sub = sub.normalized()
+	if !sub.native() {
+		if _, err := entities.GetTokenAddress(sub.Chain, sub.Token); err != nil {
+			return err
+		}
+	}
+	ss.mu.Lock()
+	_, subscribed := ss.subs[sub.key()]
+	full := len(ss.subs) >= ss.srv.maxSubscriptions
+	ss.mu.Unlock()
+	if subscribed {
+		onSubscribed()
+		return nil
+	}
+	if full {
+		return ErrSubscriptionLimit
+	}
+
+	client, err := web3.GetWeb3Client(sub.Chain)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	balance, err := ss.readBalance(client, sub)
+	if err != nil {
+		return err
+	}
+	onSubscribed()
+
+	ss.mu.Lock()
+	ss.subs[sub.key()] = &watchedBalance{sub: sub, lastWei: balance.TokenBalanceWei}
+	if _, ok := ss.chains[sub.Chain]; !ok {
+		headsCh, unsubscribe := ss.srv.heads.Subscribe(sub.Chain)
+		ss.chains[sub.Chain] = unsubscribe
+		go ss.followChain(sub.Chain, headsCh)
+	}
+	ss.mu.Unlock()
+	ss.send(balance)
+	return nil
+}
+
+// Unsubscribe stops watching balance
+func (ss *Session) Unsubscribe(sub Subscription) {
+	sub = sub.normalized()
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	delete(ss.subs, sub.key())
+	for _, watched := range ss.subs {
+		if watched.sub.Chain == sub.Chain {
+			return
+		}
+	}
+	if unsubscribe, ok := ss.chains[sub.Chain]; ok {
+		unsubscribe()
+		delete(ss.chains, sub.Chain)
+	}
+}
+
+// Close drops all subscriptions of the session
+func (ss *Session) Close() {
+	ss.cancel()
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for chain, unsubscribe := range ss.chains {
+		unsubscribe()
+		delete(ss.chains, chain)
+	}
+}
+
+func (ss *Session) followChain(chain entities.Chain, headsCh <-chan *types.Header) {
+	var lastBlock uint64
+	for head := range headsCh {
+		fromBlock := head.Number.Uint64()
+		if lastBlock != 0 && lastBlock < fromBlock {
+			fromBlock = lastBlock + 1
+		}
+		lastBlock = head.Number.Uint64()
+		ss.refreshChain(chain, fromBlock, lastBlock)
+	}
+}
+
+// refreshChain re-reads native balances and erc20 balances touched by Transfer logs in blocks range.
+func (ss *Session) refreshChain(chain entities.Chain, fromBlock, toBlock uint64) {
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		ss.srv.log.Error("unable to get web3 client", err, zap.String("chain", chain.String()))
+		return
+	}
+	defer client.Close()
+
+	byToken := make(map[entities.Token][]*watchedBalance)
+	ss.mu.Lock()
+	for _, watched := range ss.subs {
+		if watched.sub.Chain == chain {
+			byToken[watched.sub.Token] = append(byToken[watched.sub.Token], watched)
+		}
+	}
+	ss.mu.Unlock()
+
+	for token, list := range byToken {
+		if token != "" {
+			list = ss.touchedByTransfers(client, chain, token, fromBlock, toBlock, list)
+		}
+		for _, watched := range list {
+			ss.refreshBalance(client, watched)
+		}
+	}
+}
+
+// touchedByTransfers filters watched balances to ones which took part in token transfers.
+// If logs are not available all balances are returned, so change is not missed.
+func (ss *Session) touchedByTransfers(client *ethclient.Client, chain entities.Chain, token entities.Token, fromBlock, toBlock uint64, list []*watchedBalance) []*watchedBalance {
+	tokenAddress, err := entities.GetTokenAddress(chain, token)
+	if err != nil {
+		return nil
+	}
+	addresses := make([]common.Address, 0, len(list))
+	for _, watched := range list {
+		addresses = append(addresses, watched.sub.Address)
+	}
+	touched, err := ss.srv.erc20.GetTransferParticipants(ss.ctx, client, tokenAddress, fromBlock, toBlock, addresses)
+	if err != nil {
+		ss.srv.log.Error("unable to get transfer logs, re-read all balances", err,
+			zap.String("chain", chain.String()),
+			zap.String("token", string(token)),
+		)
+		return list
+	}
+	result := make([]*watchedBalance, 0, len(touched))
+	for _, watched := range list {
+		if _, ok := touched[watched.sub.Address]; ok {
+			result = append(result, watched)
+		}
+	}
+	return result
+}
+
+// refreshBalance reads balance and sends update if it differs from last sent one
+func (ss *Session) refreshBalance(client *ethclient.Client, watched *watchedBalance) {
+	balance, err := ss.readBalance(client, watched.sub)
+	if err != nil {
+		ss.srv.log.Error("unable to read balance", err,
+			zap.String("chain", watched.sub.Chain.String()),
+			zap.String("token", string(watched.sub.Token)),
+			zap.String("address", watched.sub.Address.String()),
+		)
+		return
+	}
+	ss.mu.Lock()
+	if _, ok := ss.subs[watched.sub.key()]; !ok || watched.lastWei == balance.TokenBalanceWei {
+		ss.mu.Unlock()
+		return
+	}
+	watched.lastWei = balance.TokenBalanceWei
+	ss.mu.Unlock()
+	ss.send(balance)
+}
+
+func (ss *Session) readBalance(client *ethclient.Client, sub Subscription) (*entities.Balance, error) {
+	return ss.srv.erc20.GetBalance(ss.ctx, client, sub.Chain, sub.Token, sub.Address, entities.BlockTagLatest)
+}
+
+// send passes balance to updates unless session is closed
+func (ss *Session) send(balance *entities.Balance) {
+	select {
+	case ss.updates <- balance:
+	case <-ss.ctx.Done():
+	}
+}
@@ -0,0 +1,180 @@
+package transactions
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/approver"
+	"altt/internal/service/web3/gas"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrChainNotAvailable = errors.New("chain is not available")
+	ErrTxNotFound        = errors.New("transaction is not found")
+)
+
+// txBackend is part of rpc client used to read transaction
+type txBackend interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Service reads status of transactions with erc20 events decoded from receipt logs
+type Service struct {
+	log logger.AppLogger
+}
+
+func NewService(log logger.AppLogger) *Service {
+	return &Service{
+		log: log.With(zap.String("service", "transactions")),
+	}
+}
+
+// GetTransaction returns status of transaction. ErrTxNotFound is returned when node does not know the hash,
+// transaction from mempool has TxStatusPending.
+func (s *Service) GetTransaction(ctx context.Context, chain entities.Chain, hash common.Hash) (*entities.TransactionInfo, error) {
+	if !rpc.ChainAvailable(chain) {
+		return nil, ErrChainNotAvailable
+	}
+	connector, err := web3.GetConnector(chain)
+	if err != nil {
+		return nil, err
+	}
+	client, err := connector.GetWeb3()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get web3 client: %w", err)
+	}
+	defer client.Close()
+	info, err := s.getTransaction(ctx, client, chain, hash)
+	if err != nil {
+		return nil, err
+	}
+	info.ExplorerURL = connector.TxHash(hash.String())
+	return info, nil
+}
+
+func (s *Service) getTransaction(ctx context.Context, backend txBackend, chain entities.Chain, hash common.Hash) (*entities.TransactionInfo, error) {
+	tx, isPending, err := backend.TransactionByHash(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, ErrTxNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get transaction: %w", err)
+	}
+	info := &entities.TransactionInfo{
+		Chain:       chain,
+		ChainName:   chain.String(),
+		Hash:        hash.String(),
+		Status:      entities.TxStatusPending,
+		ERC20Events: make([]entities.TxERC20Event, 0),
+	}
+	if from, errSender := types.Sender(types.LatestSignerForChainID(chain.ChainIDBI()), tx); errSender == nil {
+		info.From = from.String()
+	}
+	if tx.To() != nil {
+		info.To = tx.To().String()
+	}
+	if isPending {
+		return info, nil
+	}
+	receipt, err := backend.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		// node which served transaction has no receipt yet, it is just included
+		return info, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get transaction receipt: %w", err)
+	}
+	latest, err := backend.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get latest block: %w", err)
+	}
+
+	info.Status = entities.TxStatusFailed
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		info.Status = entities.TxStatusSuccess
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		info.ContractAddress = receipt.ContractAddress.String()
+	}
+	info.BlockNumber = receipt.BlockNumber.Uint64()
+	info.BlockHash = receipt.BlockHash.String()
+	if latest >= info.BlockNumber {
+		info.Confirmations = latest - info.BlockNumber + 1
+	}
+	info.GasUsed = receipt.GasUsed
+	if receipt.EffectiveGasPrice != nil {
+		price := gas.NewGasFee(receipt.EffectiveGasPrice)
+		info.EffectiveGasPrice = &price
+	}
+	if info.ERC20Events, err = decodeERC20Events(chain, receipt.Logs); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// decodeERC20Events decodes Transfer and Approval logs of any erc20 contract. Erc721 events have the same
+// signatures with indexed token id and are skipped.
+func decodeERC20Events(chain entities.Chain, logs []*types.Log) ([]entities.TxERC20Event, error) {
+	parsed, err := approver.Erc20MetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	filterer, err := approver.NewErc20Filterer(common.Address{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	transferID, approvalID := parsed.Events["Transfer"].ID, parsed.Events["Approval"].ID
+	result := make([]entities.TxERC20Event, 0)
+	for _, log := range logs {
+		if len(log.Topics) != 3 {
+			continue
+		}
+		var event entities.TxERC20Event
+		switch log.Topics[0] {
+		case transferID:
+			transfer, errParse := filterer.ParseTransfer(*log)
+			if errParse != nil {
+				continue
+			}
+			event = newERC20Event(chain, entities.TxEventTransfer, log, transfer.Value)
+			event.From, event.To = transfer.From.String(), transfer.To.String()
+		case approvalID:
+			approval, errParse := filterer.ParseApproval(*log)
+			if errParse != nil {
+				continue
+			}
+			event = newERC20Event(chain, entities.TxEventApproval, log, approval.Value)
+			event.Owner, event.Spender = approval.Owner.String(), approval.Spender.String()
+		default:
+			continue
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func newERC20Event(chain entities.Chain, event entities.TxEventType, log *types.Log, value *big.Int) entities.TxERC20Event {
+	result := entities.TxERC20Event{
+		Event:     event,
+		LogIndex:  log.Index,
+		Contract:  log.Address.String(),
+		AmountWei: value.String(),
+	}
+	if token, ok := entities.GetTokenByAddress(chain, log.Address); ok {
+		result.Token = token
+		result.Amount = entities.CoinFromWEI(token, value)
+	}
+	return result
+}
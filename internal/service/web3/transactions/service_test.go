@@ -0,0 +1,132 @@
+package transactions
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBackend struct {
+	tx        *types.Transaction
+	isPending bool
+	receipt   *types.Receipt
+	latest    uint64
+}
+
+func (f *fakeBackend) TransactionByHash(context.Context, common.Hash) (*types.Transaction, bool, error) {
+	if f.tx == nil {
+		return nil, false, ethereum.NotFound
+	}
+	return f.tx, f.isPending, nil
+}
+
+func (f *fakeBackend) TransactionReceipt(context.Context, common.Hash) (*types.Receipt, error) {
+	if f.receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return f.receipt, nil
+}
+
+func (f *fakeBackend) BlockNumber(context.Context) (uint64, error) {
+	return f.latest, nil
+}
+
+func TestService_GetTransaction(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	srv := NewService(appLog)
+	chain := entities.ChainEthereum
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	usdc, err := entities.GetTokenAddress(chain, entities.USDC)
+	require.NoError(t, err)
+	spender := common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45")
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chain.ChainIDBI()), &types.DynamicFeeTx{
+		ChainID:   chain.ChainIDBI(),
+		Gas:       60_000,
+		GasFeeCap: big.NewInt(30_000_000_000),
+		GasTipCap: big.NewInt(1_000_000_000),
+		To:        &usdc,
+	})
+	require.NoError(t, err)
+
+	topic := func(address common.Address) common.Hash {
+		return common.BytesToHash(address.Bytes())
+	}
+	amount := common.BigToHash(big.NewInt(1_500_000)).Bytes()
+	transferID := crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	approvalID := crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	logs := []*types.Log{
+		{Address: usdc, Index: 1, Topics: []common.Hash{transferID, topic(sender), topic(spender)}, Data: amount},
+		{Address: other, Index: 2, Topics: []common.Hash{approvalID, topic(sender), topic(spender)}, Data: amount},
+		// erc721 transfer with indexed token id
+		{Address: other, Index: 3, Topics: []common.Hash{transferID, topic(sender), topic(spender), common.BigToHash(big.NewInt(7))}},
+	}
+
+	t.Run("mined", func(t *testing.T) {
+		// given
+		backend := &fakeBackend{
+			tx:     tx,
+			latest: 110,
+			receipt: &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				BlockNumber:       big.NewInt(100),
+				GasUsed:           50_000,
+				EffectiveGasPrice: big.NewInt(21_000_000_000),
+				Logs:              logs,
+			},
+		}
+
+		// when
+		info, err := srv.getTransaction(context.Background(), backend, chain, tx.Hash())
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, entities.TxStatusSuccess, info.Status)
+		require.Equal(t, sender.String(), info.From)
+		require.Equal(t, usdc.String(), info.To)
+		require.Equal(t, uint64(11), info.Confirmations)
+		require.Equal(t, "21", info.EffectiveGasPrice.Gwei)
+		require.Len(t, info.ERC20Events, 2)
+		require.Equal(t, entities.TxERC20Event{
+			Event: entities.TxEventTransfer, LogIndex: 1, Contract: usdc.String(), Token: entities.USDC,
+			From: sender.String(), To: spender.String(), Amount: "1.5", AmountWei: "1500000",
+		}, info.ERC20Events[0])
+		require.Equal(t, entities.TxEventApproval, info.ERC20Events[1].Event)
+		require.Empty(t, info.ERC20Events[1].Token)
+		require.Empty(t, info.ERC20Events[1].Amount)
+		require.Equal(t, spender.String(), info.ERC20Events[1].Spender)
+	})
+
+	t.Run("reverted", func(t *testing.T) {
+		backend := &fakeBackend{tx: tx, latest: 100, receipt: &types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(100)}}
+		info, err := srv.getTransaction(context.Background(), backend, chain, tx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, entities.TxStatusFailed, info.Status)
+		require.Equal(t, uint64(1), info.Confirmations)
+	})
+
+	t.Run("pending", func(t *testing.T) {
+		info, err := srv.getTransaction(context.Background(), &fakeBackend{tx: tx, isPending: true}, chain, tx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, entities.TxStatusPending, info.Status)
+		require.Equal(t, sender.String(), info.From)
+		require.Zero(t, info.BlockNumber)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := srv.getTransaction(context.Background(), &fakeBackend{}, chain, tx.Hash())
+		require.ErrorIs(t, err, ErrTxNotFound)
+	})
+}
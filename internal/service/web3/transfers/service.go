@@ -0,0 +1,178 @@
+package transfers
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/approver"
+	"altt/internal/service/web3/logrange"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInitialBlockRange = 5_000
+	defaultMaxBlockRange     = 100_000
+	defaultMaxScanBlocks     = 1_000_000
+)
+
+var (
+	ErrNativeToken       = errors.New("native coin transfers have no logs")
+	ErrChainNotAvailable = errors.New("chain is not available")
+)
+
+type fetchFunc func(ctx context.Context, fromBlock, toBlock uint64) ([]*approver.Erc20Transfer, error)
+
+// Service reads token transfer history of address from Transfer logs, newest first.
+// Block range of eth_getLogs adapts per chain to range limits of providers.
+type Service struct {
+	log    logger.AppLogger
+	erc20  *approver.Service
+	conf   config.TransfersConfig
+	ranges *logrange.Ranges
+}
+
+func NewService(log logger.AppLogger, erc20 *approver.Service, conf config.TransfersConfig) *Service {
+	if conf.InitialBlockRange == 0 {
+		conf.InitialBlockRange = defaultInitialBlockRange
+	}
+	if conf.MaxBlockRange == 0 {
+		conf.MaxBlockRange = defaultMaxBlockRange
+	}
+	if conf.MaxScanBlocks == 0 {
+		conf.MaxScanBlocks = defaultMaxScanBlocks
+	}
+	log = log.With(zap.String("service", "transfers"))
+	return &Service{
+		log:    log,
+		erc20:  erc20,
+		conf:   conf,
+		ranges: logrange.New(log, conf.InitialBlockRange, conf.MaxBlockRange),
+	}
+}
+
+// GetTransfers returns page of token transfers of address
+func (s *Service) GetTransfers(ctx context.Context, chain entities.Chain, token entities.Token, address common.Address, query entities.TransferQuery) (*entities.TransferPage, error) {
+	if entities.IsFuel(chain, token) {
+		return nil, ErrNativeToken
+	}
+	if !rpc.ChainAvailable(chain) {
+		return nil, ErrChainNotAvailable
+	}
+	tokenAddress, err := entities.GetTokenAddress(chain, token)
+	if err != nil {
+		return nil, err
+	}
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	end := query.ToBlock
+	if query.Cursor != nil {
+		end = query.Cursor.BlockNumber
+	} else if end == 0 {
+		if end, err = client.BlockNumber(ctx); err != nil {
+			return nil, fmt.Errorf("unable to get latest block: %w", err)
+		}
+	}
+	filters := directionFilters(address, query.Direction)
+	fetch := func(ctx context.Context, fromBlock, toBlock uint64) ([]*approver.Erc20Transfer, error) {
+		return s.erc20.FilterTransfers(ctx, client, tokenAddress, fromBlock, toBlock, filters...)
+	}
+	found, next, err := s.scan(ctx, chain, end, query.FromBlock, query.Limit, query.Cursor, fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entities.TransferPage{
+		Chain:     chain,
+		ChainName: chain.String(),
+		Token:     token,
+		Address:   address.String(),
+		FromBlock: query.FromBlock,
+		Transfers: make([]entities.Transfer, 0, len(found)),
+	}
+	if next != nil {
+		page.NextCursor = next.String()
+	}
+	for _, transfer := range found {
+		direction := entities.TransferDirectionIn
+		if transfer.From == address {
+			direction = entities.TransferDirectionOut
+		}
+		page.Transfers = append(page.Transfers, entities.Transfer{
+			TxHash:      transfer.Raw.TxHash.String(),
+			LogIndex:    transfer.Raw.Index,
+			BlockNumber: transfer.Raw.BlockNumber,
+			From:        transfer.From.String(),
+			To:          transfer.To.String(),
+			Direction:   direction,
+			Amount:      entities.CoinFromWEI(token, transfer.Value),
+			AmountWei:   transfer.Value.String(),
+		})
+	}
+	return page, nil
+}
+
+// scan walks blocks from end down to floor in chunks and collects up to limit transfers before cursor, newest first.
+// Returned cursor is nil when history is exhausted.
+func (s *Service) scan(
+	ctx context.Context,
+	chain entities.Chain,
+	end, floor uint64,
+	limit int,
+	cursor *entities.TransferCursor,
+	fetch fetchFunc,
+) ([]*approver.Erc20Transfer, *entities.TransferCursor, error) {
+	if end < floor {
+		return []*approver.Erc20Transfer{}, nil, nil
+	}
+	// one request scans at most MaxScanBlocks, next page continues below scanned range
+	var next *entities.TransferCursor
+	if end-floor+1 > s.conf.MaxScanBlocks {
+		floor = end - s.conf.MaxScanBlocks + 1
+		next = &entities.TransferCursor{BlockNumber: floor}
+	}
+	collected := make([]*approver.Erc20Transfer, 0, limit)
+	err := s.ranges.Walk(ctx, chain, floor, end, func(ctx context.Context, start, end uint64) error {
+		found, err := fetch(ctx, start, end)
+		if err != nil {
+			return err
+		}
+		for i := len(found) - 1; i >= 0; i-- {
+			transfer := found[i]
+			if cursor != nil && !cursor.Before(transfer.Raw.BlockNumber, transfer.Raw.Index) {
+				continue
+			}
+			collected = append(collected, transfer)
+			if len(collected) == limit {
+				next = &entities.TransferCursor{BlockNumber: transfer.Raw.BlockNumber, LogIndex: transfer.Raw.Index}
+				return logrange.ErrStopWalk
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return collected, next, nil
+}
+
+func directionFilters(address common.Address, direction entities.TransferDirection) []approver.TransferFilter {
+	addresses := []common.Address{address}
+	switch direction {
+	case entities.TransferDirectionIn:
+		return []approver.TransferFilter{{To: addresses}}
+	case entities.TransferDirectionOut:
+		return []approver.TransferFilter{{From: addresses}}
+	}
+	return []approver.TransferFilter{{From: addresses}, {To: addresses}}
+}
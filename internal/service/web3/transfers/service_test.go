@@ -0,0 +1,93 @@
+package transfers
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/web3/approver"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Scan(t *testing.T) {
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	// transfer in every 10th block from 0 to 990, one log per block
+	history := func(ctx context.Context, fromBlock, toBlock uint64) ([]*approver.Erc20Transfer, error) {
+		result := make([]*approver.Erc20Transfer, 0)
+		for block := fromBlock; block <= toBlock; block++ {
+			if block%10 == 0 {
+				result = append(result, &approver.Erc20Transfer{Value: big.NewInt(1), Raw: types.Log{BlockNumber: block, Index: 1}})
+			}
+		}
+		return result, nil
+	}
+
+	t.Run("pages follow cursor", func(t *testing.T) {
+		// given
+		srv := NewService(appLog, nil, config.TransfersConfig{InitialBlockRange: 100})
+
+		// when
+		first, cursor, err := srv.scan(context.Background(), entities.ChainEthereum, 999, 0, 15, nil, history)
+		require.NoError(t, err)
+		second, _, err := srv.scan(context.Background(), entities.ChainEthereum, cursor.BlockNumber, 0, 15, cursor, history)
+		require.NoError(t, err)
+
+		// then
+		require.Len(t, first, 15)
+		require.Equal(t, uint64(990), first[0].Raw.BlockNumber)
+		require.Equal(t, uint64(850), first[14].Raw.BlockNumber)
+		require.Equal(t, "850-1", cursor.String())
+		require.Len(t, second, 15)
+		require.Equal(t, uint64(840), second[0].Raw.BlockNumber)
+	})
+
+	t.Run("history is exhausted", func(t *testing.T) {
+		srv := NewService(appLog, nil, config.TransfersConfig{InitialBlockRange: 100})
+		found, cursor, err := srv.scan(context.Background(), entities.ChainEthereum, 999, 900, 50, nil, history)
+		require.NoError(t, err)
+		require.Len(t, found, 10)
+		require.Nil(t, cursor)
+	})
+
+	t.Run("range shrinks on provider limit", func(t *testing.T) {
+		// given provider which accepts at most 30 blocks
+		srv := NewService(appLog, nil, config.TransfersConfig{InitialBlockRange: 100})
+		limited := func(ctx context.Context, fromBlock, toBlock uint64) ([]*approver.Erc20Transfer, error) {
+			if toBlock-fromBlock+1 > 30 {
+				return nil, errors.New("eth_getLogs block range is too wide")
+			}
+			return history(ctx, fromBlock, toBlock)
+		}
+
+		// when
+		found, _, err := srv.scan(context.Background(), entities.ChainEthereum, 999, 0, 20, nil, limited)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, found, 20)
+		require.Equal(t, uint64(990), found[0].Raw.BlockNumber)
+		require.Equal(t, uint64(800), found[19].Raw.BlockNumber)
+	})
+
+	t.Run("scan budget returns cursor", func(t *testing.T) {
+		srv := NewService(appLog, nil, config.TransfersConfig{InitialBlockRange: 100, MaxScanBlocks: 200})
+		found, cursor, err := srv.scan(context.Background(), entities.ChainEthereum, 999, 0, 50, nil, history)
+		require.NoError(t, err)
+		require.Len(t, found, 20)
+		require.Equal(t, uint64(800), cursor.BlockNumber)
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		srv := NewService(appLog, nil, config.TransfersConfig{})
+		_, _, err := srv.scan(context.Background(), entities.ChainEthereum, 999, 0, 50, nil, func(ctx context.Context, fromBlock, toBlock uint64) ([]*approver.Erc20Transfer, error) {
+			return nil, errors.New("connection refused")
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,134 @@
+package verifier
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// accountProof is eth_getProof response
+type accountProof struct {
+	Address      common.Address `json:"address"`
+	AccountProof []string       `json:"accountProof"`
+	Balance      *hexutil.Big   `json:"balance"`
+	StorageHash  common.Hash    `json:"storageHash"`
+	StorageProof []storageProof `json:"storageProof"`
+}
+
+type storageProof struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
+// slotLayout is how compiler places mapping values in storage
+type slotLayout string
+
+const (
+	// layoutSolidity stores balances[holder] at keccak256(holder . slot)
+	layoutSolidity slotLayout = "solidity"
+	// layoutVyper stores balances[holder] at keccak256(slot . holder)
+	layoutVyper slotLayout = "vyper"
+)
+
+type balanceSlot struct {
+	index  uint64
+	layout slotLayout
+}
+
+// key returns storage key of holder balance in mapping located at slot
+func (b balanceSlot) key(holder common.Address) common.Hash {
+	holderWord := common.LeftPadBytes(holder.Bytes(), 32)
+	slotWord := common.LeftPadBytes(new(big.Int).SetUint64(b.index).Bytes(), 32)
+	if b.layout == layoutVyper {
+		return crypto.Keccak256Hash(slotWord, holderWord)
+	}
+	return crypto.Keccak256Hash(holderWord, slotWord)
+}
+
+// verifyAccountProof checks account proof against state root and returns proven account.
+// Valid proof of absence returns empty account.
+func verifyAccountProof(stateRoot common.Hash, address common.Address, proof []string) (*types.StateAccount, error) {
+	value, err := verifyProof(stateRoot, crypto.Keccak256(address.Bytes()), proof)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return &types.StateAccount{
+			Balance:  new(big.Int),
+			Root:     types.EmptyRootHash,
+			CodeHash: types.EmptyCodeHash.Bytes(),
+		}, nil
+	}
+	var account types.StateAccount
+	if err = rlp.DecodeBytes(value, &account); err != nil {
+		return nil, fmt.Errorf("%w: unable to decode account: %s", ErrInvalidProof, err)
+	}
+	return &account, nil
+}
+
+// verifyStorageProof checks storage proof against storage root of account and returns proven slot value.
+func verifyStorageProof(storageRoot, key common.Hash, proof []string) (*big.Int, error) {
+	if storageRoot == types.EmptyRootHash {
+		return new(big.Int), nil
+	}
+	value, err := verifyProof(storageRoot, crypto.Keccak256(key.Bytes()), proof)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return new(big.Int), nil
+	}
+	var content []byte
+	if err = rlp.DecodeBytes(value, &content); err != nil {
+		return nil, fmt.Errorf("%w: unable to decode storage value: %s", ErrInvalidProof, err)
+	}
+	return new(big.Int).SetBytes(content), nil
+}
+
+// findBalanceSlot returns candidate which slot value, proven against state root, equals expected balance. Proof has
+// storage proofs of candidates in the same order.
+func findBalanceSlot(stateRoot common.Hash, token, holder common.Address, candidates []balanceSlot, proof *accountProof, expected *big.Int) (balanceSlot, error) {
+	account, err := verifyAccountProof(stateRoot, token, proof.AccountProof)
+	if err != nil {
+		return balanceSlot{}, err
+	}
+	if account.Root != proof.StorageHash || len(proof.StorageProof) != len(candidates) {
+		return balanceSlot{}, ErrProofMismatch
+	}
+	for i, candidate := range candidates {
+		value, err := verifyStorageProof(account.Root, candidate.key(holder), proof.StorageProof[i].Proof)
+		if err != nil {
+			return balanceSlot{}, err
+		}
+		if value.Cmp(expected) == 0 {
+			return candidate, nil
+		}
+	}
+	return balanceSlot{}, ErrBalanceSlotUnknown
+}
+
+func verifyProof(root common.Hash, key []byte, proof []string) ([]byte, error) {
+	db := memorydb.New()
+	for _, encoded := range proof {
+		node, err := hexutil.Decode(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+		}
+		if err = db.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	value, err := trie.VerifyProof(root, key, db)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+	return value, nil
+}
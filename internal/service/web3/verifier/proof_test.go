@@ -0,0 +1,156 @@
+package verifier
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	holder = common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	token  = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+)
+
+func TestVerifyAccountProof(t *testing.T) {
+	// given state with holder account
+	balance := big.NewInt(1_000_000_000)
+	state := newTrie()
+	state.MustUpdate(crypto.Keccak256(holder.Bytes()), mustRLP(t, &types.StateAccount{
+		Nonce:    1,
+		Balance:  balance,
+		Root:     types.EmptyRootHash,
+		CodeHash: types.EmptyCodeHash.Bytes(),
+	}))
+	state.MustUpdate(crypto.Keccak256(token.Bytes()), mustRLP(t, &types.StateAccount{
+		Balance:  new(big.Int),
+		Root:     types.EmptyRootHash,
+		CodeHash: types.EmptyCodeHash.Bytes(),
+	}))
+	root := state.Hash()
+
+	t.Run("existing account", func(t *testing.T) {
+		account, err := verifyAccountProof(root, holder, prove(t, state, crypto.Keccak256(holder.Bytes())))
+		require.NoError(t, err)
+		require.Equal(t, balance, account.Balance)
+	})
+	t.Run("absent account", func(t *testing.T) {
+		absent := common.HexToAddress("0x0000000000000000000000000000000000000001")
+		account, err := verifyAccountProof(root, absent, prove(t, state, crypto.Keccak256(absent.Bytes())))
+		require.NoError(t, err)
+		require.Zero(t, account.Balance.Sign())
+	})
+	t.Run("proof of other root", func(t *testing.T) {
+		_, err := verifyAccountProof(common.HexToHash("0x01"), holder, prove(t, state, crypto.Keccak256(holder.Bytes())))
+		require.ErrorIs(t, err, ErrInvalidProof)
+	})
+}
+
+func TestVerifyStorageProof(t *testing.T) {
+	// given token storage with holder balance in solidity mapping at slot 9
+	slot := balanceSlot{index: 9, layout: layoutSolidity}
+	balance := big.NewInt(123_456_789)
+	storage := newTrie()
+	storage.MustUpdate(crypto.Keccak256(slot.key(holder).Bytes()), mustRLP(t, balance.Bytes()))
+	root := storage.Hash()
+
+	// when
+	value, err := verifyStorageProof(root, slot.key(holder), prove(t, storage, crypto.Keccak256(slot.key(holder).Bytes())))
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, balance, value)
+
+	// vyper layout points to other key with no value
+	vyperKey := balanceSlot{index: 9, layout: layoutVyper}.key(holder)
+	value, err = verifyStorageProof(root, vyperKey, prove(t, storage, crypto.Keccak256(vyperKey.Bytes())))
+	require.NoError(t, err)
+	require.Zero(t, value.Sign())
+}
+
+func TestFindBalanceSlot(t *testing.T) {
+	// given token storage with holder balance in solidity mapping at slot 2
+	balance := big.NewInt(5_000)
+	storage := newTrie()
+	slot := balanceSlot{index: 2, layout: layoutSolidity}
+	storage.MustUpdate(crypto.Keccak256(slot.key(holder).Bytes()), mustRLP(t, balance.Bytes()))
+	state := newTrie()
+	state.MustUpdate(crypto.Keccak256(token.Bytes()), mustRLP(t, &types.StateAccount{
+		Balance:  new(big.Int),
+		Root:     storage.Hash(),
+		CodeHash: crypto.Keccak256([]byte{1}),
+	}))
+	candidates := make([]balanceSlot, 0, 8)
+	for i := uint64(0); i < 4; i++ {
+		candidates = append(candidates, balanceSlot{index: i, layout: layoutSolidity}, balanceSlot{index: i, layout: layoutVyper})
+	}
+	proofOf := func(storage *trie.Trie) *accountProof {
+		proof := &accountProof{AccountProof: prove(t, state, crypto.Keccak256(token.Bytes())), StorageHash: storage.Hash()}
+		for _, candidate := range candidates {
+			proof.StorageProof = append(proof.StorageProof, storageProof{Proof: prove(t, storage, crypto.Keccak256(candidate.key(holder).Bytes()))})
+		}
+		return proof
+	}
+
+	t.Run("proven slot", func(t *testing.T) {
+		found, err := findBalanceSlot(state.Hash(), token, holder, candidates, proofOf(storage), balance)
+		require.NoError(t, err)
+		require.Equal(t, slot, found)
+	})
+
+	t.Run("balance in no slot", func(t *testing.T) {
+		_, err := findBalanceSlot(state.Hash(), token, holder, candidates, proofOf(storage), big.NewInt(1))
+		require.ErrorIs(t, err, ErrBalanceSlotUnknown)
+	})
+
+	t.Run("storage of other account", func(t *testing.T) {
+		// endpoint answers with storage where balance is at slot 0
+		forged := newTrie()
+		forged.MustUpdate(crypto.Keccak256(balanceSlot{index: 0, layout: layoutSolidity}.key(holder).Bytes()), mustRLP(t, balance.Bytes()))
+		_, err := findBalanceSlot(state.Hash(), token, holder, candidates, proofOf(forged), balance)
+		require.ErrorIs(t, err, ErrProofMismatch)
+
+		// the same with storage hash of the token
+		proof := proofOf(forged)
+		proof.StorageHash = storage.Hash()
+		_, err = findBalanceSlot(state.Hash(), token, holder, candidates, proof, balance)
+		require.ErrorIs(t, err, ErrInvalidProof)
+	})
+}
+
+func TestBalanceSlotKey(t *testing.T) {
+	slot := balanceSlot{index: 0, layout: layoutSolidity}
+	expected := crypto.Keccak256Hash(common.LeftPadBytes(holder.Bytes(), 32), make([]byte, 32))
+	require.Equal(t, expected, slot.key(holder))
+	require.NotEqual(t, slot.key(holder), balanceSlot{index: 0, layout: layoutVyper}.key(holder))
+}
+
+func newTrie() *trie.Trie {
+	return trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase()))
+}
+
+func prove(t *testing.T, tr *trie.Trie, key []byte) []string {
+	db := memorydb.New()
+	require.NoError(t, tr.Prove(key, 0, db))
+	proof := make([]string, 0)
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		proof = append(proof, hexutil.Encode(it.Value()))
+	}
+	return proof
+}
+
+func mustRLP(t *testing.T, value interface{}) []byte {
+	encoded, err := rlp.EncodeToBytes(value)
+	require.NoError(t, err)
+	return encoded
+}
@@ -0,0 +1,295 @@
+package verifier
+
+import (
+	"altt/internal/config"
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3/approver"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMinHeaderSources = 2
+	defaultMaxSlotIndex     = 20
+)
+
+var (
+	ErrInvalidProof       = errors.New("invalid merkle proof")
+	ErrProofMismatch      = errors.New("rpc response does not match proof")
+	ErrHeaderMismatch     = errors.New("rpc endpoints returned different block headers")
+	ErrNotEnoughSources   = errors.New("not enough independent rpc endpoints")
+	ErrUnverifiableTag    = errors.New("pending state can not be verified")
+	ErrBalanceSlotUnknown = errors.New("balance slot of token is unknown")
+)
+
+// VerifiedBalance is balance proven against state root of block header agreed by independent endpoints
+type VerifiedBalance struct {
+	Value       *big.Int
+	BlockNumber uint64
+	BlockHash   common.Hash
+}
+
+// Service checks balances with eth_getProof against block headers fetched from all configured endpoints of chain
+type Service struct {
+	log          logger.AppLogger
+	minSources   int
+	maxSlotIndex uint64
+
+	slotsMU sync.RWMutex
+	slots   map[entities.Chain]map[common.Address]balanceSlot
+}
+
+func NewService(log logger.AppLogger, conf config.VerifierConfig) *Service {
+	srv := &Service{
+		log:          log.With(zap.String("service", "verifier")),
+		minSources:   conf.MinHeaderSources,
+		maxSlotIndex: uint64(conf.MaxSlotIndex),
+		slots:        make(map[entities.Chain]map[common.Address]balanceSlot),
+	}
+	if srv.minSources < defaultMinHeaderSources {
+		srv.minSources = defaultMinHeaderSources
+	}
+	if conf.MaxSlotIndex <= 0 {
+		srv.maxSlotIndex = defaultMaxSlotIndex
+	}
+	return srv
+}
+
+// VerifyNativeBalance returns native balance of holder proven by account proof.
+func (s *Service) VerifyNativeBalance(ctx context.Context, chain entities.Chain, holder common.Address, tag entities.BlockTag) (*VerifiedBalance, error) {
+	header, err := s.getTrustedHeader(ctx, chain, tag)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dialNextNode(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	proof, err := getProof(ctx, client, holder, nil, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	account, err := verifyAccountProof(header.Root, holder, proof.AccountProof)
+	if err != nil {
+		return nil, err
+	}
+	if proof.Balance == nil || proof.Balance.ToInt().Cmp(account.Balance) != 0 {
+		return nil, ErrProofMismatch
+	}
+	return &VerifiedBalance{Value: account.Balance, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()}, nil
+}
+
+// VerifyERC20Balance returns token balance of holder proven by storage proof of token balances mapping.
+func (s *Service) VerifyERC20Balance(ctx context.Context, chain entities.Chain, token, holder common.Address, tag entities.BlockTag) (*VerifiedBalance, error) {
+	header, err := s.getTrustedHeader(ctx, chain, tag)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dialNextNode(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	slot, err := s.getBalanceSlot(ctx, client, chain, header, token, holder)
+	if err != nil {
+		return nil, err
+	}
+	key := slot.key(holder)
+	proof, err := getProof(ctx, client, token, []common.Hash{key}, header.Number)
+	if err != nil {
+		return nil, err
+	}
+	account, err := verifyAccountProof(header.Root, token, proof.AccountProof)
+	if err != nil {
+		return nil, err
+	}
+	if account.Root != proof.StorageHash || len(proof.StorageProof) != 1 {
+		return nil, ErrProofMismatch
+	}
+	value, err := verifyStorageProof(account.Root, key, proof.StorageProof[0].Proof)
+	if err != nil {
+		return nil, err
+	}
+	if proof.StorageProof[0].Value == nil || proof.StorageProof[0].Value.ToInt().Cmp(value) != 0 {
+		return nil, ErrProofMismatch
+	}
+	return &VerifiedBalance{Value: value, BlockNumber: header.Number.Uint64(), BlockHash: header.Hash()}, nil
+}
+
+// getBalanceSlot returns cached balances mapping slot of token or discovers it by probing slots of both layouts
+// and comparing values with balanceOf. Values of slots are proven against trusted header before slot is accepted and
+// cached, so endpoint is not able to point to other storage. Discovery needs holder with non-zero balance.
+func (s *Service) getBalanceSlot(ctx context.Context, client *node, chain entities.Chain, header *types.Header, token, holder common.Address) (balanceSlot, error) {
+	s.slotsMU.RLock()
+	slot, ok := s.slots[chain][token]
+	s.slotsMU.RUnlock()
+	if ok {
+		return slot, nil
+	}
+	erc20, err := approver.NewErc20Caller(token, client.eth)
+	if err != nil {
+		return balanceSlot{}, err
+	}
+	expected, err := erc20.BalanceOf(&bind.CallOpts{Context: ctx, BlockNumber: header.Number}, holder)
+	if err != nil {
+		return balanceSlot{}, fmt.Errorf("unable to get token balance: %w", err)
+	}
+	if expected.Sign() == 0 {
+		return balanceSlot{}, fmt.Errorf("%w: discovery needs holder with non-zero balance", ErrBalanceSlotUnknown)
+	}
+	candidates := make([]balanceSlot, 0, 2*(s.maxSlotIndex+1))
+	for i := uint64(0); i <= s.maxSlotIndex; i++ {
+		candidates = append(candidates, balanceSlot{index: i, layout: layoutSolidity}, balanceSlot{index: i, layout: layoutVyper})
+	}
+	keys := make([]common.Hash, 0, len(candidates))
+	for _, candidate := range candidates {
+		keys = append(keys, candidate.key(holder))
+	}
+	proof, err := getProof(ctx, client, token, keys, header.Number)
+	if err != nil {
+		return balanceSlot{}, err
+	}
+	slot, err = findBalanceSlot(header.Root, token, holder, candidates, proof, expected)
+	if err != nil {
+		return balanceSlot{}, err
+	}
+	s.slotsMU.Lock()
+	if s.slots[chain] == nil {
+		s.slots[chain] = make(map[common.Address]balanceSlot)
+	}
+	s.slots[chain][token] = slot
+	s.slotsMU.Unlock()
+	s.log.Info("discovered balance slot",
+		zap.String("chain", chain.String()),
+		zap.String("token", token.String()),
+		zap.Uint64("slot", slot.index),
+		zap.String("layout", string(slot.layout)),
+	)
+	return slot, nil
+}
+
+// getTrustedHeader returns header of tagged block which is the same on all endpoints of chain.
+// Lowest block number among endpoints is used, so lagging endpoints can still confirm it.
+func (s *Service) getTrustedHeader(ctx context.Context, chain entities.Chain, tag entities.BlockTag) (*types.Header, error) {
+	if tag == entities.BlockTagPending {
+		return nil, ErrUnverifiableTag
+	}
+	endpoints := rpc.GetRPCs(chain)
+	if len(endpoints) < s.minSources {
+		return nil, fmt.Errorf("%w: %d configured, %d required", ErrNotEnoughSources, len(endpoints), s.minSources)
+	}
+	clients := make([]*node, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		client, err := dialNode(ctx, endpoint)
+		if err != nil {
+			s.log.Error("unable to dial rpc", err, zap.String("chain", chain.String()))
+			continue
+		}
+		clients = append(clients, client)
+	}
+	defer func() {
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+
+	heads := fetchHeaders(ctx, clients, tag.BlockNumber())
+	var number *big.Int
+	for _, head := range heads {
+		if head != nil && (number == nil || head.Number.Cmp(number) < 0) {
+			number = head.Number
+		}
+	}
+	if number == nil {
+		return nil, ErrNotEnoughSources
+	}
+
+	var trusted *types.Header
+	agreed := 0
+	for _, header := range fetchHeaders(ctx, clients, number) {
+		if header == nil {
+			continue
+		}
+		if trusted == nil {
+			trusted = header
+		}
+		if header.Hash() != trusted.Hash() {
+			return nil, fmt.Errorf("%w: block %s", ErrHeaderMismatch, number.String())
+		}
+		agreed++
+	}
+	if agreed < s.minSources {
+		return nil, fmt.Errorf("%w: %d endpoints returned block %s, %d required", ErrNotEnoughSources, agreed, number.String(), s.minSources)
+	}
+	return trusted, nil
+}
+
+// fetchHeaders returns header of block from every client, nil for clients which failed
+func fetchHeaders(ctx context.Context, clients []*node, number *big.Int) []*types.Header {
+	headers := make([]*types.Header, len(clients))
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			header, err := clients[i].eth.HeaderByNumber(ctx, number)
+			if err == nil {
+				headers[i] = header
+			}
+		}(i)
+	}
+	wg.Wait()
+	return headers
+}
+
+func getProof(ctx context.Context, client *node, address common.Address, keys []common.Hash, number *big.Int) (*accountProof, error) {
+	if keys == nil {
+		keys = []common.Hash{}
+	}
+	var proof accountProof
+	if err := client.raw.CallContext(ctx, &proof, "eth_getProof", address, keys, hexutil.EncodeBig(number)); err != nil {
+		return nil, fmt.Errorf("unable to get proof: %w", err)
+	}
+	return &proof, nil
+}
+
+// node keeps raw rpc client for eth_getProof which ethclient does not expose
+type node struct {
+	raw *gethrpc.Client
+	eth *ethclient.Client
+}
+
+func dialNode(ctx context.Context, endpoint string) (*node, error) {
+	raw, err := gethrpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial rpc: %w", err)
+	}
+	return &node{raw: raw, eth: ethclient.NewClient(raw)}, nil
+}
+
+// dialNextNode connects to next rpc node of chain from rotation
+func dialNextNode(ctx context.Context, chain entities.Chain) (*node, error) {
+	endpoint, err := rpc.GetRPC(chain)
+	if err != nil {
+		return nil, err
+	}
+	return dialNode(ctx, endpoint)
+}
+
+func (n *node) Close() {
+	n.raw.Close()
+}
@@ -0,0 +1,72 @@
+package watchlist
+
+import (
+	"altt/internal/entities"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Repository persists watch definitions, last evaluated balances and dead letters
+type Repository interface {
+	LoadWatches() ([]*entities.Watch, error)
+	SaveWatches(watches []*entities.Watch) error
+	LoadWatchBalances() (map[string]*entities.Balance, error)
+	SaveWatchBalance(watchID string, balance *entities.Balance) error
+	deadLetterStore
+}
+
+// FileRepository keeps watch definitions in json file, it is read once to import watches into storage.
+// Empty path keeps them in memory only.
+type FileRepository struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileRepository(path string) *FileRepository {
+	return &FileRepository{path: path}
+}
+
+func (r *FileRepository) LoadWatches() ([]*entities.Watch, error) {
+	if r.path == "" {
+		return nil, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := os.ReadFile(filepath.Clean(r.path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read watches file: %w", err)
+	}
+	var watches []*entities.Watch
+	if err = json.Unmarshal(data, &watches); err != nil {
+		return nil, fmt.Errorf("unable to decode watches file: %w", err)
+	}
+	return watches, nil
+}
+
+// SaveWatches writes watches to temporary file and renames it, so file is never left half-written
+func (r *FileRepository) SaveWatches(watches []*entities.Watch) error {
+	if r.path == "" {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.Marshal(watches)
+	if err != nil {
+		return fmt.Errorf("unable to encode watches: %w", err)
+	}
+	tmp := filepath.Clean(r.path) + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("unable to write watches file: %w", err)
+	}
+	if err = os.Rename(tmp, filepath.Clean(r.path)); err != nil {
+		return fmt.Errorf("unable to replace watches file: %w", err)
+	}
+	return nil
+}
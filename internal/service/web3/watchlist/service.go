@@ -0,0 +1,382 @@
+package watchlist
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/service/rpc"
+	"altt/internal/service/web3"
+	"altt/internal/service/web3/approver"
+	"altt/internal/service/web3/heads"
+	"altt/internal/utils"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrWatchNotFound     = errors.New("watch not found")
+	ErrChainNotAvailable = errors.New("chain is not available")
+	ErrInvalidThreshold  = errors.New("threshold is required for below and above conditions")
+	ErrInvalidWebhookURL = errors.New("webhook url must be absolute https url of public host")
+)
+
+// Service evaluates registered watches on every new head of their chain and fires webhooks for matched ones.
+type Service struct {
+	log     logger.AppLogger
+	erc20   *approver.Service
+	heads   *heads.Service
+	repo    Repository
+	webhook *webhookSender
+
+	mu      sync.Mutex
+	watches map[string]*watchState
+	chains  map[entities.Chain]func()
+	ctx     context.Context
+	cancel  context.CancelFunc
+	// webhookDone is closed when webhook sender has drained its queue
+	webhookDone chan struct{}
+}
+
+type watchState struct {
+	watch     *entities.Watch
+	threshold decimal.Decimal
+	// last is balance seen on previous evaluation, it is persisted, so conditions do not fire again after restart
+	last *entities.Balance
+	// seed is set for persisted watches without saved balance, their first evaluation only records balance
+	seed bool
+}
+
+type Config struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func NewService(log logger.AppLogger, erc20 *approver.Service, headsService *heads.Service, repo Repository, conf Config) *Service {
+	log = log.With(zap.String("service", "watchlist"))
+	return &Service{
+		log:     log,
+		erc20:   erc20,
+		heads:   headsService,
+		repo:    repo,
+		webhook: newWebhookSender(log, repo, conf.MaxAttempts, conf.InitialBackoff, conf.MaxBackoff),
+		watches: make(map[string]*watchState),
+		chains:  make(map[entities.Chain]func()),
+	}
+}
+
+// Start loads persisted watches with their last balances and dead letters, starts evaluation and webhook delivery
+func (s *Service) Start() error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.webhookDone = make(chan struct{})
+	go func(ctx context.Context, done chan struct{}) {
+		s.webhook.run(ctx)
+		close(done)
+	}(s.ctx, s.webhookDone)
+	for _, state := range s.watches {
+		s.followChain(state.watch.Chain)
+	}
+	s.log.Info("watchlist started", zap.Int("watches", len(s.watches)))
+	return nil
+}
+
+// load restores watches, balances seen before restart and dead letters
+func (s *Service) load() error {
+	watches, err := s.repo.LoadWatches()
+	if err != nil {
+		return err
+	}
+	balances, err := s.repo.LoadWatchBalances()
+	if err != nil {
+		return err
+	}
+	if err = s.webhook.loadDeadLetters(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, watch := range watches {
+		state, errState := newWatchState(watch)
+		if errState != nil {
+			s.log.Error("skip invalid persisted watch", errState, zap.String("watch_id", watch.ID))
+			continue
+		}
+		state.last = balances[watch.ID]
+		state.seed = state.last == nil
+		s.watches[watch.ID] = state
+	}
+	return nil
+}
+
+// Stop stops evaluation of watches, pending webhooks are moved to dead-letter list
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	for chain, unsubscribe := range s.chains {
+		unsubscribe()
+		delete(s.chains, chain)
+	}
+	done := s.webhookDone
+	s.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// AddWatch validates and registers new watch. Secret for webhook signature is generated if not set.
+func (s *Service) AddWatch(watch *entities.Watch) (*entities.Watch, error) {
+	if !rpc.ChainAvailable(watch.Chain) {
+		return nil, ErrChainNotAvailable
+	}
+	if _, err := entities.WatchConditionFromString(string(watch.Condition)); err != nil {
+		return nil, err
+	}
+	if watch.Token == entities.MapChainToFuel(watch.Chain) {
+		watch.Token = ""
+	}
+	if watch.Token != "" {
+		if _, err := entities.GetTokenAddress(watch.Chain, watch.Token); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := utils.ValidatePublicURL(watch.WebhookURL); err != nil {
+		return nil, ErrInvalidWebhookURL
+	}
+	watch.ID = randomHex(16)
+	watch.ChainName = watch.Chain.String()
+	watch.CreatedAt = time.Now().Unix()
+	if watch.Secret == "" {
+		watch.Secret = randomHex(32)
+	}
+	state, err := newWatchState(watch)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watches[watch.ID] = state
+	if err = s.persist(); err != nil {
+		delete(s.watches, watch.ID)
+		return nil, err
+	}
+	s.followChain(watch.Chain)
+	return watch, nil
+}
+
+// GetWatches returns all watches without secrets ordered by creation time
+func (s *Service) GetWatches() []*entities.Watch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := make([]*entities.Watch, 0, len(s.watches))
+	for _, state := range s.watches {
+		result = append(result, withoutSecret(state.watch))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CreatedAt == result[j].CreatedAt {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].CreatedAt < result[j].CreatedAt
+	})
+	return result
+}
+
+// GetWatch returns watch without secret
+func (s *Service) GetWatch(id string) (*entities.Watch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.watches[id]
+	if !ok {
+		return nil, ErrWatchNotFound
+	}
+	return withoutSecret(state.watch), nil
+}
+
+// DeleteWatch removes watch, chain stops being followed when it has no watches left
+func (s *Service) DeleteWatch(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.watches[id]
+	if !ok {
+		return ErrWatchNotFound
+	}
+	delete(s.watches, id)
+	if err := s.persist(); err != nil {
+		s.watches[id] = state
+		return err
+	}
+	for _, other := range s.watches {
+		if other.watch.Chain == state.watch.Chain {
+			return nil
+		}
+	}
+	if unsubscribe, ok := s.chains[state.watch.Chain]; ok {
+		unsubscribe()
+		delete(s.chains, state.watch.Chain)
+	}
+	return nil
+}
+
+// GetDeadLetters returns webhooks which were not delivered after all retries
+func (s *Service) GetDeadLetters() []*entities.DeadLetter {
+	return s.webhook.getDeadLetters()
+}
+
+// persist saves watch definitions, must be called under lock
+func (s *Service) persist() error {
+	watches := make([]*entities.Watch, 0, len(s.watches))
+	for _, state := range s.watches {
+		watches = append(watches, state.watch)
+	}
+	if err := s.repo.SaveWatches(watches); err != nil {
+		return fmt.Errorf("unable to persist watches: %w", err)
+	}
+	return nil
+}
+
+// followChain starts evaluation on heads of chain if not started yet, must be called under lock
+func (s *Service) followChain(chain entities.Chain) {
+	if _, ok := s.chains[chain]; ok || s.ctx == nil {
+		return
+	}
+	headsCh, unsubscribe := s.heads.Subscribe(chain)
+	s.chains[chain] = unsubscribe
+	go func() {
+		for head := range headsCh {
+			s.evaluateChain(chain, head)
+		}
+	}()
+}
+
+func (s *Service) evaluateChain(chain entities.Chain, head *types.Header) {
+	s.mu.Lock()
+	states := make([]*watchState, 0, len(s.watches))
+	for _, state := range s.watches {
+		if state.watch.Chain == chain {
+			states = append(states, state)
+		}
+	}
+	s.mu.Unlock()
+	if len(states) == 0 {
+		return
+	}
+
+	client, err := web3.GetWeb3Client(chain)
+	if err != nil {
+		s.log.Error("unable to get web3 client", err, zap.String("chain", chain.String()))
+		return
+	}
+	defer client.Close()
+	for _, state := range states {
+		balance, errBalance := s.erc20.GetBalance(s.ctx, client, chain, state.watch.Token, common.HexToAddress(state.watch.Address), entities.BlockTagLatest)
+		if errBalance != nil {
+			s.log.Error("unable to read watched balance", errBalance, zap.String("watch_id", state.watch.ID))
+			continue
+		}
+		s.evaluate(state, balance, head.Number.Uint64())
+	}
+}
+
+func (s *Service) evaluate(state *watchState, balance *entities.Balance, blockNumber uint64) {
+	s.mu.Lock()
+	if _, ok := s.watches[state.watch.ID]; !ok {
+		s.mu.Unlock()
+		return
+	}
+	prev := state.last
+	changed := prev == nil || prev.TokenBalanceWei != balance.TokenBalanceWei
+	if prev == nil && state.seed {
+		prev = balance
+	}
+	state.last, state.seed = balance, false
+	s.mu.Unlock()
+
+	if changed {
+		if err := s.repo.SaveWatchBalance(state.watch.ID, balance); err != nil {
+			s.log.Error("unable to persist watch balance", err, zap.String("watch_id", state.watch.ID))
+		}
+	}
+	if !state.matches(prev, balance) {
+		return
+	}
+	event := &entities.WatchEvent{
+		ID:          randomHex(16),
+		WatchID:     state.watch.ID,
+		Condition:   state.watch.Condition,
+		Threshold:   state.watch.Threshold,
+		BlockNumber: blockNumber,
+		Balance:     balance,
+		TriggeredAt: time.Now().Unix(),
+	}
+	if prev != nil {
+		event.PreviousBalance = prev.TokenBalance
+	}
+	s.webhook.enqueue(&delivery{
+		url:    state.watch.WebhookURL,
+		secret: state.watch.Secret,
+		event:  event,
+	})
+}
+
+func newWatchState(watch *entities.Watch) (*watchState, error) {
+	state := &watchState{watch: watch}
+	if watch.Condition == entities.WatchConditionAnyChange {
+		return state, nil
+	}
+	threshold, err := decimal.NewFromString(watch.Threshold)
+	if err != nil {
+		return nil, ErrInvalidThreshold
+	}
+	state.threshold = threshold
+	return state, nil
+}
+
+// matches reports whether watch fires for balance change. Below and above fire when balance crosses threshold
+// (or is already beyond it on first evaluation), so webhook is not repeated on every block while condition holds.
+func (w *watchState) matches(prev, current *entities.Balance) bool {
+	if w.watch.Condition == entities.WatchConditionAnyChange {
+		return prev != nil && prev.TokenBalanceWei != current.TokenBalanceWei
+	}
+	holds := func(balance *entities.Balance) bool {
+		value, err := decimal.NewFromString(balance.TokenBalance)
+		if err != nil {
+			return false
+		}
+		if w.watch.Condition == entities.WatchConditionBelow {
+			return value.LessThan(w.threshold)
+		}
+		return value.GreaterThan(w.threshold)
+	}
+	return holds(current) && (prev == nil || !holds(prev))
+}
+
+func withoutSecret(watch *entities.Watch) *entities.Watch {
+	res := *watch
+	res.Secret = ""
+	return &res
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("unable to read random bytes: %s", err))
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,50 @@
+package watchlist
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Restart(t *testing.T) {
+	// given
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	balance := func(value string) *entities.Balance {
+		return &entities.Balance{TokenBalance: value, TokenBalanceWei: value}
+	}
+	repo := newRepository(t)
+	require.NoError(t, repo.SaveWatches([]*entities.Watch{
+		{ID: "evaluated", Chain: entities.ChainEthereum, Condition: entities.WatchConditionAbove, Threshold: "1", WebhookURL: "https://127.0.0.1/hook"},
+		{ID: "not evaluated", Chain: entities.ChainEthereum, Condition: entities.WatchConditionAbove, Threshold: "1", WebhookURL: "https://127.0.0.1/hook"},
+	}))
+	require.NoError(t, repo.SaveWatchBalance("evaluated", balance("2")))
+	srv := NewService(appLog, nil, nil, repo, Config{})
+	require.NoError(t, srv.load())
+
+	// when
+	srv.evaluate(srv.watches["evaluated"], balance("2"), 10)
+	srv.evaluate(srv.watches["not evaluated"], balance("2"), 10)
+	srv.evaluate(srv.watches["evaluated"], balance("0.5"), 11)
+	srv.evaluate(srv.watches["evaluated"], balance("3"), 12)
+	srv.webhook.drain()
+
+	// then
+	letters := srv.GetDeadLetters()
+	require.Len(t, letters, 1)
+	require.Equal(t, "evaluated", letters[0].Event.WatchID)
+	require.Equal(t, "3", letters[0].Event.Balance.TokenBalance)
+	require.Equal(t, "0.5", letters[0].Event.PreviousBalance)
+
+	balances, err := repo.LoadWatchBalances()
+	require.NoError(t, err)
+	require.Equal(t, "3", balances["evaluated"].TokenBalance)
+	require.Equal(t, "2", balances["not evaluated"].TokenBalance)
+
+	restarted := NewService(appLog, nil, nil, repo, Config{})
+	require.NoError(t, restarted.load())
+	require.Len(t, restarted.GetDeadLetters(), 1)
+	require.Equal(t, "3", restarted.watches["evaluated"].last.TokenBalance)
+}
@@ -0,0 +1,245 @@
+package watchlist
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/utils"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderSignature = "X-Altt-Signature"
+	HeaderTimestamp = "X-Altt-Timestamp"
+	HeaderEventID   = "X-Altt-Event-Id"
+
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = time.Minute
+	deliveryTimeout       = 10 * time.Second
+	deliveryQueueSize     = 1024
+	deliveryWorkers       = 4
+	maxDeadLetters        = 1000
+)
+
+type delivery struct {
+	url    string
+	secret string
+	event  *entities.WatchEvent
+}
+
+// deadLetterStore persists dead letters, so they survive restart
+type deadLetterStore interface {
+	LoadDeadLetters() ([]*entities.DeadLetter, error)
+	AddDeadLetter(letter *entities.DeadLetter, limit int) error
+}
+
+// webhookSender delivers events with retries and exponential backoff.
+// Events which were not delivered after all attempts are kept in dead-letter list.
+type webhookSender struct {
+	log            logger.AppLogger
+	client         *http.Client
+	store          deadLetterStore
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	queue          chan *delivery
+
+	// stopped is set when queue is drained, later events go straight to dead-letter list
+	queueMU sync.Mutex
+	stopped bool
+
+	deadMU      sync.Mutex
+	deadLetters []*entities.DeadLetter
+}
+
+func newWebhookSender(log logger.AppLogger, store deadLetterStore, maxAttempts int, initialBackoff, maxBackoff time.Duration) *webhookSender {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	if initialBackoff <= 0 {
+		initialBackoff = defaultInitialBackoff
+	}
+	if maxBackoff < initialBackoff {
+		maxBackoff = defaultMaxBackoff
+	}
+	return &webhookSender{
+		log:            log,
+		client:         utils.NewPublicHTTPClient(deliveryTimeout),
+		store:          store,
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		queue:          make(chan *delivery, deliveryQueueSize),
+	}
+}
+
+func (w *webhookSender) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(deliveryWorkers)
+	for i := 0; i < deliveryWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-w.queue:
+					w.deliver(ctx, d)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	w.drain()
+}
+
+// loadDeadLetters restores dead letters saved before restart
+func (w *webhookSender) loadDeadLetters() error {
+	letters, err := w.store.LoadDeadLetters()
+	if err != nil {
+		return err
+	}
+	w.deadMU.Lock()
+	defer w.deadMU.Unlock()
+	w.deadLetters = letters
+	return nil
+}
+
+// enqueue schedules delivery, if queue is full or sender is stopped event goes straight to dead-letter list
+func (w *webhookSender) enqueue(d *delivery) {
+	w.queueMU.Lock()
+	defer w.queueMU.Unlock()
+	if w.stopped {
+		w.addDeadLetter(d, 0, "watchlist is stopped")
+		return
+	}
+	select {
+	case w.queue <- d:
+	default:
+		w.addDeadLetter(d, 0, "delivery queue is full")
+	}
+}
+
+// drain moves deliveries which are still queued after stop to dead-letter list
+func (w *webhookSender) drain() {
+	w.queueMU.Lock()
+	defer w.queueMU.Unlock()
+	w.stopped = true
+	for {
+		select {
+		case d := <-w.queue:
+			w.addDeadLetter(d, 0, "watchlist is stopped")
+		default:
+			return
+		}
+	}
+}
+
+func (w *webhookSender) deliver(ctx context.Context, d *delivery) {
+	body, err := json.Marshal(d.event)
+	if err != nil {
+		w.addDeadLetter(d, 0, err.Error())
+		return
+	}
+	backoff := w.initialBackoff
+	for attempt := 1; ; attempt++ {
+		err = w.send(ctx, d, body)
+		if err == nil {
+			return
+		}
+		w.log.Info("webhook delivery failed",
+			zap.String("watch_id", d.event.WatchID),
+			zap.String("event_id", d.event.ID),
+			zap.Int("attempt", attempt),
+			zap.String("reason", err.Error()),
+		)
+		if attempt >= w.maxAttempts {
+			w.addDeadLetter(d, attempt, err.Error())
+			return
+		}
+		select {
+		case <-ctx.Done():
+			w.addDeadLetter(d, attempt, ctx.Err().Error())
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > w.maxBackoff {
+			backoff = w.maxBackoff
+		}
+	}
+}
+
+func (w *webhookSender) send(ctx context.Context, d *delivery, body []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderEventID, d.event.ID)
+	req.Header.Set(HeaderSignature, Sign(d.secret, timestamp, body))
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return nil
+}
+
+func (w *webhookSender) addDeadLetter(d *delivery, attempts int, reason string) {
+	w.log.Error("webhook moved to dead-letter list", fmt.Errorf("%s", reason),
+		zap.String("watch_id", d.event.WatchID),
+		zap.String("event_id", d.event.ID),
+	)
+	letter := &entities.DeadLetter{
+		Event:      d.event,
+		WebhookURL: d.url,
+		Attempts:   attempts,
+		LastError:  reason,
+		FailedAt:   time.Now().Unix(),
+	}
+	if err := w.store.AddDeadLetter(letter, maxDeadLetters); err != nil {
+		w.log.Error("unable to persist dead letter", err, zap.String("event_id", d.event.ID))
+	}
+	w.deadMU.Lock()
+	defer w.deadMU.Unlock()
+	w.deadLetters = append(w.deadLetters, letter)
+	if len(w.deadLetters) > maxDeadLetters {
+		w.deadLetters = w.deadLetters[len(w.deadLetters)-maxDeadLetters:]
+	}
+}
+
+func (w *webhookSender) getDeadLetters() []*entities.DeadLetter {
+	w.deadMU.Lock()
+	defer w.deadMU.Unlock()
+	result := make([]*entities.DeadLetter, len(w.deadLetters))
+	copy(result, w.deadLetters)
+	return result
+}
+
+// Sign returns value of signature header: hex encoded HMAC-SHA256 of `timestamp.body` with watch secret.
+// Receiver should recompute it and compare with hmac.Equal, also rejecting stale timestamps to prevent replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
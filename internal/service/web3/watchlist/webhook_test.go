@@ -0,0 +1,112 @@
+package watchlist
+
+import (
+	"altt/internal/entities"
+	"altt/internal/logger"
+	"altt/internal/storage"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newRepository(t *testing.T) *storage.Repository {
+	store, err := storage.Open("")
+	require.NoError(t, err)
+	return storage.NewRepository(store)
+}
+
+func TestWebhookSender_Retry(t *testing.T) {
+	// given
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	var calls int32
+	received := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, errRead := io.ReadAll(r.Body)
+		require.NoError(t, errRead)
+		require.Equal(t, Sign("secret", r.Header.Get(HeaderTimestamp), body), r.Header.Get(HeaderSignature))
+		require.Equal(t, "event", r.Header.Get(HeaderEventID))
+		close(received)
+	}))
+	defer srv.Close()
+	sender := newWebhookSender(appLog, newRepository(t), 3, 10*time.Millisecond, 20*time.Millisecond)
+	sender.client = srv.Client()
+
+	// when
+	sender.deliver(context.Background(), &delivery{
+		url:    srv.URL,
+		secret: "secret",
+		event:  &entities.WatchEvent{ID: "event", WatchID: "watch"},
+	})
+
+	// then
+	<-received
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	require.Empty(t, sender.getDeadLetters())
+}
+
+func TestWebhookSender_DeadLetter(t *testing.T) {
+	// given
+	appLog, err := logger.NewAppLogger("test")
+	require.NoError(t, err)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	sender := newWebhookSender(appLog, newRepository(t), 2, 10*time.Millisecond, 20*time.Millisecond)
+	sender.client = srv.Client()
+
+	// when
+	sender.deliver(context.Background(), &delivery{
+		url:    srv.URL,
+		secret: "secret",
+		event:  &entities.WatchEvent{ID: "event", WatchID: "watch"},
+	})
+
+	// then
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	deadLetters := sender.getDeadLetters()
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, 2, deadLetters[0].Attempts)
+	require.Equal(t, "event", deadLetters[0].Event.ID)
+}
+
+func TestWatchState_Matches(t *testing.T) {
+	balance := func(value string) *entities.Balance {
+		return &entities.Balance{TokenBalance: value, TokenBalanceWei: value}
+	}
+	table := []struct {
+		name      string
+		condition entities.WatchCondition
+		prev      *entities.Balance
+		current   *entities.Balance
+		expected  bool
+	}{
+		{name: "below on first check", condition: entities.WatchConditionBelow, current: balance("0.5"), expected: true},
+		{name: "below crossed", condition: entities.WatchConditionBelow, prev: balance("2"), current: balance("0.5"), expected: true},
+		{name: "below still holds", condition: entities.WatchConditionBelow, prev: balance("0.7"), current: balance("0.5"), expected: false},
+		{name: "below not reached", condition: entities.WatchConditionBelow, prev: balance("3"), current: balance("2"), expected: false},
+		{name: "above crossed", condition: entities.WatchConditionAbove, prev: balance("0.5"), current: balance("2"), expected: true},
+		{name: "above still holds", condition: entities.WatchConditionAbove, prev: balance("3"), current: balance("2"), expected: false},
+		{name: "any change first check", condition: entities.WatchConditionAnyChange, current: balance("2"), expected: false},
+		{name: "any change", condition: entities.WatchConditionAnyChange, prev: balance("1"), current: balance("2"), expected: true},
+		{name: "no change", condition: entities.WatchConditionAnyChange, prev: balance("2"), current: balance("2"), expected: false},
+	}
+	for _, tc := range table {
+		state, err := newWatchState(&entities.Watch{Condition: tc.condition, Threshold: "1"})
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, state.matches(tc.prev, tc.current), tc.name)
+	}
+}
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const openTimeout = 5 * time.Second
+
+// BoltStore keeps data in single bbolt file, file is locked while store is open.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(filepath.Clean(path), 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open storage file: %w", err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) View(fn func(tx Tx) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (s *BoltStore) Update(fn func(tx Tx) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t *boltTx) CreateBucket(bucket string) error {
+	if !t.tx.Writable() {
+		return ErrTxReadOnly
+	}
+	_, err := t.tx.CreateBucketIfNotExists([]byte(bucket))
+	return err
+}
+
+func (t *boltTx) DeleteBucket(bucket string) error {
+	if !t.tx.Writable() {
+		return ErrTxReadOnly
+	}
+	if err := t.tx.DeleteBucket([]byte(bucket)); err != nil && err != bolt.ErrBucketNotFound {
+		return err
+	}
+	return nil
+}
+
+func (t *boltTx) Get(bucket, key string) ([]byte, error) {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	value := b.Get([]byte(key))
+	if value == nil {
+		return nil, nil
+	}
+	// bbolt values are valid only until end of transaction
+	return append([]byte(nil), value...), nil
+}
+
+func (t *boltTx) Put(bucket, key string, value []byte) error {
+	if !t.tx.Writable() {
+		return ErrTxReadOnly
+	}
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(key), value)
+}
+
+func (t *boltTx) Delete(bucket, key string) error {
+	if !t.tx.Writable() {
+		return ErrTxReadOnly
+	}
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	return b.Delete([]byte(key))
+}
+
+func (t *boltTx) ForEach(bucket string, fn func(key string, value []byte) error) error {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	return b.ForEach(func(k, v []byte) error {
+		return fn(string(k), append([]byte(nil), v...))
+	})
+}
+
+func (t *boltTx) bucket(bucket string) (*bolt.Bucket, error) {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBucketNotFound, bucket)
+	}
+	return b, nil
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps data in memory only, it is used in tests and when storage path is not configured.
+// Update works on copy of data which replaces current one only when fn succeeds.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]map[string][]byte)}
+}
+
+func (s *MemoryStore) View(fn func(tx Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&memoryTx{buckets: s.buckets})
+}
+
+func (s *MemoryStore) Update(fn func(tx Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buckets := make(map[string]map[string][]byte, len(s.buckets))
+	for name, bucket := range s.buckets {
+		buckets[name] = make(map[string][]byte, len(bucket))
+		for key, value := range bucket {
+			buckets[name][key] = value
+		}
+	}
+	if err := fn(&memoryTx{buckets: buckets, writable: true}); err != nil {
+		return err
+	}
+	s.buckets = buckets
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+type memoryTx struct {
+	buckets  map[string]map[string][]byte
+	writable bool
+}
+
+func (t *memoryTx) CreateBucket(bucket string) error {
+	if !t.writable {
+		return ErrTxReadOnly
+	}
+	if _, ok := t.buckets[bucket]; !ok {
+		t.buckets[bucket] = make(map[string][]byte)
+	}
+	return nil
+}
+
+func (t *memoryTx) DeleteBucket(bucket string) error {
+	if !t.writable {
+		return ErrTxReadOnly
+	}
+	delete(t.buckets, bucket)
+	return nil
+}
+
+func (t *memoryTx) Get(bucket, key string) ([]byte, error) {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := b[key]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte(nil), value...), nil
+}
+
+func (t *memoryTx) Put(bucket, key string, value []byte) error {
+	if !t.writable {
+		return ErrTxReadOnly
+	}
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	b[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (t *memoryTx) Delete(bucket, key string) error {
+	if !t.writable {
+		return ErrTxReadOnly
+	}
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	delete(b, key)
+	return nil
+}
+
+func (t *memoryTx) ForEach(bucket string, fn func(key string, value []byte) error) error {
+	b, err := t.bucket(bucket)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(b))
+	for key := range b {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err = fn(key, append([]byte(nil), b[key]...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *memoryTx) bucket(bucket string) (map[string][]byte, error) {
+	b, ok := t.buckets[bucket]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrBucketNotFound, bucket)
+	}
+	return b, nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	bucketMeta     = "meta"
+	bucketBalances = "balances"
+	bucketTokens   = "tokens"
+	bucketIndexer  = "indexer"
+	bucketWatches  = "watches"
+	// bucketWatchBalances is last evaluated balance of watch, bucketDeadLetters is webhooks which were not delivered
+	bucketWatchBalances = "watch_balances"
+	bucketDeadLetters   = "dead_letters"
+	// bucketIndexerBalances is indexed balances by chain, bucketIndexer keeps cursor of chain only
+	bucketIndexerBalances = "indexer_balances"
+
+	keySchemaVersion = "schema_version"
+)
+
+// migration moves schema from version-1 to version. Every migration runs in own transaction together with version bump,
+// so failed migration leaves store at previous version.
+type migration struct {
+	version int
+	name    string
+	up      func(tx Tx) error
+}
+
+// migrations must be appended only, applied migrations are never changed
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create buckets",
+		up: func(tx Tx) error {
+			buckets := []string{
+				bucketBalances, bucketTokens, bucketIndexer, bucketIndexerBalances, bucketWatches, bucketWatchBalances,
+				bucketDeadLetters,
+			}
+			for _, bucket := range buckets {
+				if err := tx.CreateBucket(bucket); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+}
+
+func migrate(store Store, list []migration) error {
+	var current int
+	err := store.Update(func(tx Tx) error {
+		if err := tx.CreateBucket(bucketMeta); err != nil {
+			return err
+		}
+		var errVersion error
+		current, errVersion = schemaVersion(tx)
+		return errVersion
+	})
+	if err != nil {
+		return fmt.Errorf("unable to read schema version: %w", err)
+	}
+	if latest := len(list); current > latest {
+		return fmt.Errorf("%w: %d > %d", ErrSchemaTooNew, current, latest)
+	}
+	for _, m := range list[current:] {
+		m := m
+		err = store.Update(func(tx Tx) error {
+			if errUp := m.up(tx); errUp != nil {
+				return errUp
+			}
+			return tx.Put(bucketMeta, keySchemaVersion, []byte(strconv.Itoa(m.version)))
+		})
+		if err != nil {
+			return fmt.Errorf("unable to apply migration %d %q: %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
+
+func schemaVersion(tx Tx) (int, error) {
+	raw, err := tx.Get(bucketMeta, keySchemaVersion)
+	if err != nil || raw == nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(raw))
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	applied := make([]int, 0)
+	step := func(version int) migration {
+		return migration{version: version, name: "test", up: func(tx Tx) error {
+			applied = append(applied, version)
+			return nil
+		}}
+	}
+	version := func(t *testing.T, store Store) int {
+		var current int
+		require.NoError(t, store.View(func(tx Tx) (err error) {
+			current, err = schemaVersion(tx)
+			return err
+		}))
+		return current
+	}
+
+	t.Run("pending migrations are applied once", func(t *testing.T) {
+		// given
+		applied = applied[:0]
+		store := NewMemoryStore()
+		require.NoError(t, migrate(store, []migration{step(1)}))
+
+		// when
+		require.NoError(t, migrate(store, []migration{step(1), step(2), step(3)}))
+
+		// then
+		require.Equal(t, []int{1, 2, 3}, applied)
+		require.Equal(t, 3, version(t, store))
+	})
+
+	t.Run("failed migration keeps previous version", func(t *testing.T) {
+		// given
+		store := NewMemoryStore()
+		failing := migration{version: 2, name: "failing", up: func(tx Tx) error {
+			if err := tx.CreateBucket("partial"); err != nil {
+				return err
+			}
+			return errors.New("fail")
+		}}
+
+		// when
+		err := migrate(store, []migration{step(1), failing})
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, 1, version(t, store))
+		require.ErrorIs(t, store.View(func(tx Tx) error {
+			_, errGet := tx.Get("partial", "k")
+			return errGet
+		}), ErrBucketNotFound)
+	})
+
+	t.Run("newer schema is rejected", func(t *testing.T) {
+		store := NewMemoryStore()
+		require.NoError(t, migrate(store, []migration{step(1), step(2)}))
+		require.ErrorIs(t, migrate(store, []migration{step(1)}), ErrSchemaTooNew)
+	})
+}
@@ -0,0 +1,361 @@
+package storage
+
+import (
+	"altt/internal/entities"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Repository stores service state in typed form on top of Store, values are encoded as json.
+type Repository struct {
+	store Store
+}
+
+// IndexerState is indexed balances of chain at Cursor block. Cursor is stored apart from balances, so saved state may
+// hold changed balances only.
+type IndexerState struct {
+	Chain         entities.Chain   `json:"chain"`
+	Cursor        uint64           `json:"cursor"`
+	IndexedAt     time.Time        `json:"indexed_at"`
+	LastReconcile time.Time        `json:"last_reconcile"`
+	Balances      []IndexedBalance `json:"balances,omitempty"`
+}
+
+// IndexedBalance is balance of holder read at Block, nil value means balance is tracked but not read yet
+type IndexedBalance struct {
+	Token  common.Address `json:"token"`
+	Holder common.Address `json:"holder"`
+	Value  *big.Int       `json:"value"`
+	Block  uint64         `json:"block"`
+}
+
+func NewRepository(store Store) *Repository {
+	return &Repository{store: store}
+}
+
+func (r *Repository) Close() error {
+	return r.store.Close()
+}
+
+// GetBalanceSnapshot returns nil if there is no snapshot for key
+func (r *Repository) GetBalanceSnapshot(key string) (*entities.BalanceSnapshot, error) {
+	var snapshot *entities.BalanceSnapshot
+	err := r.store.View(func(tx Tx) error {
+		return get(tx, bucketBalances, key, &snapshot)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get balance snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// SaveBalanceSnapshots saves batch of snapshots in one transaction and deletes snapshots updated before expiredBefore,
+// as they are not reused anymore. Returns number of deleted snapshots.
+func (r *Repository) SaveBalanceSnapshots(snapshots map[string]*entities.BalanceSnapshot, expiredBefore time.Time) (int, error) {
+	var pruned int
+	err := r.store.Update(func(tx Tx) error {
+		for key, snapshot := range snapshots {
+			if err := put(tx, bucketBalances, key, snapshot); err != nil {
+				return err
+			}
+		}
+		expired := make([]string, 0)
+		err := tx.ForEach(bucketBalances, func(key string, value []byte) error {
+			var snapshot entities.BalanceSnapshot
+			if errDecode := json.Unmarshal(value, &snapshot); errDecode != nil || snapshot.UpdatedAt.Before(expiredBefore) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err = tx.Delete(bucketBalances, key); err != nil {
+				return err
+			}
+		}
+		pruned = len(expired)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to save balance snapshots: %w", err)
+	}
+	return pruned, nil
+}
+
+// GetTokenMetadata returns nil if metadata of token was not saved yet
+func (r *Repository) GetTokenMetadata(chain entities.Chain, token common.Address) (*entities.TokenMetadata, error) {
+	var metadata *entities.TokenMetadata
+	err := r.store.View(func(tx Tx) error {
+		return get(tx, bucketTokens, tokenKey(chain, token), &metadata)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get token metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func (r *Repository) SaveTokenMetadata(metadata *entities.TokenMetadata) error {
+	err := r.store.Update(func(tx Tx) error {
+		return put(tx, bucketTokens, tokenKey(metadata.Chain, common.HexToAddress(metadata.Address)), metadata)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save token metadata: %w", err)
+	}
+	return nil
+}
+
+// LoadIndexerStates returns saved cursors of chains with all their indexed balances
+func (r *Repository) LoadIndexerStates() ([]*IndexerState, error) {
+	states := make([]*IndexerState, 0)
+	err := r.store.View(func(tx Tx) error {
+		byChain := make(map[string]*IndexerState)
+		err := tx.ForEach(bucketIndexer, func(key string, value []byte) error {
+			var state IndexerState
+			if err := json.Unmarshal(value, &state); err != nil {
+				return fmt.Errorf("unable to decode indexer state %s: %w", key, err)
+			}
+			states = append(states, &state)
+			byChain[state.Chain.String()] = &state
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return tx.ForEach(bucketIndexerBalances, func(key string, value []byte) error {
+			chain, _, _ := strings.Cut(key, "/")
+			state, ok := byChain[chain]
+			if !ok {
+				return nil
+			}
+			var balance IndexedBalance
+			if err = json.Unmarshal(value, &balance); err != nil {
+				return fmt.Errorf("unable to decode indexed balance %s: %w", key, err)
+			}
+			state.Balances = append(state.Balances, balance)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load indexer states: %w", err)
+	}
+	return states, nil
+}
+
+// SaveIndexerDelta saves cursor of chain with balances changed since previous save. Balances in state are written over
+// stored ones, removed balances are deleted and other stored balances are kept.
+func (r *Repository) SaveIndexerDelta(state *IndexerState, removed []IndexedBalance) error {
+	err := r.store.Update(func(tx Tx) error {
+		for _, balance := range state.Balances {
+			if err := put(tx, bucketIndexerBalances, indexedBalanceKey(state.Chain, balance), balance); err != nil {
+				return err
+			}
+		}
+		for _, balance := range removed {
+			if err := tx.Delete(bucketIndexerBalances, indexedBalanceKey(state.Chain, balance)); err != nil {
+				return err
+			}
+		}
+		cursor := *state
+		cursor.Balances = nil
+		return put(tx, bucketIndexer, state.Chain.String(), &cursor)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save indexer state: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) LoadWatches() ([]*entities.Watch, error) {
+	watches := make([]*entities.Watch, 0)
+	err := r.store.View(func(tx Tx) error {
+		return tx.ForEach(bucketWatches, func(key string, value []byte) error {
+			var watch entities.Watch
+			if err := json.Unmarshal(value, &watch); err != nil {
+				return fmt.Errorf("unable to decode watch %s: %w", key, err)
+			}
+			watches = append(watches, &watch)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load watches: %w", err)
+	}
+	return watches, nil
+}
+
+// SaveWatches replaces all stored watches, last balances of removed watches are dropped
+func (r *Repository) SaveWatches(watches []*entities.Watch) error {
+	err := r.store.Update(func(tx Tx) error {
+		if err := tx.DeleteBucket(bucketWatches); err != nil {
+			return err
+		}
+		if err := tx.CreateBucket(bucketWatches); err != nil {
+			return err
+		}
+		ids := make(map[string]struct{}, len(watches))
+		for _, watch := range watches {
+			ids[watch.ID] = struct{}{}
+			if err := put(tx, bucketWatches, watch.ID, watch); err != nil {
+				return err
+			}
+		}
+		removed := make([]string, 0)
+		err := tx.ForEach(bucketWatchBalances, func(key string, _ []byte) error {
+			if _, ok := ids[key]; !ok {
+				removed = append(removed, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range removed {
+			if err = tx.Delete(bucketWatchBalances, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save watches: %w", err)
+	}
+	return nil
+}
+
+// ImportWatches copies watches from source when storage has none, so definitions kept by previous versions survive upgrade.
+// Returns number of imported watches.
+func (r *Repository) ImportWatches(source interface {
+	LoadWatches() ([]*entities.Watch, error)
+}) (int, error) {
+	stored, err := r.LoadWatches()
+	if err != nil {
+		return 0, err
+	}
+	if len(stored) > 0 {
+		return 0, nil
+	}
+	watches, err := source.LoadWatches()
+	if err != nil {
+		return 0, fmt.Errorf("unable to load watches for import: %w", err)
+	}
+	if len(watches) == 0 {
+		return 0, nil
+	}
+	if err = r.SaveWatches(watches); err != nil {
+		return 0, err
+	}
+	return len(watches), nil
+}
+
+// LoadWatchBalances returns last evaluated balance by watch id
+func (r *Repository) LoadWatchBalances() (map[string]*entities.Balance, error) {
+	balances := make(map[string]*entities.Balance)
+	err := r.store.View(func(tx Tx) error {
+		return tx.ForEach(bucketWatchBalances, func(key string, value []byte) error {
+			var balance entities.Balance
+			if err := json.Unmarshal(value, &balance); err != nil {
+				return fmt.Errorf("unable to decode watch balance %s: %w", key, err)
+			}
+			balances[key] = &balance
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load watch balances: %w", err)
+	}
+	return balances, nil
+}
+
+func (r *Repository) SaveWatchBalance(watchID string, balance *entities.Balance) error {
+	err := r.store.Update(func(tx Tx) error {
+		return put(tx, bucketWatchBalances, watchID, balance)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save watch balance: %w", err)
+	}
+	return nil
+}
+
+// LoadDeadLetters returns dead letters from oldest to newest
+func (r *Repository) LoadDeadLetters() ([]*entities.DeadLetter, error) {
+	letters := make([]*entities.DeadLetter, 0)
+	err := r.store.View(func(tx Tx) error {
+		return tx.ForEach(bucketDeadLetters, func(key string, value []byte) error {
+			var letter entities.DeadLetter
+			if err := json.Unmarshal(value, &letter); err != nil {
+				return fmt.Errorf("unable to decode dead letter %s: %w", key, err)
+			}
+			letters = append(letters, &letter)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to load dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+// AddDeadLetter saves dead letter and drops oldest ones above limit
+func (r *Repository) AddDeadLetter(letter *entities.DeadLetter, limit int) error {
+	err := r.store.Update(func(tx Tx) error {
+		if err := put(tx, bucketDeadLetters, deadLetterKey(letter), letter); err != nil {
+			return err
+		}
+		keys := make([]string, 0)
+		err := tx.ForEach(bucketDeadLetters, func(key string, _ []byte) error {
+			keys = append(keys, key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(keys)-limit; i++ {
+			if err = tx.Delete(bucketDeadLetters, keys[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save dead letter: %w", err)
+	}
+	return nil
+}
+
+// deadLetterKey orders dead letters by failure time
+func deadLetterKey(letter *entities.DeadLetter) string {
+	return fmt.Sprintf("%020d-%s", letter.FailedAt, letter.Event.ID)
+}
+
+// indexedBalanceKey groups balances by chain, chain is taken from key on load
+func indexedBalanceKey(chain entities.Chain, balance IndexedBalance) string {
+	return chain.String() + "/" + strings.ToLower(balance.Token.Hex()) + "/" + strings.ToLower(balance.Holder.Hex())
+}
+
+func tokenKey(chain entities.Chain, token common.Address) string {
+	return chain.String() + "-" + strings.ToLower(token.Hex())
+}
+
+// get decodes value of key into dst, dst is left untouched if key is missing
+func get(tx Tx, bucket, key string, dst interface{}) error {
+	raw, err := tx.Get(bucket, key)
+	if err != nil || raw == nil {
+		return err
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+func put(tx Tx, bucket, key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return tx.Put(bucket, key, raw)
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"altt/internal/entities"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type watchesSource struct {
+	watches []*entities.Watch
+	err     error
+}
+
+func (s *watchesSource) LoadWatches() ([]*entities.Watch, error) {
+	return s.watches, s.err
+}
+
+func TestRepository(t *testing.T) {
+	newRepository := func(t *testing.T) *Repository {
+		store, err := Open("")
+		require.NoError(t, err)
+		return NewRepository(store)
+	}
+
+	t.Run("balance snapshot", func(t *testing.T) {
+		// given
+		repo := newRepository(t)
+		missing, err := repo.GetBalanceSnapshot("key")
+		require.NoError(t, err)
+		require.Nil(t, missing)
+
+		// when
+		updatedAt := time.Now().Truncate(time.Second)
+		_, err = repo.SaveBalanceSnapshots(map[string]*entities.BalanceSnapshot{
+			"key":     {Value: big.NewInt(42), BlockTag: entities.BlockTagLatest, UpdatedAt: updatedAt},
+			"expired": {Value: big.NewInt(1), BlockTag: entities.BlockTagLatest, UpdatedAt: updatedAt.Add(-time.Hour)},
+		}, updatedAt.Add(-time.Minute))
+		require.NoError(t, err)
+
+		// then
+		snapshot, err := repo.GetBalanceSnapshot("key")
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(42), snapshot.Value)
+		require.True(t, updatedAt.Equal(snapshot.UpdatedAt))
+		expired, err := repo.GetBalanceSnapshot("expired")
+		require.NoError(t, err)
+		require.Nil(t, expired)
+	})
+
+	t.Run("expired balance snapshots are pruned", func(t *testing.T) {
+		// given
+		repo := newRepository(t)
+		_, err := repo.SaveBalanceSnapshots(map[string]*entities.BalanceSnapshot{
+			"old": {Value: big.NewInt(1), UpdatedAt: time.Now().Add(-time.Hour)},
+			"new": {Value: big.NewInt(2), UpdatedAt: time.Now()},
+		}, time.Time{})
+		require.NoError(t, err)
+
+		// when
+		pruned, err := repo.SaveBalanceSnapshots(nil, time.Now().Add(-time.Minute))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, pruned)
+		old, err := repo.GetBalanceSnapshot("old")
+		require.NoError(t, err)
+		require.Nil(t, old)
+	})
+
+	t.Run("indexer delta keeps unchanged balances", func(t *testing.T) {
+		// given
+		repo := newRepository(t)
+		token := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+		alice := IndexedBalance{Token: token, Holder: common.HexToAddress("0x01"), Value: big.NewInt(1), Block: 10}
+		bob := IndexedBalance{Token: token, Holder: common.HexToAddress("0x02"), Value: big.NewInt(2), Block: 10}
+		carol := IndexedBalance{Token: token, Holder: common.HexToAddress("0x03")}
+		require.NoError(t, repo.SaveIndexerDelta(&IndexerState{Chain: entities.ChainEthereum, Cursor: 10, Balances: []IndexedBalance{alice, bob}}, nil))
+		require.NoError(t, repo.SaveIndexerDelta(&IndexerState{Chain: entities.ChainPolygon, Cursor: 5, Balances: []IndexedBalance{alice}}, nil))
+
+		// when
+		require.NoError(t, repo.SaveIndexerDelta(&IndexerState{Chain: entities.ChainEthereum, Cursor: 11, Balances: []IndexedBalance{carol}}, []IndexedBalance{bob}))
+
+		// then
+		states, err := repo.LoadIndexerStates()
+		require.NoError(t, err)
+		require.Len(t, states, 2)
+		for _, state := range states {
+			if state.Chain == entities.ChainEthereum {
+				require.Equal(t, uint64(11), state.Cursor)
+				require.ElementsMatch(t, []IndexedBalance{alice, carol}, state.Balances)
+			} else {
+				require.Equal(t, []IndexedBalance{alice}, state.Balances)
+			}
+		}
+	})
+
+	t.Run("token metadata", func(t *testing.T) {
+		repo := newRepository(t)
+		token := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+		require.NoError(t, repo.SaveTokenMetadata(&entities.TokenMetadata{
+			Chain:    entities.ChainEthereum,
+			Address:  token.Hex(),
+			Symbol:   "USDC",
+			Decimals: 6,
+		}))
+		metadata, err := repo.GetTokenMetadata(entities.ChainEthereum, token)
+		require.NoError(t, err)
+		require.Equal(t, "USDC", metadata.Symbol)
+		require.Equal(t, uint8(6), metadata.Decimals)
+	})
+
+	t.Run("watches are replaced", func(t *testing.T) {
+		// given
+		repo := newRepository(t)
+		require.NoError(t, repo.SaveWatches([]*entities.Watch{{ID: "a"}, {ID: "b"}}))
+
+		// when
+		require.NoError(t, repo.SaveWatches([]*entities.Watch{{ID: "c"}}))
+
+		// then
+		watches, err := repo.LoadWatches()
+		require.NoError(t, err)
+		require.Len(t, watches, 1)
+		require.Equal(t, "c", watches[0].ID)
+	})
+
+	t.Run("watches are imported into empty storage only", func(t *testing.T) {
+		// given
+		repo := newRepository(t)
+		source := &watchesSource{watches: []*entities.Watch{{ID: "a"}, {ID: "b"}}}
+
+		// when
+		imported, err := repo.ImportWatches(source)
+		require.NoError(t, err)
+		again, err := repo.ImportWatches(&watchesSource{err: errors.New("must not be read")})
+		require.NoError(t, err)
+
+		// then
+		require.Equal(t, 2, imported)
+		require.Zero(t, again)
+		watches, err := repo.LoadWatches()
+		require.NoError(t, err)
+		require.Len(t, watches, 2)
+	})
+
+	t.Run("watch balances are dropped with watch", func(t *testing.T) {
+		// given
+		repo := newRepository(t)
+		require.NoError(t, repo.SaveWatches([]*entities.Watch{{ID: "a"}, {ID: "b"}}))
+		require.NoError(t, repo.SaveWatchBalance("a", &entities.Balance{TokenBalanceWei: "1"}))
+		require.NoError(t, repo.SaveWatchBalance("b", &entities.Balance{TokenBalanceWei: "2"}))
+
+		// when
+		require.NoError(t, repo.SaveWatches([]*entities.Watch{{ID: "b"}}))
+
+		// then
+		balances, err := repo.LoadWatchBalances()
+		require.NoError(t, err)
+		require.Len(t, balances, 1)
+		require.Equal(t, "2", balances["b"].TokenBalanceWei)
+	})
+
+	t.Run("dead letters keep newest", func(t *testing.T) {
+		// given
+		repo := newRepository(t)
+
+		// when
+		for i, id := range []string{"a", "b", "c"} {
+			require.NoError(t, repo.AddDeadLetter(&entities.DeadLetter{Event: &entities.WatchEvent{ID: id}, FailedAt: int64(100 + i)}, 2))
+		}
+
+		// then
+		letters, err := repo.LoadDeadLetters()
+		require.NoError(t, err)
+		require.Len(t, letters, 2)
+		require.Equal(t, "b", letters[0].Event.ID)
+		require.Equal(t, "c", letters[1].Event.ID)
+	})
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"errors"
+)
+
+var (
+	ErrBucketNotFound = errors.New("bucket not found")
+	ErrTxReadOnly     = errors.New("transaction is read-only")
+	ErrSchemaTooNew   = errors.New("storage schema is newer than supported")
+)
+
+// Store is embedded key-value storage with buckets. Changes made in Update are applied atomically,
+// error returned from fn discards all of them.
+type Store interface {
+	View(fn func(tx Tx) error) error
+	Update(fn func(tx Tx) error) error
+	Close() error
+}
+
+// Tx is view of store inside transaction. Values returned by Get and ForEach are copies and may be retained.
+type Tx interface {
+	CreateBucket(bucket string) error
+	DeleteBucket(bucket string) error
+	// Get returns nil value for missing key
+	Get(bucket, key string) ([]byte, error)
+	Put(bucket, key string, value []byte) error
+	Delete(bucket, key string) error
+	// ForEach calls fn for every key of bucket in key order, bucket must not be modified inside fn
+	ForEach(bucket string, fn func(key string, value []byte) error) error
+}
+
+// Open opens bbolt store at path, empty path opens in-memory store. Pending migrations are applied before store is returned.
+func Open(path string) (Store, error) {
+	var (
+		store Store
+		err   error
+	)
+	if path == "" {
+		store = NewMemoryStore()
+	} else if store, err = NewBoltStore(path); err != nil {
+		return nil, err
+	}
+	if err = migrate(store, migrations); err != nil {
+		_ = store.Close()
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	backends := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store {
+			return NewMemoryStore()
+		},
+		"bolt": func(t *testing.T) Store {
+			store, err := NewBoltStore(filepath.Join(t.TempDir(), "test.db"))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, store.Close())
+			})
+			return store
+		},
+	}
+	for name, open := range backends {
+		open := open
+		t.Run(name, func(t *testing.T) {
+			t.Run("put and get", func(t *testing.T) {
+				// given
+				store := open(t)
+				require.NoError(t, store.Update(func(tx Tx) error {
+					if err := tx.CreateBucket("b"); err != nil {
+						return err
+					}
+					return tx.Put("b", "k", []byte("v"))
+				}))
+
+				// when
+				var value, missing []byte
+				require.NoError(t, store.View(func(tx Tx) (err error) {
+					if value, err = tx.Get("b", "k"); err != nil {
+						return err
+					}
+					missing, err = tx.Get("b", "missing")
+					return err
+				}))
+
+				// then
+				require.Equal(t, []byte("v"), value)
+				require.Nil(t, missing)
+			})
+
+			t.Run("failed update is discarded", func(t *testing.T) {
+				// given
+				store := open(t)
+				require.NoError(t, store.Update(func(tx Tx) error {
+					return tx.CreateBucket("b")
+				}))
+
+				// when
+				errFail := errors.New("fail")
+				err := store.Update(func(tx Tx) error {
+					if err := tx.Put("b", "k", []byte("v")); err != nil {
+						return err
+					}
+					return errFail
+				})
+
+				// then
+				require.ErrorIs(t, err, errFail)
+				require.NoError(t, store.View(func(tx Tx) error {
+					value, errGet := tx.Get("b", "k")
+					require.Nil(t, value)
+					return errGet
+				}))
+			})
+
+			t.Run("view is read-only", func(t *testing.T) {
+				store := open(t)
+				require.NoError(t, store.Update(func(tx Tx) error {
+					return tx.CreateBucket("b")
+				}))
+				err := store.View(func(tx Tx) error {
+					return tx.Put("b", "k", []byte("v"))
+				})
+				require.ErrorIs(t, err, ErrTxReadOnly)
+			})
+
+			t.Run("missing bucket", func(t *testing.T) {
+				store := open(t)
+				err := store.View(func(tx Tx) error {
+					_, errGet := tx.Get("missing", "k")
+					return errGet
+				})
+				require.ErrorIs(t, err, ErrBucketNotFound)
+			})
+
+			t.Run("keys are iterated in order", func(t *testing.T) {
+				// given
+				store := open(t)
+				require.NoError(t, store.Update(func(tx Tx) error {
+					if err := tx.CreateBucket("b"); err != nil {
+						return err
+					}
+					for _, key := range []string{"c", "a", "b"} {
+						if err := tx.Put("b", key, []byte(key)); err != nil {
+							return err
+						}
+					}
+					return tx.Delete("b", "b")
+				}))
+
+				// when
+				keys := make([]string, 0)
+				require.NoError(t, store.View(func(tx Tx) error {
+					return tx.ForEach("b", func(key string, value []byte) error {
+						keys = append(keys, key)
+						return nil
+					})
+				}))
+
+				// then
+				require.Equal(t, []string{"a", "c"}, keys)
+			})
+		})
+	}
+}
+
+func TestBoltStore_Reopen(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "test.db")
+	store, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Update(func(tx Tx) error {
+		return tx.Put(bucketWatches, "k", []byte("v"))
+	}))
+	require.NoError(t, store.Close())
+
+	// when
+	store, err = Open(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	// then
+	require.NoError(t, store.View(func(tx Tx) error {
+		value, errGet := tx.Get(bucketWatches, "k")
+		require.Equal(t, []byte("v"), value)
+		return errGet
+	}))
+}
@@ -5,8 +5,21 @@ import (
 	"altt/internal/entities"
 	"altt/internal/logger"
 	"altt/internal/service/rpc"
+	"altt/internal/service/web3/allowance"
 	"altt/internal/service/web3/approver"
 	"altt/internal/service/web3/balancer"
+	"altt/internal/service/web3/ens"
+	"altt/internal/service/web3/follower"
+	"altt/internal/service/web3/gas"
+	"altt/internal/service/web3/heads"
+	"altt/internal/service/web3/indexer"
+	"altt/internal/service/web3/pricing"
+	"altt/internal/service/web3/streamer"
+	"altt/internal/service/web3/transactions"
+	"altt/internal/service/web3/transfers"
+	"altt/internal/service/web3/verifier"
+	"altt/internal/service/web3/watchlist"
+	"altt/internal/storage"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -16,22 +29,61 @@ type TestContainer struct {
 	Log  logger.AppLogger
 	Conf *config.AppConfig
 
-	ServiceBalancer *balancer.Service
+	ServiceBalancer     *balancer.Service
+	ServiceStreamer     *streamer.Service
+	ServiceWatchlist    *watchlist.Service
+	ServiceTransfers    *transfers.Service
+	ServiceAllowance    *allowance.Service
+	ServiceENS          *ens.Service
+	ServiceGas          *gas.Service
+	ServiceTransactions *transactions.Service
 }
 
 func GetClean(t *testing.T) *TestContainer {
 	conf := getTestConfig()
 	rpc.NewService(conf.ChainRPCs)
+	store, err := storage.Open("")
+	require.NoError(t, err)
+	repository := storage.NewRepository(store)
 
 	appLog, err := logger.NewAppLogger("test")
 	require.NoError(t, err)
 
-	serviceBalancer := balancer.NewService(appLog, approver.InitService(appLog), conf.DisableMetrics)
+	serviceGas, err := gas.NewService(appLog, conf.Gas)
+	require.NoError(t, err)
+	serviceApprover := approver.InitService(appLog, serviceGas)
+	servicePricing, err := pricing.NewService(appLog, conf.Pricing)
+	require.NoError(t, err)
+	serviceHeads := heads.NewService(appLog, conf.HeadPollInterval)
+	serviceVerifier := verifier.NewService(appLog, conf.Verifier)
+	serviceFollower := follower.NewService(appLog, serviceHeads, conf.FollowerWindow)
+	serviceIndexer := indexer.NewService(appLog, serviceApprover, serviceFollower, repository, conf.Indexer)
+	serviceBalancer := balancer.NewService(appLog, serviceApprover, servicePricing, serviceVerifier, serviceIndexer, repository, conf.DisableMetrics, conf.Balancer)
+	serviceStreamer := streamer.NewService(appLog, serviceApprover, serviceHeads, conf.Stream.MaxSubscriptions)
+	serviceTransfers := transfers.NewService(appLog, serviceApprover, conf.Transfers)
+	serviceAllowance, err := allowance.NewService(appLog, serviceApprover, serviceBalancer, conf.Allowance)
+	require.NoError(t, err)
+	serviceENS, err := ens.NewService(appLog, conf.ENS)
+	require.NoError(t, err)
+	serviceWatchlist := watchlist.NewService(appLog, serviceApprover, serviceHeads, repository, watchlist.Config{})
+	require.NoError(t, serviceWatchlist.Start())
+	t.Cleanup(serviceWatchlist.Stop)
+	serviceIndexer.Start()
+	t.Cleanup(serviceIndexer.Stop)
+	serviceBalancer.Start()
+	t.Cleanup(serviceBalancer.Stop)
 
 	return &TestContainer{
-		Log:             appLog,
-		Conf:            conf,
-		ServiceBalancer: serviceBalancer,
+		Log:                 appLog,
+		Conf:                conf,
+		ServiceBalancer:     serviceBalancer,
+		ServiceStreamer:     serviceStreamer,
+		ServiceWatchlist:    serviceWatchlist,
+		ServiceTransfers:    serviceTransfers,
+		ServiceAllowance:    serviceAllowance,
+		ServiceENS:          serviceENS,
+		ServiceGas:          serviceGas,
+		ServiceTransactions: transactions.NewService(appLog),
 	}
 }
 
@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/fasthttp/websocket"
 	"github.com/phayes/freeport"
 	"github.com/stretchr/testify/require"
 )
@@ -28,8 +31,16 @@ func NewTestServer(t *testing.T, container *TestContainer) *TestServer {
 	appHTTPServer := routes.InitAppRouter(
 		container.Log,
 		container.ServiceBalancer,
+		container.ServiceStreamer,
+		container.ServiceWatchlist,
+		container.ServiceTransfers,
+		container.ServiceAllowance,
+		container.ServiceENS,
+		container.ServiceGas,
+		container.ServiceTransactions,
 		fmt.Sprintf(":%d", srv.appPort),
 		container.Conf.DisableMetrics,
+		container.Conf.Addresses.Lenient,
 	)
 	t.Cleanup(func() {
 		require.NoError(t, appHTTPServer.Stop())
@@ -37,6 +48,13 @@ func NewTestServer(t *testing.T, container *TestContainer) *TestServer {
 	go func() {
 		require.NoError(t, appHTTPServer.Run())
 	}()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%d", srv.appPort))
+		if err != nil {
+			return false
+		}
+		return conn.Close() == nil
+	}, 5*time.Second, 10*time.Millisecond, "server is not started")
 	return srv
 }
 
@@ -81,3 +99,25 @@ func (ts *TestServer) Get(t *testing.T, path string) *TestResponse {
 	t.Helper()
 	return ts.Request(t, http.MethodGet, path, nil, nil)
 }
+
+func (ts *TestServer) Post(t *testing.T, path string, body interface{}) *TestResponse {
+	t.Helper()
+	return ts.Request(t, http.MethodPost, path, body, nil)
+}
+
+func (ts *TestServer) Delete(t *testing.T, path string) *TestResponse {
+	t.Helper()
+	return ts.Request(t, http.MethodDelete, path, nil, nil)
+}
+
+func (ts *TestServer) Dial(t *testing.T, path string) *websocket.Conn {
+	t.Helper()
+	u := fmt.Sprintf("ws://localhost:%d%s", ts.appPort, path)
+	conn, res, err := websocket.DefaultDialer.Dial(u, nil)
+	require.NoError(t, err, "failed to dial %s: %s", u, err)
+	t.Cleanup(func() {
+		require.NoError(t, res.Body.Close())
+		require.NoError(t, conn.Close())
+	})
+	return conn
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddressReason is machine-readable reason of rejected address
+type AddressReason string
+
+const (
+	AddressReasonEmpty            AddressReason = "empty"
+	AddressReasonMissingPrefix    AddressReason = "missing_prefix"
+	AddressReasonInvalidLength    AddressReason = "invalid_length"
+	AddressReasonInvalidCharacter AddressReason = "invalid_character"
+	AddressReasonMissingChecksum  AddressReason = "missing_checksum"
+	AddressReasonInvalidChecksum  AddressReason = "invalid_checksum"
+	AddressReasonZeroAddress      AddressReason = "zero_address"
+)
+
+const maxAddressErrorInput = 64
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+// AddressError is returned for rejected address, it matches ErrInvalidAddress
+type AddressError struct {
+	Input  string
+	Reason AddressReason
+}
+
+func (e *AddressError) Error() string {
+	input := e.Input
+	if len(input) > maxAddressErrorInput {
+		input = input[:maxAddressErrorInput] + "..."
+	}
+	return fmt.Sprintf("invalid address %q: %s", input, e.Reason)
+}
+
+func (e *AddressError) Unwrap() error {
+	return ErrInvalidAddress
+}
+
+// ParseAddress parses 0x-prefixed hex address. Mixed case address must have valid EIP-55 checksum.
+// All lowercase or all uppercase address has no checksum and is accepted only when lenient is set.
+func ParseAddress(src string, lenient bool) (common.Address, error) {
+	if src == "" {
+		return common.Address{}, &AddressError{Input: src, Reason: AddressReasonEmpty}
+	}
+	hasPrefix := strings.HasPrefix(src, "0x") || strings.HasPrefix(src, "0X")
+	digits := src
+	if hasPrefix {
+		digits = src[2:]
+	}
+	// non-hex input is reported as such even without prefix
+	for _, c := range digits {
+		if !isHexCharacter(c) {
+			return common.Address{}, &AddressError{Input: src, Reason: AddressReasonInvalidCharacter}
+		}
+	}
+	if !hasPrefix {
+		return common.Address{}, &AddressError{Input: src, Reason: AddressReasonMissingPrefix}
+	}
+	if len(digits) != 2*common.AddressLength {
+		return common.Address{}, &AddressError{Input: src, Reason: AddressReasonInvalidLength}
+	}
+	address := common.HexToAddress(digits)
+	checksummed := address.Hex()[2:]
+	switch {
+	case digits == checksummed:
+		return address, nil
+	case digits == strings.ToLower(digits) || digits == strings.ToUpper(digits):
+		if lenient {
+			return address, nil
+		}
+		return common.Address{}, &AddressError{Input: src, Reason: AddressReasonMissingChecksum}
+	}
+	return common.Address{}, &AddressError{Input: src, Reason: AddressReasonInvalidChecksum}
+}
+
+func isHexCharacter(c rune) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
@@ -0,0 +1,62 @@
+package utils_test
+
+import (
+	"altt/internal/utils"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	checksummed := "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"
+	expected := common.HexToAddress(checksummed)
+
+	t.Run("valid", func(t *testing.T) {
+		for _, lenient := range []bool{false, true} {
+			for _, src := range []string{checksummed, "0x0000000000000000000000000000000000000000"} {
+				address, err := utils.ParseAddress(src, lenient)
+				require.NoError(t, err, src)
+				require.Equal(t, common.HexToAddress(src), address)
+			}
+		}
+	})
+
+	t.Run("single case requires lenient mode", func(t *testing.T) {
+		for _, src := range []string{"0xd8da6bf26964af9d7eed9e03e53415d37aa96045", "0xD8DA6BF26964AF9D7EED9E03E53415D37AA96045"} {
+			_, err := utils.ParseAddress(src, false)
+			requireReason(t, err, utils.AddressReasonMissingChecksum)
+
+			address, err := utils.ParseAddress(src, true)
+			require.NoError(t, err)
+			require.Equal(t, expected, address)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		table := map[string]utils.AddressReason{
+			"":     utils.AddressReasonEmpty,
+			"asda": utils.AddressReasonInvalidCharacter,
+			"d8dA6BF26964aF9D7eEd9e03E53415D37aA96045": utils.AddressReasonMissingPrefix,
+			"0xasda": utils.AddressReasonInvalidCharacter,
+			"0xd8dA6BF26964aF9D7eEd9e03E53415D37aA9604":   utils.AddressReasonInvalidLength,
+			"0xd8dA6BF26964aF9D7eEd9e03E53415D37aA960450": utils.AddressReasonInvalidLength,
+			"0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045 ": utils.AddressReasonInvalidCharacter,
+			"0xD8dA6BF26964aF9D7eEd9e03E53415D37aA96045":  utils.AddressReasonInvalidChecksum,
+		}
+		for src, reason := range table {
+			for _, lenient := range []bool{false, true} {
+				_, err := utils.ParseAddress(src, lenient)
+				requireReason(t, err, reason)
+			}
+		}
+	})
+}
+
+func requireReason(t *testing.T, err error, reason utils.AddressReason) {
+	t.Helper()
+	require.ErrorIs(t, err, utils.ErrInvalidAddress)
+	var addressErr *utils.AddressError
+	require.ErrorAs(t, err, &addressErr)
+	require.Equal(t, reason, addressErr.Reason)
+}
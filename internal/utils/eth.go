@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/shopspring/decimal"
 )
@@ -41,3 +43,9 @@ func CustomToWei(amount float64, decimals int) *big.Int {
 	mul := decimal.NewFromFloat(float64(10)).Pow(decimal.NewFromFloat(float64(decimals)))
 	return amountDecimal.Mul(mul).BigInt()
 }
+
+// ResolveTokenURI substitutes `{id}` placeholder of ERC-1155 metadata uri.
+// Per standard id is lowercase hex, zero-padded to 64 characters, without 0x prefix.
+func ResolveTokenURI(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
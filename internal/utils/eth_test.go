@@ -5,15 +5,16 @@ import (
 	"math/big"
 	"testing"
 
-	"github.com/ethereum/go-ethereum/common"
-
 	"github.com/stretchr/testify/require"
 )
 
 func TestCustomFromWei2(t *testing.T) {
 	a := "asda"
-	ad := common.HexToAddress(a)
-	t.Log(ad.String())
+	_, err := utils.ParseAddress(a, true)
+	require.ErrorIs(t, err, utils.ErrInvalidAddress)
+	var addressErr *utils.AddressError
+	require.ErrorAs(t, err, &addressErr)
+	require.Equal(t, utils.AddressReasonInvalidCharacter, addressErr.Reason)
 }
 
 func TestWeiFromETHString(t *testing.T) {
@@ -91,3 +92,14 @@ func TestCustomToWei(t *testing.T) {
 		require.Equal(t, a, res)
 	}
 }
+
+func TestResolveTokenURI(t *testing.T) {
+	table := map[string]string{
+		"https://token-cdn-domain/{id}.json": "https://token-cdn-domain/000000000000000000000000000000000000000000000000000000000004cce0.json",
+		"ipfs://QmHash/314592.json":          "ipfs://QmHash/314592.json",
+	}
+	for uri, expected := range table {
+		res := utils.ResolveTokenURI(uri, big.NewInt(314592))
+		require.Equal(t, expected, res)
+	}
+}